@@ -1,12 +1,45 @@
 package main
 import (
+		"context"
+		"flag"
+		"fmt"
 		"go-api/pkg/api"
+		"go-api/pkg/api/audit"
+		"go-api/pkg/attendance"
+		"go-api/pkg/events"
+		"go-api/pkg/models"
+		"go-api/pkg/mockDatabase"
+		"go-api/pkg/notifications"
+		"go-api/pkg/retention"
+		"go-api/pkg/scheduler"
 		"log"
+		"os"
+		"time"
 )
 
 func main(){
 
-	router := api.InitRouter()
+	configPath := flag.String("config", "", "path to a JSON configuration file")
+	flag.Parse()
+
+	config, err := api.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	router := api.NewRouter(config)
+	go events.Default.Run(context.Background(), time.Second)
+	noShows := func(tx *database.Tx, before models.Booking, after models.Booking) error {
+		return audit.System(tx, events.BookingNoShow, after.ID, before, after)
+	}
+	go attendance.Run(context.Background(), time.Minute, noShows)
+	go retention.Run(context.Background(), config.Retention, audit.System)
+
+	host, _ := os.Hostname()
+	jobs := scheduler.New(config.Jobs, fmt.Sprintf("%s-%d", host, os.Getpid()))
+	jobs.Handle(notifications.ReminderJob, notifications.Remind)
+	go jobs.Run(context.Background(), 10*time.Second)
+
 	if err := router.Run(":8080"); err != nil {
 		log.Fatal(err)
 	}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+)
+
+func (a *app) audit(ctx context.Context, command string, args []string) error {
+	switch command {
+	case "list":
+		flags := flag.NewFlagSet("audit list", flag.ContinueOnError)
+		options := filterFlags(flags, "actor", "action", "resource", "resource_id", "request_id", "from", "to")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		entries, err := a.client.ListAudit(ctx, options()...)
+		if err != nil {
+			return err
+		}
+		return a.print(entries)
+
+	case "verify":
+		verification, err := a.client.VerifyAudit(ctx)
+		if err != nil {
+			return err
+		}
+		return a.print(verification)
+	}
+	return fmt.Errorf("unknown audit command %q", command)
+}
\ No newline at end of file
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"go-api/pkg/client"
+	"go-api/pkg/models"
+)
+
+func (a *app) bookings(ctx context.Context, command string, args []string) error {
+	switch command {
+	case "list":
+		flags := flag.NewFlagSet("bookings list", flag.ContinueOnError)
+		options := filterFlags(flags, "class_id", "studio_id", "name", "status", "from", "to", "include_deleted", "tz")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		bookings, err := a.client.ListBookings(ctx, options()...)
+		if err != nil {
+			return err
+		}
+		return a.print(bookings)
+
+	case "get":
+		id, rest, err := idArg(args)
+		if err != nil {
+			return err
+		}
+		flags := flag.NewFlagSet("bookings get", flag.ContinueOnError)
+		tz := flags.String("tz", "", `render dates in "studio" or an IANA zone`)
+		if err := flags.Parse(rest); err != nil {
+			return err
+		}
+		booking, err := a.client.GetBooking(ctx, id, timeZone(*tz)...)
+		if err != nil {
+			return err
+		}
+		return a.print(booking)
+
+	case "create":
+		var newBooking models.CreateBooking
+		flags := bookingFlags("bookings create", &newBooking.Name, &newBooking.ClassId)
+		date := flags.String("date", "", "booking date, RFC 3339")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		if err := parseDate(*date, &newBooking.Date); err != nil {
+			return err
+		}
+		booking, err := a.client.CreateBooking(ctx, newBooking)
+		if err != nil {
+			return err
+		}
+		return a.print(booking)
+
+	case "update":
+		id, rest, err := idArg(args)
+		if err != nil {
+			return err
+		}
+
+		var response client.Response
+		current, err := a.client.GetBooking(ctx, id, client.CaptureResponse(&response))
+		if err != nil {
+			return err
+		}
+		updated := models.UpdateBooking{Name: current.Name, ClassId: current.ClassId, Date: current.Date}
+		flags := bookingFlags("bookings update", &updated.Name, &updated.ClassId)
+		date := flags.String("date", "", "booking date, RFC 3339")
+		if err := flags.Parse(rest); err != nil {
+			return err
+		}
+		if err := parseDate(*date, &updated.Date); err != nil {
+			return err
+		}
+		booking, err := a.client.UpdateBooking(ctx, id, updated, client.IfMatch(response.ETag))
+		if err != nil {
+			return err
+		}
+		return a.print(booking)
+
+	case "confirm", "cancel", "attend", "no-show", "restore":
+		id, _, err := idArg(args)
+		if err != nil {
+			return err
+		}
+		transitions := map[string]func(context.Context, int, ...client.RequestOption) (*models.Booking, error){
+			"confirm": a.client.ConfirmBooking,
+			"cancel":  a.client.CancelBooking,
+			"attend":  a.client.AttendBooking,
+			"no-show": a.client.MarkNoShow,
+			"restore": a.client.RestoreBooking,
+		}
+		booking, err := transitions[command](ctx, id)
+		if err != nil {
+			return err
+		}
+		return a.print(booking)
+
+	case "checkin-code":
+		id, rest, err := idArg(args)
+		if err != nil {
+			return err
+		}
+		flags := flag.NewFlagSet("bookings checkin-code", flag.ContinueOnError)
+		format := flags.String("format", "", "png or svg for a QR code, the token otherwise")
+		path := flags.String("file", "", "write the QR code to this file instead of stdout")
+		if err := flags.Parse(rest); err != nil {
+			return err
+		}
+		if *format == "png" || *format == "svg" {
+			return a.download(*path, func(w io.Writer) error {
+				return a.client.GetCheckInQRCode(ctx, id, *format, w)
+			})
+		}
+		code, err := a.client.GetCheckInCode(ctx, id)
+		if err != nil {
+			return err
+		}
+		return a.print(code)
+
+	case "kiosk-check-in":
+		if len(args) == 0 {
+			return fmt.Errorf("missing <token>")
+		}
+		booking, err := a.client.KioskCheckIn(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		return a.print(booking)
+
+	case "export":
+		return a.exportBookings(ctx, args)
+	}
+	return fmt.Errorf("unknown bookings command %q", command)
+}
+
+/**
+ * @brief exportBookings downloads the bookings from GET /api/bookings/export as CSV or XLSX.
+ *
+ * Any other -format renders the filtered list in that output format instead.
+ */
+func (a *app) exportBookings(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("bookings export", flag.ContinueOnError)
+	format := flags.String("format", "csv", "csv, xlsx, or any output format")
+	path := flags.String("file", "", "write to this file instead of stdout")
+	options := filterFlags(flags, "class_id", "studio_id", "name", "status", "from", "to", "tz")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	return a.download(*path, func(w io.Writer) error {
+		if *format == "csv" || *format == "xlsx" {
+			return a.client.ExportBookings(ctx, *format, w, options()...)
+		}
+
+		bookings, err := a.client.ListBookings(ctx, options()...)
+		if err != nil {
+			return err
+		}
+		return render(w, *format, bookings)
+	})
+}
+
+func bookingFlags(name string, memberName *string, classId *int) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.StringVar(memberName, "name", *memberName, "member name")
+	flags.IntVar(classId, "class", *classId, "class ID")
+	return flags
+}
+
+func parseDate(value string, date *time.Time) error {
+	if value == "" {
+		return nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return fmt.Errorf("-date: %v", err)
+	}
+	*date = parsed
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,323 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-api/pkg/client"
+	"go-api/pkg/models"
+)
+
+func (a *app) classes(ctx context.Context, command string, args []string) error {
+	switch command {
+	case "list":
+		flags := flag.NewFlagSet("classes list", flag.ContinueOnError)
+		options := filterFlags(flags, "studio_id", "name", "from", "to", "include_deleted", "tz")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		classes, err := a.client.ListClasses(ctx, options()...)
+		if err != nil {
+			return err
+		}
+		return a.print(classes)
+
+	case "get":
+		id, rest, err := idArg(args)
+		if err != nil {
+			return err
+		}
+		flags := flag.NewFlagSet("classes get", flag.ContinueOnError)
+		tz := flags.String("tz", "", `render dates in "studio" or an IANA zone`)
+		if err := flags.Parse(rest); err != nil {
+			return err
+		}
+		class, err := a.client.GetClass(ctx, id, timeZone(*tz)...)
+		if err != nil {
+			return err
+		}
+		return a.print(class)
+
+	case "create":
+		var newClass models.CreateClass
+		flags := classFlags("classes create", &newClass.Name, &newClass.StudioId, &newClass.Capacity, &newClass.Price, &newClass.LocalStartDate, &newClass.LocalEndDate)
+		start := flags.String("start", "", "start date, RFC 3339")
+		end := flags.String("end", "", "end date, RFC 3339")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		if err := parseDates(*start, *end, &newClass.StartDate, &newClass.EndDate); err != nil {
+			return err
+		}
+		class, err := a.client.CreateClass(ctx, newClass)
+		if err != nil {
+			return err
+		}
+		return a.print(class)
+
+	case "update":
+		id, rest, err := idArg(args)
+		if err != nil {
+			return err
+		}
+
+		// Start from the current class so that only the given flags change, and
+		// refuse to overwrite a concurrent change.
+		var response client.Response
+		current, err := a.client.GetClass(ctx, id, client.CaptureResponse(&response))
+		if err != nil {
+			return err
+		}
+		updated := models.UpdateClass{
+			Name:      current.Name,
+			StudioId:  current.StudioId,
+			StartDate: current.StartDate,
+			EndDate:   current.EndDate,
+			Capacity:  current.Capacity,
+			Price:     current.Price,
+		}
+		flags := classFlags("classes update", &updated.Name, &updated.StudioId, &updated.Capacity, &updated.Price, &updated.LocalStartDate, &updated.LocalEndDate)
+		start := flags.String("start", "", "start date, RFC 3339")
+		end := flags.String("end", "", "end date, RFC 3339")
+		if err := flags.Parse(rest); err != nil {
+			return err
+		}
+		if err := parseDates(*start, *end, &updated.StartDate, &updated.EndDate); err != nil {
+			return err
+		}
+		class, err := a.client.UpdateClass(ctx, id, updated, client.IfMatch(response.ETag))
+		if err != nil {
+			return err
+		}
+		return a.print(class)
+
+	case "delete":
+		id, _, err := idArg(args)
+		if err != nil {
+			return err
+		}
+		if err := a.client.DeleteClass(ctx, id); err != nil {
+			return err
+		}
+		fmt.Fprintf(a.stdout, "class %d deleted\n", id)
+		return nil
+
+	case "restore":
+		id, _, err := idArg(args)
+		if err != nil {
+			return err
+		}
+		class, err := a.client.RestoreClass(ctx, id)
+		if err != nil {
+			return err
+		}
+		return a.print(class)
+
+	case "import":
+		return a.importClasses(ctx, args)
+
+	case "roster":
+		id, rest, err := idArg(args)
+		if err != nil {
+			return err
+		}
+		flags := flag.NewFlagSet("classes roster", flag.ContinueOnError)
+		format := flags.String("format", "csv", "csv or xlsx")
+		path := flags.String("file", "", "write to this file instead of stdout")
+		options := filterFlags(flags, "name", "status", "from", "to", "tz")
+		if err := flags.Parse(rest); err != nil {
+			return err
+		}
+		return a.download(*path, func(w io.Writer) error {
+			return a.client.GetClassRoster(ctx, id, *format, w, options()...)
+		})
+
+	case "check-in":
+		id, rest, err := idArg(args)
+		if err != nil {
+			return err
+		}
+		var checkIn models.CheckIn
+		flags := flag.NewFlagSet("classes check-in", flag.ContinueOnError)
+		flags.IntVar(&checkIn.MemberId, "member", 0, "member ID")
+		flags.IntVar(&checkIn.BookingId, "booking", 0, "booking ID")
+		if err := flags.Parse(rest); err != nil {
+			return err
+		}
+		booking, err := a.client.CheckIn(ctx, id, checkIn)
+		if err != nil {
+			return err
+		}
+		return a.print(booking)
+
+	case "roster-check-in":
+		id, rest, err := idArg(args)
+		if err != nil {
+			return err
+		}
+		flags := flag.NewFlagSet("classes roster-check-in", flag.ContinueOnError)
+		members := flags.String("members", "", "comma-separated member IDs")
+		bookings := flags.String("bookings", "", "comma-separated booking IDs")
+		if err := flags.Parse(rest); err != nil {
+			return err
+		}
+		memberIds, err := idList(*members)
+		if err != nil {
+			return err
+		}
+		bookingIds, err := idList(*bookings)
+		if err != nil {
+			return err
+		}
+		var request models.BulkCheckIn
+		for _, memberId := range memberIds {
+			request.CheckIns = append(request.CheckIns, models.CheckIn{MemberId: memberId})
+		}
+		for _, bookingId := range bookingIds {
+			request.CheckIns = append(request.CheckIns, models.CheckIn{BookingId: bookingId})
+		}
+		report, err := a.client.BulkCheckIn(ctx, id, request)
+		if err != nil {
+			return err
+		}
+		return a.print(report)
+	}
+	return fmt.Errorf("unknown classes command %q", command)
+}
+
+/**
+ * @brief importClasses uploads a CSV, JSON or iCalendar file of classes to POST /api/classes/import.
+ *
+ * CSV files need a header naming the CreateClass JSON fields, e.g.
+ * name,start_date,end_date,capacity,studio_id. JSON files hold an array of
+ * CreateClass objects. iCalendar (.ics) files need -capacity. The server validates every row; -dry-run only prints
+ * the report. The request carries an Idempotency-Key derived from the file
+ * content, so re-running an interrupted import does not create duplicates.
+ */
+func (a *app) importClasses(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("classes import", flag.ContinueOnError)
+	format := flags.String("format", "", "csv, json or ics (default from the file extension)")
+	mode := flags.String("mode", "atomic", "atomic or best_effort")
+	dryRun := flags.Bool("dry-run", false, "only validate the rows")
+	capacity := flags.String("capacity", "", "capacity of the classes of an ics file")
+	studio := flags.String("studio", "", "studio ID of the classes of an ics file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: studioctl classes import [-format csv|json|ics] [-mode atomic|best_effort] [-dry-run] [-capacity n] [-studio id] <file>")
+	}
+
+	path := flags.Arg(0)
+	if *format == "" {
+		*format = strings.TrimPrefix(filepath.Ext(path), ".")
+	}
+
+	contentType := map[string]string{"csv": "text/csv", "json": "application/json", "ics": "text/calendar"}[*format]
+	if contentType == "" {
+		return fmt.Errorf("unknown import format %q", *format)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	options := []client.RequestOption{client.Query("mode", *mode)}
+	if *capacity != "" {
+		options = append(options, client.Query("capacity", *capacity))
+	}
+	if *studio != "" {
+		options = append(options, client.Query("studio_id", *studio))
+	}
+	if *dryRun {
+		options = append(options, client.Query("dry_run", "true"))
+	} else {
+		sum := sha256.Sum256(data)
+		options = append(options, client.IdempotencyKey(fmt.Sprintf("studioctl-import-%s-%s-%s-%x", *mode, *capacity, *studio, sum[:8])))
+	}
+
+	report, importErr := a.client.ImportClasses(ctx, contentType, bytes.NewReader(data), options...)
+	if report == nil {
+		return importErr
+	}
+
+	if err := a.printImportReport(report); err != nil {
+		return err
+	}
+	if importErr != nil {
+		return importErr
+	}
+	if report.Invalid > 0 {
+		return fmt.Errorf("%d of %d rows are invalid", report.Invalid, report.Total)
+	}
+	return nil
+}
+
+// importRow is the table view of a models.ClassImportRow.
+type importRow struct {
+	Row      int    `json:"row"`
+	Status   string `json:"status"`
+	ClassId  string `json:"class_id"`
+	UID      string `json:"uid"`
+	Error    string `json:"error"`
+	Warnings string `json:"warnings"`
+}
+
+func (a *app) printImportReport(report *models.ClassImportReport) error {
+	if a.output != "table" {
+		return a.print(report)
+	}
+
+	rows := make([]importRow, 0, len(report.Rows))
+	for _, row := range report.Rows {
+		view := importRow{Row: row.Row, Status: row.Status, UID: row.UID, Error: row.Error, Warnings: strings.Join(row.Warnings, "; ")}
+		if row.Class != nil {
+			view.ClassId = strconv.Itoa(row.Class.ID)
+		}
+		rows = append(rows, view)
+	}
+	return a.print(rows)
+}
+
+func classFlags(name string, className *string, studioId *int, capacity *int, price *int, localStart *string, localEnd *string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.StringVar(className, "name", *className, "class name")
+	flags.IntVar(studioId, "studio", *studioId, "studio ID")
+	flags.IntVar(capacity, "capacity", *capacity, "capacity")
+	flags.IntVar(price, "price", *price, "drop-in price in cents, 0 for free")
+	flags.StringVar(localStart, "local-start", "", "start as studio wall-clock time, 2006-01-02T15:04:05")
+	flags.StringVar(localEnd, "local-end", "", "end as studio wall-clock time, 2006-01-02T15:04:05")
+	return flags
+}
+
+func parseDates(start string, end string, startDate *time.Time, endDate *time.Time) error {
+	var err error
+	if start != "" {
+		if *startDate, err = time.Parse(time.RFC3339, start); err != nil {
+			return fmt.Errorf("-start: %v", err)
+		}
+	}
+	if end != "" {
+		if *endDate, err = time.Parse(time.RFC3339, end); err != nil {
+			return fmt.Errorf("-end: %v", err)
+		}
+	}
+	return nil
+}
+
+func timeZone(tz string) []client.RequestOption {
+	if tz == "" {
+		return nil
+	}
+	return []client.RequestOption{client.TimeZone(tz)}
+}
\ No newline at end of file
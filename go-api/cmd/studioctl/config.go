@@ -0,0 +1,51 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is read from ~/.studioctl.yaml, or the file named by -config or STUDIOCTL_CONFIG.
+type Config struct {
+	Server string `yaml:"server"`
+	APIKey string `yaml:"api_key"`
+	Output string `yaml:"output"`
+}
+
+/**
+ * @brief loadConfig reads the configuration file; a missing default file is not an error.
+ *
+ * @param path string: The file given with -config, "" for the default location.
+ */
+func loadConfig(path string) (Config, error) {
+	config := Config{Server: "http://localhost:8080", Output: "table"}
+
+	explicit := path != ""
+	if !explicit {
+		path = os.Getenv("STUDIOCTL_CONFIG")
+		explicit = path != ""
+	}
+	if !explicit {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return config, nil
+		}
+		path = filepath.Join(home, ".studioctl.yaml")
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !explicit {
+		return config, nil
+	}
+	if err != nil {
+		return config, err
+	}
+
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return config, err
+	}
+	return config, nil
+}
\ No newline at end of file
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-api/pkg/client"
+)
+
+const usage = `Usage: studioctl [global flags] <resource> <command> [flags]
+
+Resources and commands:
+  classes   list | get <id> | create | update <id> | delete <id> | restore <id> | import <file> | roster <id> | check-in <id> | roster-check-in <id>
+  bookings  list | get <id> | create | update <id> | confirm <id> | cancel <id> | attend <id> | no-show <id> | restore <id> | checkin-code <id> | kiosk-check-in <token> | export
+  studios   list | get <id> | create | schedule <id> | templates <id> | set-template <id> | reset-template <id>
+  members   list | get <id> | create | attendance <id> | credits <id> | buy <id> | feed <id>
+  plans     list | create
+  webhooks  list | create | delete <id> | dead-letters | redeliver <delivery id>
+  audit     list | verify
+
+Global flags:
+`
+
+// app holds what every command needs.
+type app struct {
+	client *client.Client
+	output string
+	stdout io.Writer
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil && !errors.Is(err, flag.ErrHelp) {
+		fmt.Fprintln(os.Stderr, "studioctl:", err)
+		os.Exit(1)
+	}
+}
+
+/**
+ * @brief run parses the global flags and dispatches to the resource command.
+ *
+ * @param args []string: The command-line arguments, without the program name.
+ * @param stdout io.Writer: Where results are written.
+ */
+func run(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("studioctl", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	configPath := flags.String("config", "", "configuration file (default ~/.studioctl.yaml)")
+	server := flags.String("server", "", "API server URL, overrides the configuration file")
+	apiKey := flags.String("api-key", "", "API key, overrides the configuration file")
+	output := flags.String("o", "", "output format: table, json or yaml")
+	timeout := flags.Duration("timeout", 30*time.Second, "timeout of each command")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	config, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	if *server != "" {
+		config.Server = *server
+	}
+	if *apiKey != "" {
+		config.APIKey = *apiKey
+	}
+	if *output != "" {
+		config.Output = *output
+	}
+
+	if flags.NArg() < 2 {
+		flags.Usage()
+		return fmt.Errorf("missing resource or command")
+	}
+
+	a := &app{
+		client: client.New(config.Server, client.WithAPIKey(config.APIKey)),
+		output: config.Output,
+		stdout: stdout,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	resource, command, rest := flags.Arg(0), flags.Arg(1), flags.Args()[2:]
+	switch resource {
+	case "classes":
+		return a.classes(ctx, command, rest)
+	case "bookings":
+		return a.bookings(ctx, command, rest)
+	case "studios":
+		return a.studios(ctx, command, rest)
+	case "members":
+		return a.members(ctx, command, rest)
+	case "plans":
+		return a.plans(ctx, command, rest)
+	case "webhooks":
+		return a.webhooks(ctx, command, rest)
+	case "audit":
+		return a.audit(ctx, command, rest)
+	}
+	return fmt.Errorf("unknown resource %q", resource)
+}
+
+func (a *app) print(value interface{}) error {
+	return render(a.stdout, a.output, value)
+}
+
+// idArg parses the <id> argument of get, update and delete.
+func idArg(args []string) (int, []string, error) {
+	if len(args) == 0 {
+		return 0, nil, fmt.Errorf("missing <id>")
+	}
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid id %q", args[0])
+	}
+	return id, args[1:], nil
+}
+
+// idList parses a comma-separated list of IDs, empty for "".
+func idList(value string) ([]int, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var ids []int
+	for _, item := range strings.Split(value, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(item))
+		if err != nil {
+			return nil, fmt.Errorf("invalid id %q", item)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+/**
+ * @brief download runs write against the file at path, or stdout when path is empty.
+ */
+func (a *app) download(path string, write func(w io.Writer) error) error {
+	if path == "" {
+		return write(a.stdout)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+/**
+ * @brief filterFlags registers the given list filters on flags.
+ *
+ * @param names ...string: Query parameter names, e.g. "class_id", "from".
+ * @return func() []client.RequestOption: The query options, to call after flags.Parse.
+ */
+func filterFlags(flags *flag.FlagSet, names ...string) func() []client.RequestOption {
+	usages := map[string][2]string{
+		"class_id":  {"class", "only this class ID"},
+		"studio_id": {"studio", "only this studio ID"},
+		"name":      {"name", "only this name"},
+		"status":    {"status", "only this status, confirmed or cancelled"},
+		"from":      {"from", "only dated at or after this RFC 3339 time"},
+		"to":        {"to", "only dated before this RFC 3339 time"},
+		"tz":        {"tz", `render dates in "studio" or an IANA zone`},
+
+		"actor":       {"actor", "only changes by this user"},
+		"action":      {"action", "only this action, e.g. class.updated"},
+		"resource":    {"resource", "only this resource, class or booking"},
+		"resource_id": {"id", "only this resource ID"},
+		"request_id":  {"request", "only this request ID"},
+
+		"include_deleted": {"include-deleted", "true to also list soft-deleted items (admin)"},
+	}
+
+	values := map[string]*string{}
+	for _, name := range names {
+		values[name] = flags.String(usages[name][0], "", usages[name][1])
+	}
+
+	return func() []client.RequestOption {
+		var options []client.RequestOption
+		for _, name := range names {
+			if *values[name] != "" {
+				options = append(options, client.Query(name, *values[name]))
+			}
+		}
+		return options
+	}
+}
\ No newline at end of file
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"go-api/pkg/models"
+)
+
+func (a *app) members(ctx context.Context, command string, args []string) error {
+	switch command {
+	case "list":
+		members, err := a.client.ListMembers(ctx)
+		if err != nil {
+			return err
+		}
+		return a.print(members)
+
+	case "get":
+		id, _, err := idArg(args)
+		if err != nil {
+			return err
+		}
+		member, err := a.client.GetMember(ctx, id)
+		if err != nil {
+			return err
+		}
+		return a.print(member)
+
+	case "create":
+		var newMember models.CreateMember
+		flags := flag.NewFlagSet("members create", flag.ContinueOnError)
+		flags.StringVar(&newMember.Name, "name", "", "member name, as used in bookings")
+		flags.StringVar(&newMember.Email, "email", "", "address booking notifications are sent to")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		member, err := a.client.CreateMember(ctx, newMember)
+		if err != nil {
+			return err
+		}
+		return a.print(member)
+
+	case "attendance":
+		id, _, err := idArg(args)
+		if err != nil {
+			return err
+		}
+		attendance, err := a.client.GetMemberAttendance(ctx, id)
+		if err != nil {
+			return err
+		}
+		return a.print(attendance)
+
+	case "credits":
+		id, _, err := idArg(args)
+		if err != nil {
+			return err
+		}
+		credits, err := a.client.GetMemberCredits(ctx, id)
+		if err != nil {
+			return err
+		}
+		return a.print(credits)
+
+	case "buy":
+		id, rest, err := idArg(args)
+		if err != nil {
+			return err
+		}
+		flags := flag.NewFlagSet("members buy", flag.ContinueOnError)
+		planId := flags.Int("plan", 0, "plan ID, see plans list")
+		if err := flags.Parse(rest); err != nil {
+			return err
+		}
+		credits, err := a.client.PurchasePlan(ctx, id, *planId)
+		if err != nil {
+			return err
+		}
+		return a.print(credits)
+
+	case "feed":
+		id, _, err := idArg(args)
+		if err != nil {
+			return err
+		}
+		feed, err := a.client.CreateMemberFeed(ctx, id)
+		if err != nil {
+			return err
+		}
+		return a.print(feed)
+	}
+	return fmt.Errorf("unknown members command %q", command)
+}
\ No newline at end of file
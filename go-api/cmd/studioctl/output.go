@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+/**
+ * @brief render writes a model or a slice of models as a table, JSON or YAML.
+ *
+ * @param w io.Writer: Where to write.
+ * @param format string: "table", "json" or "yaml".
+ * @param value interface{}: A struct, a pointer to one or a slice of them.
+ */
+func render(w io.Writer, format string, value interface{}) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	case "yaml":
+		// Round-trip through JSON so that YAML keys match the API field names.
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		var generic interface{}
+		if err := json.Unmarshal(data, &generic); err != nil {
+			return err
+		}
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		return encoder.Encode(generic)
+	case "table":
+		return renderTable(w, value)
+	}
+	return fmt.Errorf("unknown output format %q", format)
+}
+
+func renderTable(w io.Writer, value interface{}) error {
+	rows := reflect.ValueOf(value)
+	for rows.Kind() == reflect.Ptr {
+		rows = rows.Elem()
+	}
+	if rows.Kind() == reflect.Struct {
+		single := reflect.MakeSlice(reflect.SliceOf(rows.Type()), 1, 1)
+		single.Index(0).Set(rows)
+		rows = single
+	}
+	if rows.Kind() != reflect.Slice {
+		return fmt.Errorf("cannot render %T as a table", value)
+	}
+
+	table := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	itemType := rows.Type().Elem()
+
+	var headers []string
+	var fields []int
+	for i := 0; i < itemType.NumField(); i++ {
+		name := strings.Split(itemType.Field(i).Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		headers = append(headers, strings.ToUpper(name))
+		fields = append(fields, i)
+	}
+	fmt.Fprintln(table, strings.Join(headers, "\t"))
+
+	for row := 0; row < rows.Len(); row++ {
+		cells := make([]string, 0, len(fields))
+		for _, field := range fields {
+			cells = append(cells, cell(rows.Index(row).Field(field).Interface()))
+		}
+		fmt.Fprintln(table, strings.Join(cells, "\t"))
+	}
+
+	return table.Flush()
+}
+
+func cell(value interface{}) string {
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.Format(time.RFC3339)
+	}
+	return fmt.Sprint(value)
+}
\ No newline at end of file
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"go-api/pkg/models"
+)
+
+func (a *app) plans(ctx context.Context, command string, args []string) error {
+	switch command {
+	case "list":
+		plans, err := a.client.ListPlans(ctx)
+		if err != nil {
+			return err
+		}
+		return a.print(plans)
+
+	case "create":
+		var newPlan models.CreatePlan
+		flags := flag.NewFlagSet("plans create", flag.ContinueOnError)
+		flags.StringVar(&newPlan.Name, "name", "", "plan name")
+		flags.StringVar(&newPlan.Kind, "kind", models.PlanPack, "pack or membership")
+		flags.IntVar(&newPlan.Credits, "credits", 0, "classes in a pack")
+		flags.IntVar(&newPlan.Days, "days", 0, "days a membership lasts")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		plan, err := a.client.CreatePlan(ctx, newPlan)
+		if err != nil {
+			return err
+		}
+		return a.print(plan)
+	}
+	return fmt.Errorf("unknown plans command %q", command)
+}
\ No newline at end of file
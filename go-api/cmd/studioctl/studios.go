@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"go-api/pkg/models"
+)
+
+func (a *app) studios(ctx context.Context, command string, args []string) error {
+	switch command {
+	case "list":
+		studios, err := a.client.ListStudios(ctx)
+		if err != nil {
+			return err
+		}
+		return a.print(studios)
+
+	case "get":
+		id, _, err := idArg(args)
+		if err != nil {
+			return err
+		}
+		studio, err := a.client.GetStudio(ctx, id)
+		if err != nil {
+			return err
+		}
+		return a.print(studio)
+
+	case "create":
+		var newStudio models.CreateStudio
+		flags := flag.NewFlagSet("studios create", flag.ContinueOnError)
+		flags.StringVar(&newStudio.Name, "name", "", "studio name")
+		flags.StringVar(&newStudio.TimeZone, "tz", "", "IANA time zone, e.g. Europe/Madrid")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		studio, err := a.client.CreateStudio(ctx, newStudio)
+		if err != nil {
+			return err
+		}
+		return a.print(studio)
+
+	case "schedule":
+		id, rest, err := idArg(args)
+		if err != nil {
+			return err
+		}
+		flags := flag.NewFlagSet("studios schedule", flag.ContinueOnError)
+		path := flags.String("file", "", "write to this file instead of stdout")
+		if err := flags.Parse(rest); err != nil {
+			return err
+		}
+		return a.download(*path, func(w io.Writer) error {
+			return a.client.GetStudioSchedule(ctx, id, w)
+		})
+
+	case "templates":
+		id, _, err := idArg(args)
+		if err != nil {
+			return err
+		}
+		templates, err := a.client.ListEmailTemplates(ctx, id)
+		if err != nil {
+			return err
+		}
+		return a.print(templates)
+
+	case "set-template":
+		id, rest, err := idArg(args)
+		if err != nil {
+			return err
+		}
+		var update models.UpdateEmailTemplate
+		flags := flag.NewFlagSet("studios set-template", flag.ContinueOnError)
+		kind := flags.String("kind", "", "confirmation, change, cancellation, promotion or reminder")
+		flags.StringVar(&update.Subject, "subject", "", "subject template")
+		textPath := flags.String("text", "", "file with the plain text template")
+		htmlPath := flags.String("html", "", "file with the HTML template")
+		if err := flags.Parse(rest); err != nil {
+			return err
+		}
+		text, err := os.ReadFile(*textPath)
+		if err != nil {
+			return err
+		}
+		html, err := os.ReadFile(*htmlPath)
+		if err != nil {
+			return err
+		}
+		update.Text, update.HTML = string(text), string(html)
+		template, err := a.client.SetEmailTemplate(ctx, id, *kind, update)
+		if err != nil {
+			return err
+		}
+		return a.print(template)
+
+	case "reset-template":
+		id, rest, err := idArg(args)
+		if err != nil {
+			return err
+		}
+		flags := flag.NewFlagSet("studios reset-template", flag.ContinueOnError)
+		kind := flags.String("kind", "", "confirmation, change, cancellation, promotion or reminder")
+		if err := flags.Parse(rest); err != nil {
+			return err
+		}
+		template, err := a.client.ResetEmailTemplate(ctx, id, *kind)
+		if err != nil {
+			return err
+		}
+		return a.print(template)
+	}
+	return fmt.Errorf("unknown studios command %q", command)
+}
\ No newline at end of file
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"go-api/pkg/models"
+)
+
+func (a *app) webhooks(ctx context.Context, command string, args []string) error {
+	switch command {
+	case "list":
+		webhooks, err := a.client.ListWebhooks(ctx)
+		if err != nil {
+			return err
+		}
+		return a.print(webhooks)
+
+	case "create":
+		var newWebhook models.CreateWebhook
+		var events string
+		flags := flag.NewFlagSet("webhooks create", flag.ContinueOnError)
+		flags.StringVar(&newWebhook.URL, "url", "", "URL the events are posted to")
+		flags.StringVar(&events, "events", "", "comma-separated event types, e.g. booking.created,class.updated")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		if events != "" {
+			newWebhook.Events = strings.Split(events, ",")
+		}
+		webhook, err := a.client.CreateWebhook(ctx, newWebhook)
+		if err != nil {
+			return err
+		}
+		return a.print(webhook)
+
+	case "delete":
+		id, _, err := idArg(args)
+		if err != nil {
+			return err
+		}
+		if err := a.client.DeleteWebhook(ctx, id); err != nil {
+			return err
+		}
+		fmt.Fprintf(a.stdout, "webhook %d deleted\n", id)
+		return nil
+
+	case "dead-letters":
+		deliveries, err := a.client.ListDeadLetters(ctx)
+		if err != nil {
+			return err
+		}
+		return a.print(deliveries)
+
+	case "redeliver":
+		id, _, err := idArg(args)
+		if err != nil {
+			return err
+		}
+		delivery, err := a.client.RedeliverDeadLetter(ctx, id)
+		if err != nil {
+			return err
+		}
+		return a.print(delivery)
+	}
+	return fmt.Errorf("unknown webhooks command %q", command)
+}
\ No newline at end of file
@@ -0,0 +1,147 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+	"go-api/pkg/models"
+	"go-api/pkg/api/middleware"
+	"go-api/pkg/mockDatabase"
+	"github.com/gin-gonic/gin"
+)
+
+// SystemActor is the actor of the changes made by background jobs rather than requests.
+const SystemActor = "system"
+
+/**
+ * @brief Record adds the change of a class or booking made by a request to the audit log.
+ *
+ * Call it in the database.Transaction making the change, next to tx.Record, so
+ * that the entry is only written when the change is.
+ *
+ * @param c *gin.Context: The request, naming the actor and the request ID.
+ * @param tx *database.Tx: The transaction making the change.
+ * @param action string: The event type recorded with the change, e.g. "class.updated"; the part before the dot is the resource.
+ * @param id int: The ID of the class or booking.
+ * @param before interface{}: The resource before the change, nil when it is created.
+ * @param after interface{}: The resource after the change.
+ */
+func Record(c *gin.Context, tx *database.Tx, action string, id int, before interface{}, after interface{}) error {
+	entry, err := newEntry(action, id, before, after)
+	if err != nil {
+		return err
+	}
+
+	entry.Actor = "anonymous"
+	entry.RequestId = middleware.CurrentRequestID(c)
+	if principal, ok := middleware.CurrentPrincipal(c); ok {
+		entry.Actor = principal.Name
+		entry.Role = principal.Role
+	}
+
+	tx.Audit(entry)
+	return nil
+}
+
+/**
+ * @brief System adds a change made by a background job, such as a purge, to the audit log.
+ *
+ * The entry is recorded for SystemActor, without a request ID. Like Record, call
+ * it in the database.Transaction making the change.
+ *
+ * @param tx *database.Tx: The transaction making the change.
+ * @param action string: The action recorded, e.g. "booking.no_show".
+ * @param id int: The ID of the class or booking.
+ * @param before interface{}: The resource before the change.
+ * @param after interface{}: The resource after the change, nil when it is removed.
+ */
+func System(tx *database.Tx, action string, id int, before interface{}, after interface{}) error {
+	entry, err := newEntry(action, id, before, after)
+	if err != nil {
+		return err
+	}
+
+	entry.Actor = SystemActor
+	tx.Audit(entry)
+	return nil
+}
+
+/**
+ * @brief Verify checks the hash chain of audit entries.
+ *
+ * @param entries []models.AuditEntry: The audit log, oldest first.
+ * @return models.AuditVerification: Invalid, with the first broken entry, when an entry was changed or removed.
+ */
+func Verify(entries []models.AuditEntry) models.AuditVerification {
+	verification := models.AuditVerification{Valid: true, Entries: len(entries)}
+
+	previous := ""
+	for _, entry := range entries {
+		if entry.PrevHash != previous || entry.Hash != entry.Digest() {
+			verification.Valid = false
+			verification.BrokenAt = entry.ID
+			return verification
+		}
+		previous = entry.Hash
+	}
+	return verification
+}
+
+// newEntry returns the audit entry of a change, without its actor.
+func newEntry(action string, id int, before interface{}, after interface{}) (models.AuditEntry, error) {
+	changes, err := diff(before, after)
+	if err != nil {
+		return models.AuditEntry{}, err
+	}
+
+	entry := models.AuditEntry{
+		At:         time.Now().UTC(),
+		Action:     action,
+		ResourceId: id,
+		Changes:    changes,
+	}
+	entry.Resource, _, _ = strings.Cut(action, ".")
+	return entry, nil
+}
+
+// diff returns the top-level JSON fields whose value differs between before and after.
+func diff(before interface{}, after interface{}) (map[string]models.AuditChange, error) {
+	old, err := fields(before)
+	if err != nil {
+		return nil, err
+	}
+	current, err := fields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]models.AuditChange{}
+	for name, value := range current {
+		if !bytes.Equal(old[name], value) {
+			changes[name] = models.AuditChange{Before: old[name], After: value}
+		}
+	}
+	for name, value := range old {
+		if _, ok := current[name]; !ok {
+			changes[name] = models.AuditChange{Before: value}
+		}
+	}
+	return changes, nil
+}
+
+func fields(resource interface{}) (map[string]json.RawMessage, error) {
+	values := map[string]json.RawMessage{}
+	if resource == nil {
+		return values, nil
+	}
+
+	payload, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(payload, &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
\ No newline at end of file
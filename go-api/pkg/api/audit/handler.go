@@ -0,0 +1,115 @@
+package audit
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"go-api/pkg/models"
+	"go-api/pkg/mockDatabase"
+	"github.com/gin-gonic/gin"
+)
+
+// filter holds the query parameters of GetAudit.
+type filter struct {
+	actor      string
+	action     string
+	resource   string
+	resourceId int
+	requestId  string
+	from       time.Time
+	to         time.Time
+}
+
+/**
+ * @brief GetAudit returns the audit log, oldest first, optionally filtered (see parseFilter).
+ *
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func GetAudit(c *gin.Context) {
+	f, ok := parseFilter(c)
+	if !ok {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid filter"})
+		return
+	}
+
+	entries := []models.AuditEntry{}
+	for _, entry := range database.AuditLog() {
+		if f.matches(entry) {
+			entries = append(entries, entry)
+		}
+	}
+
+	c.IndentedJSON(http.StatusOK, entries)
+}
+
+/**
+ * @brief VerifyAudit checks that no entry of the audit log was changed or removed.
+ *
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func VerifyAudit(c *gin.Context) {
+	c.IndentedJSON(http.StatusOK, Verify(database.AuditLog()))
+}
+
+/**
+ * @brief parseFilter reads ?actor=, ?action=, ?resource=, ?resource_id=, ?request_id=, ?from= and ?to=.
+ *
+ * from and to are RFC 3339 instants bounding the entry time; from is
+ * inclusive and to exclusive. actor matches case-insensitively.
+ *
+ * @param c *gin.Context: The Gin HTTP context.
+ * @return filter: The parsed filter.
+ * @return bool: false when a parameter is malformed.
+ */
+func parseFilter(c *gin.Context) (filter, bool) {
+	var f filter
+	var err error
+
+	if value := c.Query("resource_id"); value != "" {
+		if f.resourceId, err = strconv.Atoi(value); err != nil {
+			return f, false
+		}
+	}
+	if value := c.Query("from"); value != "" {
+		if f.from, err = time.Parse(time.RFC3339, value); err != nil {
+			return f, false
+		}
+	}
+	if value := c.Query("to"); value != "" {
+		if f.to, err = time.Parse(time.RFC3339, value); err != nil {
+			return f, false
+		}
+	}
+	f.actor = c.Query("actor")
+	f.action = c.Query("action")
+	f.resource = c.Query("resource")
+	f.requestId = c.Query("request_id")
+
+	return f, true
+}
+
+func (f filter) matches(entry models.AuditEntry) bool {
+	if f.actor != "" && !strings.EqualFold(entry.Actor, f.actor) {
+		return false
+	}
+	if f.action != "" && entry.Action != f.action {
+		return false
+	}
+	if f.resource != "" && entry.Resource != f.resource {
+		return false
+	}
+	if f.resourceId != 0 && entry.ResourceId != f.resourceId {
+		return false
+	}
+	if f.requestId != "" && entry.RequestId != f.requestId {
+		return false
+	}
+	if !f.from.IsZero() && entry.At.Before(f.from) {
+		return false
+	}
+	if !f.to.IsZero() && !entry.At.Before(f.to) {
+		return false
+	}
+	return true
+}
\ No newline at end of file
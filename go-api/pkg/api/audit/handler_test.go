@@ -0,0 +1,126 @@
+package audit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"go-api/pkg/models"
+	"go-api/pkg/api/middleware"
+	"go-api/pkg/api/openapi"
+	"go-api/pkg/mockDatabase"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// newRouter returns a test Gin router that fails the test on responses not matching the OpenAPI spec.
+func newRouter(t *testing.T) *gin.Engine {
+	router := gin.Default()
+	router.Use(openapi.ValidateResponses(func(violation string) { t.Error(violation) }))
+	router.Use(middleware.RequestID(), middleware.Authenticate(map[string]middleware.Principal{
+		"admin-key": {Name: "owner", Role: "admin"},
+		"staff-key": {Name: "frontdesk", Role: "staff"},
+	}))
+	router.GET("/audit", GetAudit)
+	router.GET("/audit/verify", VerifyAudit)
+
+	// Changes the capacity of a class the way the class handlers do
+	router.PUT("/classes/:id", func(c *gin.Context) {
+		before := models.Class{ID: 900, Name: "Audited", Capacity: 10, Version: 1}
+		after := before
+		after.Capacity = 12
+		after.Version = 2
+		err := database.Transaction(func(tx *database.Tx) error {
+			return Record(c, tx, "class.updated", after.ID, before, after)
+		})
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Could not save Class"})
+			return
+		}
+		c.IndentedJSON(http.StatusOK, after)
+	})
+	return router
+}
+
+func serve(router *gin.Engine, method string, path string, key string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, nil)
+	req.Header.Add(middleware.APIKeyHeader, key)
+	req.Header.Add(middleware.RequestIDHeader, "req-audit")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetAudit(t *testing.T) {
+	router := newRouter(t)
+
+	serve(router, http.MethodPut, "/classes/900", "staff-key")
+
+	w := serve(router, http.MethodGet, "/audit?resource=class&resource_id=900&actor=FrontDesk", "admin-key")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var entries []models.AuditEntry
+	if err := json.Unmarshal(w.Body.Bytes(), &entries); err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, entries, 1) {
+		entry := entries[0]
+		assert.Equal(t, "frontdesk", entry.Actor)
+		assert.Equal(t, "staff", entry.Role)
+		assert.Equal(t, "req-audit", entry.RequestId)
+		assert.Equal(t, "class.updated", entry.Action)
+		assert.Equal(t, "class", entry.Resource)
+		// Only the changed fields are listed
+		assert.Equal(t, map[string]models.AuditChange{
+			"capacity": {Before: json.RawMessage(`10`), After: json.RawMessage(`12`)},
+			"version":  {Before: json.RawMessage(`1`), After: json.RawMessage(`2`)},
+		}, entry.Changes)
+	}
+
+	// Other filters exclude it
+	w = serve(router, http.MethodGet, "/audit?resource_id=900&action=class.deleted", "admin-key")
+	assert.Equal(t, "[]", w.Body.String())
+	w = serve(router, http.MethodGet, "/audit?from=yesterday", "admin-key")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestVerifyAudit(t *testing.T) {
+	router := newRouter(t)
+
+	serve(router, http.MethodPut, "/classes/900", "staff-key")
+	serve(router, http.MethodPut, "/classes/900", "admin-key")
+
+	w := serve(router, http.MethodGet, "/audit/verify", "admin-key")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var verification models.AuditVerification
+	if err := json.Unmarshal(w.Body.Bytes(), &verification); err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, verification.Valid)
+	assert.GreaterOrEqual(t, verification.Entries, 2)
+
+	// Changing or removing an entry breaks the chain from that entry on
+	entries := database.AuditLog()
+	tampered := append([]models.AuditEntry(nil), entries...)
+	tampered[0].Actor = "someone else"
+	assert.Equal(t, models.AuditVerification{Valid: false, Entries: len(entries), BrokenAt: entries[0].ID}, Verify(tampered))
+
+	removed := append(append([]models.AuditEntry(nil), entries[:1]...), entries[2:]...)
+	assert.False(t, Verify(removed).Valid)
+}
+
+func TestSystem(t *testing.T) {
+	before := models.Booking{ID: 901, Name: "Audited", Status: models.BookingConfirmed}
+	err := database.Transaction(func(tx *database.Tx) error {
+		return System(tx, "booking.purged", before.ID, before, nil)
+	})
+	assert.NoError(t, err)
+
+	entries := database.AuditLog()
+	entry := entries[len(entries)-1]
+	assert.Equal(t, SystemActor, entry.Actor)
+	assert.Empty(t, entry.RequestId)
+	assert.Equal(t, "booking", entry.Resource)
+	assert.Equal(t, json.RawMessage(`"Audited"`), entry.Changes["name"].Before)
+	assert.Nil(t, entry.Changes["name"].After)
+}
\ No newline at end of file
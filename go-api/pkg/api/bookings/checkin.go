@@ -0,0 +1,262 @@
+package bookings
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"go-api/pkg/models"
+	"go-api/pkg/api/audit"
+	"go-api/pkg/api/etag"
+	"go-api/pkg/api/middleware"
+	"go-api/pkg/attendance"
+	"go-api/pkg/mockDatabase"
+	"go-api/pkg/events"
+	"github.com/gin-gonic/gin"
+)
+
+/**
+ * @brief CheckInClass marks a member, or one booking, of a class as attended.
+ *
+ * Check-in opens attendance.CheckInOpens before the booked session starts and
+ * closes when it ends. Checking in twice returns the attended booking again.
+ *
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func CheckInClass(c *gin.Context) {
+	id, ok := checkInClassID(c)
+	if !ok {
+		return
+	}
+
+	var request models.CheckIn
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid Check-in"})
+		return
+	}
+
+	if err := models.BookingValidate.Struct(request); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid Check-in"})
+		return
+	}
+
+	booking, err := attendance.CheckIn(id, request, now(), auditCheckIn(c))
+	if err != nil {
+		c.IndentedJSON(checkInStatus(err), gin.H{"error": checkInError(err)})
+		return
+	}
+
+	c.Header("ETag", etag.For("booking", booking.ID, booking.Version, ""))
+	c.IndentedJSON(http.StatusOK, booking)
+}
+
+/**
+ * @brief BulkCheckInClass checks in several members of a class roster.
+ *
+ * Every check-in is applied on its own, like CheckInClass; the report lists
+ * the outcome of each one in request order.
+ *
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func BulkCheckInClass(c *gin.Context) {
+	id, ok := checkInClassID(c)
+	if !ok {
+		return
+	}
+
+	var request models.BulkCheckIn
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid Check-in"})
+		return
+	}
+
+	if err := models.BookingValidate.Struct(request); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid Check-in"})
+		return
+	}
+
+	report := models.CheckInReport{Results: []models.CheckInResult{}}
+	at := now()
+	for _, checkIn := range request.CheckIns {
+		result := models.CheckInResult{BookingId: checkIn.BookingId, MemberId: checkIn.MemberId, Status: "checked_in"}
+
+		booking, err := attendance.CheckIn(id, checkIn, at, auditCheckIn(c))
+		if err != nil {
+			result.Status = "failed"
+			result.Error = checkInError(err)
+			report.Failed++
+		} else {
+			result.Booking = &booking
+			report.CheckedIn++
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	c.IndentedJSON(http.StatusOK, report)
+}
+
+/**
+ * @brief GetCheckInCode issues a signed check-in token for a confirmed booking.
+ *
+ * The token is returned as JSON, or rendered as a QR code with ?format=png or
+ * ?format=svg. Members can only get codes for their own bookings.
+ *
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func GetCheckInCode(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	existingBooking, _ := database.FindItemByID(database.Bookings, id)
+	if existingBooking == nil {
+		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
+		return
+	}
+	booking := existingBooking.(*models.Booking)
+
+	if principal, _ := middleware.CurrentPrincipal(c); principal.Role == "member" && !ownsBooking(principal.MemberId, *booking) {
+		c.IndentedJSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
+		return
+	}
+
+	if booking.Status != models.BookingConfirmed {
+		c.IndentedJSON(http.StatusConflict, gin.H{"error": "Only confirmed bookings can be checked in"})
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "png" && format != "svg" {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid format"})
+		return
+	}
+
+	token, expires, err := attendance.DefaultTokens.Issue(booking.ID, now())
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Could not create check-in code"})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	switch format {
+	case "png":
+		image, err := attendance.QRCodePNG(token, 256)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Could not create check-in code"})
+			return
+		}
+		c.Data(http.StatusOK, "image/png", image)
+	case "svg":
+		image, err := attendance.QRCodeSVG(token)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Could not create check-in code"})
+			return
+		}
+		c.Data(http.StatusOK, "image/svg+xml", image)
+	default:
+		c.IndentedJSON(http.StatusOK, models.CheckInCode{BookingId: booking.ID, Token: token, ExpiresAt: expires})
+	}
+}
+
+/**
+ * @brief KioskCheckIn checks in the booking of a token scanned by the front-desk kiosk.
+ *
+ * The token must be validly signed, unexpired and not used before, and its
+ * booking open for check-in, i.e. its class starting soon or running.
+ *
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func KioskCheckIn(c *gin.Context) {
+	var request models.KioskCheckIn
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid Check-in"})
+		return
+	}
+
+	if err := models.BookingValidate.Struct(request); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid Check-in"})
+		return
+	}
+
+	booking, err := attendance.DefaultTokens.CheckIn(request.Token, now(), auditCheckIn(c))
+	switch {
+	case errors.Is(err, attendance.ErrInvalidToken):
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid check-in token"})
+		return
+	case errors.Is(err, attendance.ErrExpiredToken):
+		c.IndentedJSON(http.StatusGone, gin.H{"error": "Check-in token has expired"})
+		return
+	case errors.Is(err, attendance.ErrTokenUsed):
+		c.IndentedJSON(http.StatusConflict, gin.H{"error": "Check-in token has already been used"})
+		return
+	case err != nil:
+		c.IndentedJSON(checkInStatus(err), gin.H{"error": checkInError(err)})
+		return
+	}
+
+	c.Header("ETag", etag.For("booking", booking.ID, booking.Version, ""))
+	c.IndentedJSON(http.StatusOK, booking)
+}
+
+// auditCheckIn records the check-ins of a request in the audit log.
+func auditCheckIn(c *gin.Context) attendance.Audit {
+	return func(tx *database.Tx, before models.Booking, after models.Booking) error {
+		return audit.Record(c, tx, events.BookingAttended, after.ID, before, after)
+	}
+}
+
+// ownsBooking reports whether a booking carries the name of a member.
+func ownsBooking(memberId int, booking models.Booking) bool {
+	member, _ := database.FindItemByID(database.Members, memberId)
+	return member != nil && strings.EqualFold(member.(*models.Member).Name, booking.Name)
+}
+
+func checkInClassID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return 0, false
+	}
+
+	class, _ := database.FindItemByID(database.Classes, id)
+	if class == nil {
+		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Class not found"})
+		return 0, false
+	}
+	return id, true
+}
+
+// checkInStatus maps the errors of attendance.CheckIn to a status code.
+func checkInStatus(err error) int {
+	switch {
+	case errors.Is(err, attendance.ErrBookingNotFound), errors.Is(err, attendance.ErrMemberNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, attendance.ErrNotBooked), errors.Is(err, attendance.ErrNotOpen), errors.Is(err, attendance.ErrNotConfirmed):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// checkInError is the message returned for an error of attendance.CheckIn.
+func checkInError(err error) string {
+	switch {
+	case errors.Is(err, attendance.ErrBookingNotFound):
+		return "Booking not found"
+	case errors.Is(err, attendance.ErrMemberNotFound):
+		return "Member not found"
+	case errors.Is(err, attendance.ErrNotBooked):
+		return "Member has no booking open for check-in"
+	case errors.Is(err, attendance.ErrNotOpen):
+		return "Check-in is not open"
+	case errors.Is(err, attendance.ErrNotConfirmed):
+		return "Only confirmed bookings can be checked in"
+	}
+	return "Could not save Booking"
+}
\ No newline at end of file
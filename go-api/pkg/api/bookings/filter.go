@@ -0,0 +1,94 @@
+package bookings
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"go-api/pkg/models"
+	"go-api/pkg/mockDatabase"
+	"github.com/gin-gonic/gin"
+)
+
+// filter holds the query parameters shared by GetBookings, ExportBookings and GetClassRoster.
+type filter struct {
+	classId  int
+	studioId int
+	name     string
+	statuses []string
+	from     time.Time
+	to       time.Time
+	// includeDeleted also lists the bookings soft-deleted with their class.
+	includeDeleted bool
+}
+
+/**
+ * @brief parseFilter reads ?class_id=, ?studio_id=, ?name=, ?status=, ?from= and ?to=.
+ *
+ * from and to are RFC 3339 instants bounding the booking date; from is
+ * inclusive and to exclusive. name matches case-insensitively. status takes a
+ * comma-separated list of statuses.
+ *
+ * @param c *gin.Context: The Gin HTTP context.
+ * @return filter: The parsed filter.
+ * @return bool: false when a parameter is malformed.
+ */
+func parseFilter(c *gin.Context) (filter, bool) {
+	var f filter
+	var err error
+
+	if value := c.Query("class_id"); value != "" {
+		if f.classId, err = strconv.Atoi(value); err != nil {
+			return f, false
+		}
+	}
+	if value := c.Query("studio_id"); value != "" {
+		if f.studioId, err = strconv.Atoi(value); err != nil {
+			return f, false
+		}
+	}
+	if value := c.Query("from"); value != "" {
+		if f.from, err = time.Parse(time.RFC3339, value); err != nil {
+			return f, false
+		}
+	}
+	if value := c.Query("to"); value != "" {
+		if f.to, err = time.Parse(time.RFC3339, value); err != nil {
+			return f, false
+		}
+	}
+	f.name = c.Query("name")
+	if value := c.Query("status"); value != "" {
+		f.statuses = strings.Split(value, ",")
+	}
+
+	return f, true
+}
+
+func (f filter) matches(booking models.Booking) bool {
+	if booking.DeletedAt != nil && !f.includeDeleted {
+		return false
+	}
+	if f.classId != 0 && booking.ClassId != f.classId {
+		return false
+	}
+	if f.studioId != 0 {
+		class, _ := database.FindAnyItemByID(database.Classes, booking.ClassId)
+		if class == nil || class.(*models.Class).StudioId != f.studioId {
+			return false
+		}
+	}
+	if f.name != "" && !strings.EqualFold(booking.Name, f.name) {
+		return false
+	}
+	if len(f.statuses) > 0 && !slices.Contains(f.statuses, booking.Status) {
+		return false
+	}
+	if !f.from.IsZero() && booking.Date.Before(f.from) {
+		return false
+	}
+	if !f.to.IsZero() && !booking.Date.Before(f.to) {
+		return false
+	}
+	return true
+}
\ No newline at end of file
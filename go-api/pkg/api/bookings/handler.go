@@ -3,22 +3,72 @@ package bookings
 import (
 	"net/http"
 	"strconv"
+	"go-api/pkg/credits"
 	"go-api/pkg/models"
+	"go-api/pkg/payments"
+	"go-api/pkg/api/audit"
+	"go-api/pkg/api/etag"
+	"go-api/pkg/api/middleware"
 	"go-api/pkg/mockDatabase"
+	"go-api/pkg/timezone"
+	"go-api/pkg/events"
 	"github.com/gin-gonic/gin"
 )
 
 /**
- * @brief GetBookings returns a list of all bookings.
+ * @brief GetBookings returns a list of all bookings, optionally filtered (see parseFilter).
+ *
+ * Bookings soft-deleted with their class are only listed to admins asking for ?include_deleted=true.
  * 
  * @param c *gin.Context: The Gin HTTP context.
  */
 func GetBookings(c *gin.Context) {
-	c.IndentedJSON(http.StatusOK, database.Bookings)
+	bookings := make([]models.Booking, 0, len(database.Bookings))
+	tags := make([]string, 0, len(database.Bookings))
+
+	f, ok := parseFilter(c)
+	if !ok {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid filter"})
+		return
+	}
+
+	if f.includeDeleted, ok = middleware.IncludeDeleted(c); !ok {
+		return
+	}
+
+	for _, booking := range database.Bookings {
+		if !f.matches(booking) {
+			continue
+		}
+		rendered, err := renderBooking(booking, c.Query("tz"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid time zone"})
+			return
+		}
+		bookings = append(bookings, rendered)
+		tags = append(tags, etag.For("booking", rendered.ID, rendered.Version, c.Query("tz")))
+	}
+
+	listTag := etag.ForList("bookings", tags)
+	c.Header("ETag", listTag)
+	if etag.IfNoneMatch(c.GetHeader("If-None-Match"), listTag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, bookings)
 }
 
 /**
  * @brief PostBookings creates a new booking.
+ *
+ * The booking is confirmed while the session has free seats and waitlisted
+ * once it is full, as counted in the transaction saving it. A booking made
+ * by a member, with their own API key, is covered by their membership or
+ * costs one class credit, taken in the same transaction; the name it carries
+ * does not matter. Other bookings of priced classes are pending until their
+ * payment succeeds (see PaymentWebhook); their payment intent is cancelled
+ * when the booking cannot be saved.
  * 
  * @param c *gin.Context: The Gin HTTP context.
  */
@@ -51,14 +101,77 @@ func PostBookings(c *gin.Context) {
 		return
 	}
 
-	var booking models.Booking = database.CreateBooking(newBooking)
+	principal, _ := middleware.CurrentPrincipal(c)
+
+	// Drop-ins of priced classes stay pending until their payment succeeds.
+	var payment *models.Payment
+	price := class.(*models.Class).Price
+	if entitlement, _ := credits.Entitlement(principal.MemberId, now()); price > 0 && entitlement == "" {
+		if !hasFreeSeat(newBooking.ClassId, newBooking.Date) {
+			c.IndentedJSON(http.StatusConflict, gin.H{"error": "Class is full"})
+			return
+		}
+
+		intent, err := payments.Default.CreateIntent(c.Request.Context(), price, payments.Currency, class.(*models.Class).Name)
+		if err != nil {
+			c.IndentedJSON(http.StatusBadGateway, gin.H{"error": "Could not create payment"})
+			return
+		}
+		payment = &models.Payment{IntentId: intent.ID, ClientSecret: intent.ClientSecret, Amount: intent.Amount, Currency: intent.Currency, Status: intent.Status}
+	}
 
-	database.Bookings = append(database.Bookings, booking)
+	var booking models.Booking
+	err := database.Transaction(func(tx *database.Tx) error {
+		// Seats are counted under the lock, so that concurrent requests cannot take the same last seat.
+		// Bookings beyond the capacity of the session wait for a seat to be freed; drop-ins are not taken then.
+		status := models.BookingConfirmed
+		switch free := hasFreeSeat(newBooking.ClassId, newBooking.Date); {
+		case payment != nil && !free:
+			return ErrClassFull
+		case payment != nil:
+			status = models.BookingPending
+		case !free:
+			status = models.BookingWaitlisted
+		}
+
+		booking = database.CreateBooking(newBooking)
+		booking.Status = ""
+		booking.Transition(status, now())
+		booking.MemberId = principal.MemberId
+		booking.Payment = payment
+		if payment == nil {
+			entitlement, err := credits.Entitlement(booking.MemberId, now())
+			if err != nil {
+				return err
+			}
+			booking.Entitlement = entitlement
+		}
+		if err := tx.Record(events.BookingCreated, booking.ID, booking); err != nil {
+			return err
+		}
+		if err := audit.Record(c, tx, events.BookingCreated, booking.ID, nil, booking); err != nil {
+			return err
+		}
+		database.Bookings = append(database.Bookings, booking)
+		credits.Spend(booking, now())
+		return nil
+	})
+	if err != nil && payment != nil {
+		payments.Default.Cancel(c.Request.Context(), payment.IntentId)
+	}
+	if err != nil {
+		respond(c, err)
+		return
+	}
+
+	c.Header("ETag", etag.For("booking", booking.ID, booking.Version, ""))
 	c.IndentedJSON(http.StatusCreated, booking)
 }
 
 /**
  * @brief GetBookingByID returns a booking by its ID.
+ *
+ * Bookings soft-deleted with their class are not found unless an admin asks for ?include_deleted=true.
  * 
  * @param c *gin.Context: The Gin HTTP context.
  */
@@ -70,17 +183,45 @@ func GetBookingByID(c *gin.Context) {
 		return
 	}
 
-	existingBooking, index := database.FindItemByID(database.Bookings, id)
+	include, ok := middleware.IncludeDeleted(c)
+	if !ok {
+		return
+	}
+
+	find := database.FindItemByID
+	if include {
+		find = database.FindAnyItemByID
+	}
+
+	existingBooking, _ := find(database.Bookings, id)
 	if existingBooking == nil {
 		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
 		return
 	}
 
-	c.IndentedJSON(http.StatusOK, database.Bookings[index])
+	booking, err := renderBooking(*existingBooking.(*models.Booking), c.Query("tz"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid time zone"})
+		return
+	}
+
+	tag := etag.For("booking", booking.ID, booking.Version, c.Query("tz"))
+	c.Header("ETag", tag)
+	if etag.IfNoneMatch(c.GetHeader("If-None-Match"), tag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, booking)
 }
 
 /**
  * @brief UpdateBooking updates a booking by its ID.
+ *
+ * The booking keeps the member who made it, and what covers it, whatever
+ * name it is given. A booking with a payment cannot move to another class,
+ * whose price may differ, and one with neither a payment nor an entitlement
+ * cannot move into a priced class (402): it has to be booked anew, and paid.
  * 
  * @param c *gin.Context: The Gin HTTP context.
  */
@@ -104,12 +245,6 @@ func UpdateBooking(c *gin.Context) {
 		return
 	}
 
-	existingBooking, index := database.FindItemByID(database.Bookings, id)
-	if existingBooking == nil {
-		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
-		return
-	}
-
 	class, _ := database.FindItemByID(database.Classes, updatedBooking.ClassId)
 	if class == nil {
 		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Class not found"})
@@ -125,30 +260,69 @@ func UpdateBooking(c *gin.Context) {
 		return
 	}
 
-	newBooking:= database.UpdateBooking(updatedBooking, id)
-	database.Bookings[index] = newBooking
-	c.IndentedJSON(http.StatusOK, newBooking)
-}
+	var newBooking models.Booking
+	err = database.Transaction(func(tx *database.Tx) error {
+		index, err := locateBooking(id, c.GetHeader("If-Match"))
+		if err != nil {
+			return err
+		}
 
-/**
- * @brief DeleteBooking deletes a booking by its ID.
- * 
- * @param c *gin.Context: The Gin HTTP context.
- */
-func DeleteBooking(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
+		current := database.Bookings[index]
+		if len(models.BookingTransitions[current.Status]) == 0 {
+			return conflict("Booking is " + current.Status)
+		}
+
+		// The payment was taken for the class booked, so paid bookings keep it.
+		if current.Payment != nil && updatedBooking.ClassId != current.ClassId {
+			return conflict("Paid bookings cannot move to another class")
+		}
+
+		moved := !bookingDate.Equal(current.Date) || updatedBooking.ClassId != current.ClassId
+		if moved && current.HoldsSeat() && !hasFreeSeat(updatedBooking.ClassId, bookingDate) {
+			return ErrClassFull
+		}
 
+		newBooking = database.UpdateBooking(updatedBooking, id, current.Version+1)
+		newBooking.Status = current.Status
+		newBooking.MemberId = current.MemberId
+		newBooking.Transitions = current.Transitions
+		newBooking.Entitlement = current.Entitlement
+		newBooking.Payment = current.Payment
+		// Drop-ins cannot move into a priced class, which they have not paid for.
+		if target, _ := database.FindItemByID(database.Classes, newBooking.ClassId); target != nil && target.(*models.Class).Price > 0 && newBooking.Entitlement == "" && newBooking.Payment == nil {
+			return errUnpaid
+		}
+		if err := tx.Record(events.BookingUpdated, newBooking.ID, newBooking); err != nil {
+			return err
+		}
+		if err := audit.Record(c, tx, events.BookingUpdated, newBooking.ID, current, newBooking); err != nil {
+			return err
+		}
+		database.Bookings[index] = newBooking
+		if moved && current.HoldsSeat() {
+			return promote(c, tx, current.ClassId, current.Date)
+		}
+		return nil
+	})
 	if err != nil {
-		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		respond(c, err)
 		return
 	}
 
-	existingBooking, index := database.FindItemByID(database.Bookings, id)
-	if existingBooking == nil {
-		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
-		return
-	}
+	c.Header("ETag", etag.For("booking", newBooking.ID, newBooking.Version, ""))
+	c.IndentedJSON(http.StatusOK, newBooking)
+}
 
-	database.Bookings = append(database.Bookings[:index], database.Bookings[index+1:]...)
-	c.IndentedJSON(http.StatusOK, gin.H{"message": "Booking deleted"})
+/**
+ * @brief renderBooking expresses the booking date in the zone requested through ?tz=.
+ *
+ * @param booking models.Booking: The stored booking.
+ * @param tz string: The requested zone, "studio" for the class's studio or an IANA name; "" keeps the stored date.
+ */
+func renderBooking(booking models.Booking, tz string) (models.Booking, error) {
+	loc, err := timezone.Resolve(tz, database.ClassTimeZone(booking.ClassId))
+	if err != nil || loc == nil {
+		return booking, err
+	}
+	return booking.In(loc), nil
 }
\ No newline at end of file
@@ -1,21 +1,39 @@
 package bookings
 
 import (
+	"archive/zip"
 	"bytes"
+	"context"
+	"encoding/csv"
 	"go-api/pkg/models"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"go-api/pkg/mockDatabase"
 	"time"
 	"testing"
+	"go-api/pkg/api/openapi"
+	"go-api/pkg/api/middleware"
+	"go-api/pkg/credits"
+	"go-api/pkg/payments"
+	"go-api/pkg/events"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 )
 
+// newRouter returns a test Gin router that fails the test on responses not matching the OpenAPI spec.
+func newRouter(t *testing.T) *gin.Engine {
+	router := gin.Default()
+	router.Use(openapi.ValidateResponses(func(violation string) { t.Error(violation) }))
+	return router
+}
+
 func TestGetBookingByID(t *testing.T) {
 	// Create a test Gin router
-	router := gin.Default()
+	router := newRouter(t)
 	router.GET("/bookings/:id", GetBookingByID)
 
 	// Create a GET request to retrieve booking with ID 1
@@ -43,7 +61,7 @@ func TestGetBookingByID(t *testing.T) {
 
 func TestGetBookings(t *testing.T) {
 	// Create a test Gin router
-	router := gin.Default()
+	router := newRouter(t)
 	router.GET("/bookings", GetBookings)
 
 	// Create a GET request to retrieve all bookings
@@ -74,7 +92,7 @@ func TestGetBookings(t *testing.T) {
 
 func TestPostBookings(t *testing.T) {
 	// Create a test Gin router
-	router := gin.Default()
+	router := newRouter(t)
 	router.POST("/bookings", PostBookings)
 
 	// Define a new booking  for testing
@@ -111,11 +129,16 @@ func TestPostBookings(t *testing.T) {
 	assert.Equal(t, newBooking.Name, createdBooking.Name)
 	assert.Equal(t, newBooking.ClassId, createdBooking.ClassId)
 	assert.Equal(t, newBooking.Date, createdBooking.Date)
+
+	// The booking.created event was written to the outbox with the booking
+	event := database.Outbox[len(database.Outbox)-1]
+	assert.Equal(t, events.BookingCreated, event.Type)
+	assert.Equal(t, createdBooking.ID, event.AggregateID)
 }
 
 func TestUpdateBooking(t *testing.T) {
 	// Create a test Gin router
-	router := gin.Default()
+	router := newRouter(t)
 	router.PUT("/bookings/:id", UpdateBooking)
 
 	// Define the updated booking  for testing
@@ -155,22 +178,798 @@ func TestUpdateBooking(t *testing.T) {
 
 func TestDeleteBooking(t *testing.T) {
 	// Create a test Gin router
-	router := gin.Default()
-	router.DELETE("/bookings/:id", DeleteBooking)
+	router := newRouter(t)
+	router.DELETE("/bookings/:id", CancelBooking)
 
-	// Create a DELETE request to delete an existing booking
-	req, _ := http.NewRequest(http.MethodDelete, "/bookings/1", nil)
+	booking := database.CreateBooking(models.CreateBooking{Name: "Cancelled", ClassId: 1, Date: time.Date(2023, 10, 9, 16, 0, 0, 0, time.UTC)})
+	database.Bookings = append(database.Bookings, booking)
+
+	// A day before the session is within the free window
+	now = func() time.Time { return time.Date(2023, 10, 8, 16, 0, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+
+	// Create a DELETE request to cancel the booking
+	req, _ := http.NewRequest(http.MethodDelete, "/bookings/"+strconv.Itoa(booking.ID), nil)
 
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
 	// Assert that the HTTP status code is OK (200)
 	assert.Equal(t, http.StatusOK, w.Code)
+
+	// The booking is kept, cancelled for free
+	var cancelled models.Booking
+	if err := json.Unmarshal(w.Body.Bytes(), &cancelled); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, models.BookingCancelled, cancelled.Status)
+	assert.Equal(t, 2, cancelled.Version)
+	if assert.NotNil(t, cancelled.Cancellation) {
+		assert.Equal(t, "free", cancelled.Cancellation.Outcome)
+		assert.Equal(t, PenaltyNone, cancelled.Cancellation.Penalty)
+	}
+
+	stored, _ := database.FindItemByID(database.Bookings, booking.ID)
+	assert.Equal(t, models.BookingCancelled, stored.(*models.Booking).Status)
+
+	// DELETE still records booking.deleted, after booking.cancelled
+	event := database.Outbox[len(database.Outbox)-2]
+	assert.Equal(t, events.BookingCancelled, event.Type)
+	assert.Equal(t, booking.ID, event.AggregateID)
+	assert.Equal(t, events.BookingDeleted, database.Outbox[len(database.Outbox)-1].Type)
+
+	// Cancelling twice is a Conflict (409)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestDeleteBookingLate(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.DELETE("/bookings/:id", CancelBooking)
+
+	booking := database.CreateBooking(models.CreateBooking{Name: "Late", ClassId: 1, Date: time.Date(2023, 10, 10, 16, 0, 0, 0, time.UTC)})
+	database.Bookings = append(database.Bookings, booking)
+
+	Policy = CancellationPolicy{FreeWindow: 12 * time.Hour, LatePenalty: PenaltyFee, LateFee: 500}
+	defer func() { Policy = DefaultCancellationPolicy() }()
+
+	// An hour before the session is a late cancellation
+	now = func() time.Time { return time.Date(2023, 10, 10, 15, 0, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+
+	req, _ := http.NewRequest(http.MethodDelete, "/bookings/"+strconv.Itoa(booking.ID), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert that the HTTP status code is OK (200) and the fee is charged
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var cancelled models.Booking
+	if err := json.Unmarshal(w.Body.Bytes(), &cancelled); err != nil {
+		t.Fatal(err)
+	}
+	if assert.NotNil(t, cancelled.Cancellation) {
+		assert.Equal(t, "late", cancelled.Cancellation.Outcome)
+		assert.Equal(t, PenaltyFee, cancelled.Cancellation.Penalty)
+		assert.Equal(t, 500, cancelled.Cancellation.Fee)
+	}
+}
+
+func TestDeleteBookingStarted(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.DELETE("/bookings/:id", CancelBooking)
+
+	// The session of booking 1 started in 2023
+	req, _ := http.NewRequest(http.MethodDelete, "/bookings/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert that the HTTP status code is Conflict (409) and the booking is untouched
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, models.BookingConfirmed, database.Bookings[0].Status)
+}
+
+func TestCancellationPolicyUnmarshal(t *testing.T) {
+	policy := DefaultCancellationPolicy()
+	assert.NoError(t, json.Unmarshal([]byte(`{"late_penalty": "credit"}`), &policy))
+	assert.Equal(t, 12*time.Hour, policy.FreeWindow)
+	assert.Equal(t, PenaltyCredit, policy.LatePenalty)
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"free_window": "2h30m", "late_penalty": "fee", "late_fee": 300}`), &policy))
+	assert.Equal(t, 150*time.Minute, policy.FreeWindow)
+	assert.Equal(t, 300, policy.LateFee)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"late_penalty": "ban"}`), &policy))
+	assert.Error(t, json.Unmarshal([]byte(`{"free_window": "soon"}`), &policy))
+}
+
+func TestCancellationPolicyRefund(t *testing.T) {
+	policy := DefaultCancellationPolicy()
+	assert.Equal(t, 1500, policy.Refund(1500, models.Cancellation{Outcome: "free", Penalty: PenaltyNone}))
+	assert.Equal(t, 1500, policy.Refund(1500, models.Cancellation{Outcome: "late", Penalty: PenaltyNone}))
+	assert.Equal(t, 1000, policy.Refund(1500, models.Cancellation{Outcome: "late", Penalty: PenaltyFee, Fee: 500}))
+	assert.Equal(t, 0, policy.Refund(300, models.Cancellation{Outcome: "late", Penalty: PenaltyFee, Fee: 500}))
+	assert.Equal(t, 0, policy.Refund(1500, models.Cancellation{Outcome: "late", Penalty: PenaltyCredit}))
+}
+
+// postStatus sends POST /bookings/:id/<action> and decodes the booking of a 200 response.
+func postStatus(t *testing.T, router *gin.Engine, id int, action string) (int, models.Booking) {
+	req, _ := http.NewRequest(http.MethodPost, "/bookings/"+strconv.Itoa(id)+"/"+action, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var booking models.Booking
+	if w.Code == http.StatusOK {
+		if err := json.Unmarshal(w.Body.Bytes(), &booking); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return w.Code, booking
+}
+
+func TestBookingWaitlist(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.POST("/bookings", PostBookings)
+	router.POST("/bookings/:id/cancel", CancelBooking)
+	router.POST("/bookings/:id/attend", AttendBooking)
+	router.POST("/bookings/:id/no-show", MarkNoShow)
+
+	start := time.Date(2023, 11, 1, 10, 0, 0, 0, time.UTC)
+	class := database.CreateClass(models.CreateClass{Name: "Tiny", StudioId: 1, StartDate: start, EndDate: start.Add(time.Hour), Capacity: 1})
+	database.Classes = append(database.Classes, class)
+
+	now = func() time.Time { return start.Add(-48 * time.Hour) }
+	defer func() { now = time.Now }()
+
+	// The first booking takes the only seat, the second one waits
+	var created []models.Booking
+	for _, name := range []string{"First", "Second"} {
+		body, _ := json.Marshal(models.CreateBooking{Name: name, ClassId: class.ID, Date: start})
+		req, _ := http.NewRequest(http.MethodPost, "/bookings", bytes.NewReader(body))
+		req.Header.Add("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var booking models.Booking
+		if err := json.Unmarshal(w.Body.Bytes(), &booking); err != nil {
+			t.Fatal(err)
+		}
+		created = append(created, booking)
+	}
+	assert.Equal(t, models.BookingConfirmed, created[0].Status)
+	assert.Equal(t, models.BookingWaitlisted, created[1].Status)
+	assert.Equal(t, []models.BookingTransition{{To: models.BookingWaitlisted, At: now().UTC()}}, created[1].Transitions)
+	assert.Equal(t, 1, database.SeatsTaken(class.ID, start))
+
+	// Cancelling the first booking promotes the second in the same transaction
+	code, _ := postStatus(t, router, created[0].ID, "cancel")
+	assert.Equal(t, http.StatusOK, code)
+
+	promoted, _ := database.FindItemByID(database.Bookings, created[1].ID)
+	assert.Equal(t, models.BookingConfirmed, promoted.(*models.Booking).Status)
+	assert.Len(t, promoted.(*models.Booking).Transitions, 2)
+	event := database.Outbox[len(database.Outbox)-1]
+	assert.Equal(t, events.BookingPromoted, event.Type)
+	assert.Equal(t, created[1].ID, event.AggregateID)
+
+	// Attended is final
+	code, attended := postStatus(t, router, created[1].ID, "attend")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, models.BookingAttended, attended.Status)
+	assert.Equal(t, models.BookingConfirmed, attended.Transitions[2].From)
+
+	code, _ = postStatus(t, router, created[1].ID, "no-show")
+	assert.Equal(t, http.StatusConflict, code)
+}
+
+func TestPostBookingsLastSeatConcurrent(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.POST("/bookings", PostBookings)
+
+	start := time.Date(2023, 11, 3, 7, 0, 0, 0, time.UTC)
+	class := database.CreateClass(models.CreateClass{Name: "LastSeat", StudioId: 1, StartDate: start, EndDate: start.Add(time.Hour), Capacity: 1})
+	database.Classes = append(database.Classes, class)
+
+	// Many drop-ins book the only seat at once
+	const requests = 20
+	statuses := make(chan string, requests)
+	for i := 0; i < requests; i++ {
+		body, _ := json.Marshal(models.CreateBooking{Name: "Racer" + strconv.Itoa(i), ClassId: class.ID, Date: start})
+		go func() {
+			req, _ := http.NewRequest(http.MethodPost, "/bookings", bytes.NewReader(body))
+			req.Header.Add("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			var booking models.Booking
+			json.Unmarshal(w.Body.Bytes(), &booking)
+			statuses <- booking.Status
+		}()
+	}
+
+	// Only one gets it; the others are waitlisted
+	confirmed := 0
+	for i := 0; i < requests; i++ {
+		status := <-statuses
+		if status == models.BookingConfirmed {
+			confirmed++
+		} else {
+			assert.Equal(t, models.BookingWaitlisted, status)
+		}
+	}
+	assert.Equal(t, 1, confirmed)
+	assert.Equal(t, 1, database.SeatsTaken(class.ID, start))
+}
+
+func TestConfirmBooking(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.POST("/bookings/:id/confirm", ConfirmBooking)
+	router.POST("/bookings/:id/no-show", MarkNoShow)
+
+	start := time.Date(2023, 11, 2, 10, 0, 0, 0, time.UTC)
+	class := database.CreateClass(models.CreateClass{Name: "Duo", StudioId: 1, StartDate: start, EndDate: start.Add(time.Hour), Capacity: 1})
+	database.Classes = append(database.Classes, class)
+
+	pending := database.CreateBooking(models.CreateBooking{Name: "Pending", ClassId: class.ID, Date: start})
+	pending.Status = models.BookingPending
+	waiting := database.CreateBooking(models.CreateBooking{Name: "Waiting", ClassId: class.ID, Date: start})
+	waiting.Status = models.BookingWaitlisted
+	database.Bookings = append(database.Bookings, pending, waiting)
+
+	// A pending booking already holds its seat
+	code, confirmed := postStatus(t, router, pending.ID, "confirm")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, models.BookingConfirmed, confirmed.Status)
+	assert.Equal(t, 2, confirmed.Version)
+	assert.Equal(t, events.BookingConfirmed, database.Outbox[len(database.Outbox)-1].Type)
+
+	// The waitlisted one cannot be confirmed while the class is full
+	code, _ = postStatus(t, router, waiting.ID, "confirm")
+	assert.Equal(t, http.StatusConflict, code)
+
+	// Waitlisted bookings cannot be marked as no-shows
+	code, _ = postStatus(t, router, waiting.ID, "no-show")
+	assert.Equal(t, http.StatusConflict, code)
+
+	// A no-show frees the seat for later counts
+	code, _ = postStatus(t, router, pending.ID, "no-show")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 0, database.SeatsTaken(class.ID, start))
+}
+
+func TestCheckInClass(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.POST("/classes/:id/check-in", CheckInClass)
+	router.POST("/classes/:id/roster/check-in", BulkCheckInClass)
+
+	start := time.Date(2023, 11, 3, 18, 0, 0, 0, time.UTC)
+	class := database.CreateClass(models.CreateClass{Name: "Evening", StudioId: 1, StartDate: start, EndDate: start.Add(time.Hour), Capacity: 10})
+	database.Classes = append(database.Classes, class)
+	diego := database.CreateBooking(models.CreateBooking{Name: "Diego", ClassId: class.ID, Date: start})
+	martin := database.CreateBooking(models.CreateBooking{Name: "Martin", ClassId: class.ID, Date: start})
+	database.Bookings = append(database.Bookings, diego, martin)
+
+	now = func() time.Time { return start.Add(-10 * time.Minute) }
+	defer func() { now = time.Now }()
+
+	checkIn := func(path string, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Add("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Diego (member 1) checks in through his booking
+	w := checkIn("/classes/"+strconv.Itoa(class.ID)+"/check-in", `{"member_id": 1}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var booking models.Booking
+	if err := json.Unmarshal(w.Body.Bytes(), &booking); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, diego.ID, booking.ID)
+	assert.Equal(t, models.BookingAttended, booking.Status)
+
+	// Joaquin (member 3) has no booking, and a body naming nobody is a Bad Request (400)
+	w = checkIn("/classes/"+strconv.Itoa(class.ID)+"/check-in", `{"member_id": 3}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	w = checkIn("/classes/"+strconv.Itoa(class.ID)+"/check-in", `{}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// The roster check-in reports every entry
+	w = checkIn("/classes/"+strconv.Itoa(class.ID)+"/roster/check-in", `{"check_ins": [{"booking_id": `+strconv.Itoa(martin.ID)+`}, {"booking_id": 1}]}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var report models.CheckInReport
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, report.CheckedIn)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, "checked_in", report.Results[0].Status)
+	assert.Equal(t, "Booking not found", report.Results[1].Error)
+
+	// Unknown classes are Not Found (404)
+	w = checkIn("/classes/999/check-in", `{"member_id": 1}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestKioskCheckIn(t *testing.T) {
+	// Create a test Gin router authenticating a member and the kiosk
+	router := newRouter(t)
+	router.Use(middleware.Authenticate(map[string]middleware.Principal{
+		"martin-key": {Name: "martin", Role: "member", MemberId: 2},
+		"kiosk-key":  {Name: "front desk", Role: "kiosk"},
+	}))
+	router.GET("/bookings/:id/checkin-code", middleware.RequireRole("admin", "staff", "member"), GetCheckInCode)
+	router.POST("/kiosk/check-in", middleware.RequireRole("admin", "kiosk"), KioskCheckIn)
+
+	start := time.Date(2023, 11, 4, 18, 0, 0, 0, time.UTC)
+	class := database.CreateClass(models.CreateClass{Name: "Evening", StudioId: 1, StartDate: start, EndDate: start.Add(time.Hour), Capacity: 10})
+	database.Classes = append(database.Classes, class)
+	booking := database.CreateBooking(models.CreateBooking{Name: "Martin", ClassId: class.ID, Date: start})
+	database.Bookings = append(database.Bookings, booking)
+
+	now = func() time.Time { return start.Add(-10 * time.Minute) }
+	defer func() { now = time.Now }()
+
+	request := func(method string, path string, key string, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Add("Content-Type", "application/json")
+		req.Header.Add(middleware.APIKeyHeader, key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Martin gets a code for his booking, as JSON or as a QR code
+	w := request(http.MethodGet, "/bookings/"+strconv.Itoa(booking.ID)+"/checkin-code", "martin-key", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var code models.CheckInCode
+	if err := json.Unmarshal(w.Body.Bytes(), &code); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, booking.ID, code.BookingId)
+	assert.Equal(t, start.Add(-8*time.Minute), code.ExpiresAt)
+
+	w = request(http.MethodGet, "/bookings/"+strconv.Itoa(booking.ID)+"/checkin-code?format=png", "martin-key", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+	w = request(http.MethodGet, "/bookings/"+strconv.Itoa(booking.ID)+"/checkin-code?format=svg", "martin-key", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, strings.HasPrefix(w.Body.String(), "<svg"))
+
+	// but not for Diego's booking
+	w = request(http.MethodGet, "/bookings/1/checkin-code", "martin-key", "")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// The kiosk checks the booking in once
+	w = request(http.MethodPost, "/kiosk/check-in", "kiosk-key", `{"token": "`+code.Token+`"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var attended models.Booking
+	if err := json.Unmarshal(w.Body.Bytes(), &attended); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, models.BookingAttended, attended.Status)
+
+	w = request(http.MethodPost, "/kiosk/check-in", "kiosk-key", `{"token": "`+code.Token+`"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	// Tampered tokens are a Bad Request (400), and members cannot use the kiosk
+	w = request(http.MethodPost, "/kiosk/check-in", "kiosk-key", `{"token": "`+code.Token+`x"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = request(http.MethodPost, "/kiosk/check-in", "martin-key", `{"token": "`+code.Token+`"}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Attended bookings get no more codes
+	w = request(http.MethodGet, "/bookings/"+strconv.Itoa(booking.ID)+"/checkin-code", "martin-key", "")
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestPostBookingsSpendsCredits(t *testing.T) {
+	member := database.CreateMember(models.CreateMember{Name: "Packer"})
+	database.Members = append(database.Members, member)
+
+	// Create a test Gin router authenticating the member
+	router := newRouter(t)
+	router.Use(middleware.Authenticate(map[string]middleware.Principal{
+		"packer-key": {Name: "packer", Role: "member", MemberId: member.ID},
+	}))
+	router.POST("/bookings", PostBookings)
+	router.POST("/bookings/:id/cancel", CancelBooking)
+
+	now = func() time.Time { return time.Date(2023, 10, 14, 16, 0, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+
+	post := func(path string, key string, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Add("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Add(middleware.APIKeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	book := `{"name": "Packer", "class_id": 1, "date": "2023-10-16T16:00:00Z"}`
+
+	// Without credits or a membership the member cannot book
+	w := post("/bookings", "packer-key", book)
+	assert.Equal(t, http.StatusPaymentRequired, w.Code)
+
+	// Anyone else booking under the member's name books a drop-in
+	w = post("/bookings", "", book)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.NotContains(t, w.Body.String(), "entitlement")
+	assert.NotContains(t, w.Body.String(), "member_id")
+
+	// A pack pays for the booking, and a free cancellation refunds it
+	credits.Purchase(member.ID, models.Plan{ID: 1, Kind: models.PlanPack, Credits: 1}, now())
+	w = post("/bookings", "packer-key", book)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var booking models.Booking
+	if err := json.Unmarshal(w.Body.Bytes(), &booking); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, models.EntitlementCredit, booking.Entitlement)
+	assert.Equal(t, member.ID, booking.MemberId)
+	assert.Equal(t, 0, credits.Balance(member.ID))
+	assert.Equal(t, http.StatusPaymentRequired, post("/bookings", "packer-key", book).Code)
+
+	w = post("/bookings/"+strconv.Itoa(booking.ID)+"/cancel", "", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 1, credits.Balance(member.ID))
+
+	// A membership covers bookings without spending credits
+	credits.Purchase(member.ID, models.Plan{ID: 2, Name: "Monthly", Kind: models.PlanMembership, Days: 30}, now())
+	w = post("/bookings", "packer-key", book)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	if err := json.Unmarshal(w.Body.Bytes(), &booking); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, models.EntitlementMembership, booking.Entitlement)
+	assert.Equal(t, 1, credits.Balance(member.ID))
+}
+
+func TestUpdateBookingKeepsEntitlement(t *testing.T) {
+	member := database.CreateMember(models.CreateMember{Name: "Renamed"})
+	database.Members = append(database.Members, member)
+	credits.Purchase(member.ID, models.Plan{ID: 1, Kind: models.PlanPack, Credits: 1}, time.Now())
+
+	// Create a test Gin router authenticating the member
+	router := newRouter(t)
+	router.Use(middleware.Authenticate(map[string]middleware.Principal{
+		"renamed-key": {Name: "renamed", Role: "member", MemberId: member.ID},
+	}))
+	router.POST("/bookings", PostBookings)
+	router.PUT("/bookings/:id", UpdateBooking)
+
+	request := func(method string, path string, key string, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Add("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Add(middleware.APIKeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// A drop-in renamed after a member stays a drop-in
+	w := request(http.MethodPost, "/bookings", "", `{"name": "Guest", "class_id": 1, "date": "2023-10-13T16:00:00Z"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var booking models.Booking
+	if err := json.Unmarshal(w.Body.Bytes(), &booking); err != nil {
+		t.Fatal(err)
+	}
+	w = request(http.MethodPut, "/bookings/"+strconv.Itoa(booking.ID), "", `{"name": "Renamed", "class_id": 1, "date": "2023-10-13T16:00:00Z"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "entitlement")
+	assert.Equal(t, 1, credits.Balance(member.ID))
+
+	// A member's booking keeps its member and credit whatever its name
+	w = request(http.MethodPost, "/bookings", "renamed-key", `{"name": "Renamed", "class_id": 1, "date": "2023-10-13T16:00:00Z"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	if err := json.Unmarshal(w.Body.Bytes(), &booking); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 0, credits.Balance(member.ID))
+
+	w = request(http.MethodPut, "/bookings/"+strconv.Itoa(booking.ID), "", `{"name": "Guest", "class_id": 1, "date": "2023-10-13T16:00:00Z"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"entitlement": "credit"`)
+	assert.Contains(t, w.Body.String(), `"member_id": `+strconv.Itoa(member.ID))
+	assert.Equal(t, 0, credits.Balance(member.ID))
+}
+
+func TestCancelBookingForfeitsCredit(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.POST("/bookings/:id/cancel", CancelBooking)
+
+	member := database.CreateMember(models.CreateMember{Name: "Forfeit"})
+	database.Members = append(database.Members, member)
+	credits.Purchase(member.ID, models.Plan{ID: 1, Kind: models.PlanPack, Credits: 1}, time.Now())
+
+	booking := database.CreateBooking(models.CreateBooking{Name: "Forfeit", ClassId: 1, Date: time.Date(2023, 10, 12, 16, 0, 0, 0, time.UTC)})
+	booking.MemberId = member.ID
+	booking.Entitlement = models.EntitlementCredit
+	database.Bookings = append(database.Bookings, booking)
+	credits.Spend(booking, time.Now())
+
+	Policy = CancellationPolicy{FreeWindow: 12 * time.Hour, LatePenalty: PenaltyCredit}
+	defer func() { Policy = DefaultCancellationPolicy() }()
+
+	// A late cancellation keeps the credit
+	now = func() time.Time { return time.Date(2023, 10, 12, 15, 0, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+
+	req, _ := http.NewRequest(http.MethodPost, "/bookings/"+strconv.Itoa(booking.ID)+"/cancel", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 0, credits.Balance(member.ID))
+}
+
+func TestPaidBooking(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.POST("/bookings", PostBookings)
+	router.POST("/bookings/:id/cancel", CancelBooking)
+	router.POST("/payments/webhook", PaymentWebhook)
+
+	fake := payments.NewFake([]byte("secret"))
+	payments.Default = fake
+	defer func() { payments.Default = payments.NewFake(nil) }()
+
+	start := time.Date(2023, 11, 6, 18, 0, 0, 0, time.UTC)
+	class := database.CreateClass(models.CreateClass{Name: "DropIn", StudioId: 1, StartDate: start, EndDate: start.Add(time.Hour), Capacity: 10, Price: 1500})
+	database.Classes = append(database.Classes, class)
+
+	now = func() time.Time { return start.Add(-48 * time.Hour) }
+	defer func() { now = time.Now }()
+
+	book := func(name string) models.Booking {
+		body, _ := json.Marshal(models.CreateBooking{Name: name, ClassId: class.ID, Date: start})
+		req, _ := http.NewRequest(http.MethodPost, "/bookings", bytes.NewReader(body))
+		req.Header.Add("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var booking models.Booking
+		if err := json.Unmarshal(w.Body.Bytes(), &booking); err != nil {
+			t.Fatal(err)
+		}
+		return booking
+	}
+	notify := func(intentId string, succeeded bool) *httptest.ResponseRecorder {
+		body, header, err := fake.Complete(intentId, succeeded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req, _ := http.NewRequest(http.MethodPost, "/payments/webhook", bytes.NewReader(body))
+		req.Header = header
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// A drop-in stays pending until the provider reports the payment
+	paid := book("Walkin")
+	assert.Equal(t, models.BookingPending, paid.Status)
+	if assert.NotNil(t, paid.Payment) {
+		assert.Equal(t, 1500, paid.Payment.Amount)
+		assert.Equal(t, payments.StatusRequiresPayment, paid.Payment.Status)
+	}
+
+	w := notify(paid.Payment.IntentId, true)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var confirmed models.Booking
+	if err := json.Unmarshal(w.Body.Bytes(), &confirmed); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, models.BookingConfirmed, confirmed.Status)
+	assert.Equal(t, payments.StatusSucceeded, confirmed.Payment.Status)
+
+	// Replayed events change nothing, and unsigned ones are rejected
+	assert.Equal(t, http.StatusNoContent, notify(paid.Payment.IntentId, true).Code)
+	req, _ := http.NewRequest(http.MethodPost, "/payments/webhook", strings.NewReader(`{"type": "payment.succeeded", "intent_id": "`+paid.Payment.IntentId+`"}`))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// A free cancellation refunds the payment
+	req, _ = http.NewRequest(http.MethodPost, "/bookings/"+strconv.Itoa(paid.ID)+"/cancel", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var cancelled models.Booking
+	if err := json.Unmarshal(w.Body.Bytes(), &cancelled); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, payments.StatusRefunded, cancelled.Payment.Status)
+	assert.Equal(t, 1500, cancelled.Payment.Refunded)
+	assert.Equal(t, 1500, fake.Refunded(paid.Payment.IntentId))
+
+	// A failed payment cancels the booking
+	failed := book("Declined")
+	w = notify(failed.Payment.IntentId, false)
+	assert.Equal(t, http.StatusOK, w.Code)
+	stored, _ := database.FindItemByID(database.Bookings, failed.ID)
+	assert.Equal(t, models.BookingCancelled, stored.(*models.Booking).Status)
+	assert.Equal(t, payments.StatusFailed, stored.(*models.Booking).Payment.Status)
+
+	// A payment succeeding after it failed is refunded, once
+	w = notify(failed.Payment.IntentId, true)
+	assert.Equal(t, http.StatusOK, w.Code)
+	stored, _ = database.FindItemByID(database.Bookings, failed.ID)
+	assert.Equal(t, models.BookingCancelled, stored.(*models.Booking).Status)
+	assert.Equal(t, payments.StatusRefunded, stored.(*models.Booking).Payment.Status)
+	assert.Equal(t, 1500, fake.Refunded(failed.Payment.IntentId))
+	assert.Equal(t, http.StatusNoContent, notify(failed.Payment.IntentId, true).Code)
+	assert.Equal(t, 1500, fake.Refunded(failed.Payment.IntentId))
+
+	// Cancelling a pending booking abandons its payment intent
+	abandoned := book("Abandoned")
+	req, _ = http.NewRequest(http.MethodPost, "/bookings/"+strconv.Itoa(abandoned.ID)+"/cancel", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	_, _, err := fake.Complete(abandoned.Payment.IntentId, true)
+	assert.ErrorIs(t, err, payments.ErrCancel)
+}
+
+// refundHook is a payment provider calling during at the start of each refund, while it is in flight.
+type refundHook struct {
+	*payments.Fake
+	during func()
+}
+
+func (hook refundHook) Refund(ctx context.Context, intentId string, amount int) error {
+	hook.during()
+	return hook.Fake.Refund(ctx, intentId, amount)
+}
+
+func TestPaidBookingRefundedOnce(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.POST("/bookings", PostBookings)
+	router.POST("/bookings/:id/cancel", CancelBooking)
+	router.POST("/bookings/:id/confirm", ConfirmBooking)
+	router.POST("/payments/webhook", PaymentWebhook)
+
+	fake := payments.NewFake([]byte("secret"))
+	var during func()
+	payments.Default = refundHook{fake, func() { during() }}
+	defer func() { payments.Default = payments.NewFake(nil) }()
+
+	start := time.Date(2023, 11, 8, 18, 0, 0, 0, time.UTC)
+	class := database.CreateClass(models.CreateClass{Name: "Refunded", StudioId: 1, StartDate: start, EndDate: start.Add(time.Hour), Capacity: 10, Price: 1500})
+	database.Classes = append(database.Classes, class)
+
+	now = func() time.Time { return start.Add(-48 * time.Hour) }
+	defer func() { now = time.Now }()
+
+	post := func(path string, body []byte, header http.Header) int {
+		req, _ := http.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+		if header != nil {
+			req.Header = header
+		}
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	body, _ := json.Marshal(models.CreateBooking{Name: "Twice", ClassId: class.ID, Date: start})
+	assert.Equal(t, http.StatusCreated, post("/bookings", body, nil))
+	booking := database.Bookings[len(database.Bookings)-1]
+	payload, header, err := fake.Complete(booking.Payment.IntentId, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, post("/payments/webhook", payload, header))
+	path := "/bookings/" + strconv.Itoa(booking.ID)
+
+	// While the refund is in flight, the booking cannot be cancelled again nor confirmed
+	during = func() {
+		stored, _ := database.FindItemByID(database.Bookings, booking.ID)
+		assert.Equal(t, payments.StatusRefunding, stored.(*models.Booking).Payment.Status)
+		assert.Equal(t, http.StatusConflict, post(path+"/cancel", nil, nil))
+		assert.Equal(t, http.StatusConflict, post(path+"/confirm", nil, nil))
+	}
+	assert.Equal(t, http.StatusOK, post(path+"/cancel", nil, nil))
+	assert.Equal(t, 1500, fake.Refunded(booking.Payment.IntentId))
+	stored, _ := database.FindItemByID(database.Bookings, booking.ID)
+	assert.Equal(t, models.BookingCancelled, stored.(*models.Booking).Status)
+	assert.Equal(t, payments.StatusRefunded, stored.(*models.Booking).Payment.Status)
+
+	// When the provider fails, the claim is released and the booking stays paid
+	assert.Equal(t, http.StatusCreated, post("/bookings", body, nil))
+	booking = database.Bookings[len(database.Bookings)-1]
+	payload, header, _ = fake.Complete(booking.Payment.IntentId, true)
+	assert.Equal(t, http.StatusOK, post("/payments/webhook", payload, header))
+	during = func() { fake.Refund(context.Background(), booking.Payment.IntentId, 1500) }
+	assert.Equal(t, http.StatusBadGateway, post("/bookings/"+strconv.Itoa(booking.ID)+"/cancel", nil, nil))
+	stored, _ = database.FindItemByID(database.Bookings, booking.ID)
+	assert.Equal(t, models.BookingConfirmed, stored.(*models.Booking).Status)
+	assert.Equal(t, payments.StatusSucceeded, stored.(*models.Booking).Payment.Status)
+}
+
+func TestUnpaidBookingCannotBeConfirmedOrMoved(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.POST("/bookings", PostBookings)
+	router.PUT("/bookings/:id", UpdateBooking)
+	router.POST("/bookings/:id/confirm", ConfirmBooking)
+
+	start := time.Date(2023, 11, 7, 18, 0, 0, 0, time.UTC)
+	class := database.CreateClass(models.CreateClass{Name: "Priced", StudioId: 1, StartDate: start, EndDate: start.Add(time.Hour), Capacity: 10, Price: 1500})
+	database.Classes = append(database.Classes, class)
+	cheaper := database.CreateClass(models.CreateClass{Name: "Cheaper", StudioId: 1, StartDate: start, EndDate: start.Add(time.Hour), Capacity: 10, Price: 500})
+	database.Classes = append(database.Classes, cheaper)
+
+	now = func() time.Time { return start.Add(-48 * time.Hour) }
+	defer func() { now = time.Now }()
+
+	body, _ := json.Marshal(models.CreateBooking{Name: "Walkin", ClassId: class.ID, Date: start})
+	req, _ := http.NewRequest(http.MethodPost, "/bookings", bytes.NewReader(body))
+	req.Header.Add("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var pending models.Booking
+	if err := json.Unmarshal(w.Body.Bytes(), &pending); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, models.BookingPending, pending.Status)
+
+	// Staff cannot confirm a booking whose payment has not succeeded
+	req, _ = http.NewRequest(http.MethodPost, "/bookings/"+strconv.Itoa(pending.ID)+"/confirm", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	// Nor move it to a class with another price
+	body, _ = json.Marshal(models.UpdateBooking{Name: "Walkin", ClassId: cheaper.ID, Date: start})
+	req, _ = http.NewRequest(http.MethodPut, "/bookings/"+strconv.Itoa(pending.ID), bytes.NewReader(body))
+	req.Header.Add("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	stored, _ := database.FindItemByID(database.Bookings, pending.ID)
+	assert.Equal(t, models.BookingPending, stored.(*models.Booking).Status)
+	assert.Equal(t, class.ID, stored.(*models.Booking).ClassId)
+
+	// A drop-in of a free class cannot move into a priced one without paying (402)
+	free := database.CreateClass(models.CreateClass{Name: "Free", StudioId: 1, StartDate: start, EndDate: start.Add(time.Hour), Capacity: 10})
+	database.Classes = append(database.Classes, free)
+	dropIn := database.CreateBooking(models.CreateBooking{Name: "Guest", ClassId: free.ID, Date: start})
+	database.Bookings = append(database.Bookings, dropIn)
+	body, _ = json.Marshal(models.UpdateBooking{Name: "Guest", ClassId: class.ID, Date: start})
+	req, _ = http.NewRequest(http.MethodPut, "/bookings/"+strconv.Itoa(dropIn.ID), bytes.NewReader(body))
+	req.Header.Add("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusPaymentRequired, w.Code)
+
+	stored, _ = database.FindItemByID(database.Bookings, dropIn.ID)
+	assert.Equal(t, free.ID, stored.(*models.Booking).ClassId)
 }
 
 func TestPostBookingsInvalidClassID(t *testing.T) {
 	// Create a test Gin router
-	router := gin.Default()
+	router := newRouter(t)
 	router.POST("/bookings", PostBookings)
 
 	// Define a new booking with an invalid ClassId (ClassId 50 does not exist)
@@ -194,7 +993,7 @@ func TestPostBookingsInvalidClassID(t *testing.T) {
 
 func TestUpdateBookingInvalidClassID(t *testing.T) {
 	// Create a test Gin router
-	router := gin.Default()
+	router := newRouter(t)
 	router.PUT("/bookings/:id", UpdateBooking)
 
 	// Define an updated booking with an invalid ClassId (ClassId 50 does not exist)
@@ -216,4 +1015,287 @@ func TestUpdateBookingInvalidClassID(t *testing.T) {
 
 	// Assert that the HTTP status code is Not Found Request (404)
 	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+func TestGetBookingByIDIfNoneMatch(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.GET("/bookings/:id", GetBookingByID)
+
+	// Create a GET request to read the current ETag of booking 2
+	req, _ := http.NewRequest(http.MethodGet, "/bookings/2", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	tag := w.Header().Get("ETag")
+	assert.NotEmpty(t, tag)
+
+	// Sending the ETag back returns Not Modified (304)
+	req, _ = http.NewRequest(http.MethodGet, "/bookings/2", nil)
+	req.Header.Add("If-None-Match", tag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+}
+
+func TestUpdateBookingIfMatch(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.GET("/bookings/:id", GetBookingByID)
+	router.PUT("/bookings/:id", UpdateBooking)
+
+	// Read the current ETag of booking 2
+	req, _ := http.NewRequest(http.MethodGet, "/bookings/2", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	tag := w.Header().Get("ETag")
+
+	var updatedBooking = models.UpdateBooking{
+		Name:    "MartinUpdated",
+		ClassId: 2,
+		Date:    time.Date(2023, 10, 7, 20, 0, 0, 0, time.UTC),
+	}
+	updatedBookingJSON, _ := json.Marshal(updatedBooking)
+
+	// The first update with the current ETag succeeds
+	req, _ = http.NewRequest(http.MethodPut, "/bookings/2", bytes.NewBuffer(updatedBookingJSON))
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("If-Match", tag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// The same ETag is now stale and the update is rejected with Precondition Failed (412)
+	req, _ = http.NewRequest(http.MethodPut, "/bookings/2", bytes.NewBuffer(updatedBookingJSON))
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("If-Match", tag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+}
+
+func TestUpdateBookingIfMatchConcurrent(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.GET("/bookings/:id", GetBookingByID)
+	router.PUT("/bookings/:id", UpdateBooking)
+
+	// Read the current ETag of booking 3
+	req, _ := http.NewRequest(http.MethodGet, "/bookings/3", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	tag := w.Header().Get("ETag")
+	var read models.Booking
+	if err := json.Unmarshal(w.Body.Bytes(), &read); err != nil {
+		t.Fatal(err)
+	}
+
+	// Two updates are sent at once with the same ETag
+	type response struct {
+		code    int
+		booking models.Booking
+	}
+	responses := make(chan response, 2)
+	for _, name := range []string{"JoaquinFirst", "JoaquinSecond"} {
+		body, _ := json.Marshal(models.UpdateBooking{Name: name, ClassId: read.ClassId, Date: read.Date})
+		go func() {
+			req, _ := http.NewRequest(http.MethodPut, "/bookings/3", bytes.NewReader(body))
+			req.Header.Add("Content-Type", "application/json")
+			req.Header.Add("If-Match", tag)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			var booking models.Booking
+			json.Unmarshal(w.Body.Bytes(), &booking)
+			responses <- response{w.Code, booking}
+		}()
+	}
+
+	// Only one of them is applied; the other gets Precondition Failed (412)
+	first, second := <-responses, <-responses
+	assert.ElementsMatch(t, []int{http.StatusOK, http.StatusPreconditionFailed}, []int{first.code, second.code})
+	applied := first.booking
+	if second.code == http.StatusOK {
+		applied = second.booking
+	}
+	stored, _ := database.FindItemByID(database.Bookings, 3)
+	assert.Equal(t, read.Version+1, stored.(*models.Booking).Version)
+	assert.Equal(t, applied.Name, stored.(*models.Booking).Name)
+}
+
+func TestGetBookingsFilter(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.GET("/bookings", GetBookings)
+
+	database.Bookings = append(database.Bookings, database.CreateBooking(models.CreateBooking{Name: "Filtered", ClassId: 2, Date: time.Date(2023, 10, 8, 20, 0, 0, 0, time.UTC)}))
+
+	req, _ := http.NewRequest(http.MethodGet, "/bookings?class_id=2&name=filtered&from=2023-10-08T00:00:00Z", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert that the HTTP status code is OK (200)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var bookings []models.Booking
+	err := json.Unmarshal(w.Body.Bytes(), &bookings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, bookings, 1)
+	assert.Equal(t, "Filtered", bookings[0].Name)
+
+	// A malformed filter returns Bad Request (400)
+	req, _ = http.NewRequest(http.MethodGet, "/bookings?from=yesterday", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestExportBookingsCSV(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.GET("/bookings/export", ExportBookings)
+
+	booking := database.CreateBooking(models.CreateBooking{Name: "Exported", ClassId: 3, Date: time.Date(2023, 10, 11, 11, 30, 0, 0, time.UTC)})
+	database.Bookings = append(database.Bookings, booking)
+
+	req, _ := http.NewRequest(http.MethodGet, "/bookings/export?name=Exported&tz=studio", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert that the HTTP status code is OK (200) and the body is a CSV download
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), `filename="bookings.csv"`)
+
+	records, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Rows are joined with their class and rendered in the studio zone (Montevideo, UTC-3)
+	assert.Equal(t, []string{"booking_id", "name", "class_id", "class_name", "class_start", "class_end", "date"}, records[0])
+	assert.Len(t, records, 2)
+	assert.Equal(t, "Exported", records[1][1])
+	assert.Equal(t, "Boxing", records[1][3])
+	assert.Equal(t, "2023-10-11T08:00:00-03:00", records[1][4])
+	assert.Equal(t, "2023-10-11T08:30:00-03:00", records[1][6])
+}
+
+func TestGetClassRosterXLSX(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.GET("/classes/:id/roster", GetClassRoster)
+
+	database.Bookings = append(database.Bookings, database.CreateBooking(models.CreateBooking{Name: "Roster", ClassId: 3, Date: time.Date(2023, 10, 11, 12, 0, 0, 0, time.UTC)}))
+
+	req, _ := http.NewRequest(http.MethodGet, "/classes/3/roster?format=xlsx&name=Roster", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert that the HTTP status code is OK (200) and the body is a workbook
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Disposition"), `filename="roster-3.xlsx"`)
+
+	archive, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sheet []byte
+	for _, file := range archive.File {
+		if file.Name == "xl/worksheets/sheet1.xml" {
+			reader, _ := file.Open()
+			sheet, _ = io.ReadAll(reader)
+			reader.Close()
+		}
+	}
+	assert.Contains(t, string(sheet), "<t xml:space=\"preserve\">Roster</t>")
+	assert.Contains(t, string(sheet), "<t xml:space=\"preserve\">Boxing</t>")
+}
+
+func TestGetClassRosterNotFound(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.GET("/classes/:id/roster", GetClassRoster)
+
+	req, _ := http.NewRequest(http.MethodGet, "/classes/999/roster", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert that the HTTP status code is Not Found (404)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRestoreBooking(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.POST("/bookings/:id/restore", RestoreBooking)
+
+	start := time.Date(2031, 7, 1, 18, 0, 0, 0, time.UTC)
+	deletedAt := start.Add(-48 * time.Hour)
+	class := database.CreateClass(models.CreateClass{Name: "Restored", StudioId: 1, StartDate: start, EndDate: start.Add(time.Hour), Capacity: 1})
+	gone := database.CreateClass(models.CreateClass{Name: "Gone", StudioId: 1, StartDate: start, EndDate: start.Add(time.Hour), Capacity: 1})
+	gone.DeletedAt = &deletedAt
+	database.Classes = append(database.Classes, class, gone)
+
+	// A member whose credit was refunded when the class was deleted, and spent since
+	member := database.CreateMember(models.CreateMember{Name: "Restorer"})
+	database.Members = append(database.Members, member)
+	credits.Purchase(member.ID, models.Plan{ID: 1, Kind: models.PlanPack, Credits: 1}, deletedAt)
+	booking := database.CreateBooking(models.CreateBooking{Name: "Restorer", ClassId: class.ID, Date: start})
+	booking.MemberId = member.ID
+	booking.Entitlement = models.EntitlementCredit
+	credits.Spend(booking, deletedAt)
+	credits.Refund(booking, deletedAt)
+	credits.Spend(models.Booking{ID: 9999, Name: "Restorer", MemberId: member.ID, Entitlement: models.EntitlementCredit}, deletedAt)
+	booking.DeletedAt = &deletedAt
+	booking.Version = 2
+	orphan := database.CreateBooking(models.CreateBooking{Name: "Walkin", ClassId: gone.ID, Date: start})
+	orphan.DeletedAt = &deletedAt
+	database.Bookings = append(database.Bookings, booking, orphan)
+
+	restore := func(id int) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodPost, "/bookings/"+strconv.Itoa(id)+"/restore", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// The class has to be restored first
+	w := restore(orphan.ID)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "Class is deleted")
+
+	// The member has to pay the credit again
+	assert.Equal(t, http.StatusPaymentRequired, restore(booking.ID).Code)
+
+	// And the session needs a free seat
+	credits.Purchase(member.ID, models.Plan{ID: 1, Kind: models.PlanPack, Credits: 1}, deletedAt)
+	taken := database.CreateBooking(models.CreateBooking{Name: "Walkin", ClassId: class.ID, Date: start})
+	database.Bookings = append(database.Bookings, taken)
+	w = restore(booking.ID)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "Class is full")
+
+	_, index := database.FindItemByID(database.Bookings, taken.ID)
+	database.Bookings[index].Status = models.BookingCancelled
+	w = restore(booking.ID)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var restored models.Booking
+	if err := json.Unmarshal(w.Body.Bytes(), &restored); err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, restored.DeletedAt)
+	assert.Equal(t, 3, restored.Version)
+	assert.Equal(t, 0, credits.Balance(member.ID))
+	assert.Equal(t, events.BookingRestored, database.Outbox[len(database.Outbox)-1].Type)
+
+	// A live booking cannot be restored
+	assert.Equal(t, http.StatusConflict, restore(booking.ID).Code)
 }
\ No newline at end of file
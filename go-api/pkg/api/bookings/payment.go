@@ -0,0 +1,104 @@
+package bookings
+
+import (
+	"io"
+	"net/http"
+	"go-api/pkg/models"
+	"go-api/pkg/mockDatabase"
+	"go-api/pkg/events"
+	"go-api/pkg/payments"
+	"go-api/pkg/api/etag"
+	"github.com/gin-gonic/gin"
+)
+
+/**
+ * @brief PaymentWebhook applies the signed payment events posted by the payment provider.
+ *
+ * A succeeded payment confirms its pending booking; a failed one cancels it
+ * and frees its seat. A payment succeeding after it failed, or after its
+ * booking was cancelled, is refunded in full. Events already applied, or for unknown payments, are acknowledged
+ * with 204 so that the provider stops sending them.
+ *
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func PaymentWebhook(c *gin.Context) {
+	payload, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid payment event"})
+		return
+	}
+
+	event, err := payments.Default.ParseEvent(payload, c.Request.Header)
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid payment event"})
+		return
+	}
+
+	var booking models.Booking
+	applied, released := false, ""
+	err = database.Transaction(func(tx *database.Tx) error {
+		index := findIntent(event.IntentId)
+		if index < 0 {
+			return nil
+		}
+
+		// The booking is read under the lock, so that an event delivered twice is applied once.
+		booking = database.Bookings[index]
+		payment := *booking.Payment
+		booking.Payment = &payment
+		switch {
+		case payment.Status == payments.StatusRequiresPayment && booking.Status == models.BookingPending && event.Type == payments.EventSucceeded:
+			payment.Status = payments.StatusSucceeded
+			booking.Transition(models.BookingConfirmed, now())
+			booking.Version++
+			applied = true
+			return store(c, tx, index, booking, false, events.BookingConfirmed)
+
+		case payment.Status == payments.StatusRequiresPayment && booking.Status == models.BookingPending && event.Type == payments.EventFailed:
+			payment.Status = payments.StatusFailed
+			booking.Transition(models.BookingCancelled, now())
+			booking.Version++
+			applied = true
+			return store(c, tx, index, booking, true, events.BookingCancelled)
+
+		case (payment.Status == payments.StatusFailed || payment.Status == payments.StatusCancelled) && event.Type == payments.EventSucceeded:
+			// Claimed, so that the payment is refunded once however often the event is delivered.
+			released = payment.Status
+			payment.Status = payments.StatusRefunding
+			database.Bookings[index].Payment = &payment
+		}
+		return nil
+	})
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Could not save Booking"})
+		return
+	}
+
+	if released != "" {
+		booking, err = refund(c, booking, booking.Payment.Amount, released, func(booking *models.Booking) (string, bool) {
+			return events.BookingUpdated, false
+		})
+		if err != nil {
+			respond(c, err)
+			return
+		}
+		applied = true
+	}
+	if !applied {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	c.Header("ETag", etag.For("booking", booking.ID, booking.Version, ""))
+	c.IndentedJSON(http.StatusOK, booking)
+}
+
+// findIntent returns the index of the booking paid with a payment intent, -1 when there is none.
+func findIntent(intentId string) int {
+	for index, stored := range database.Bookings {
+		if stored.Payment != nil && stored.Payment.IntentId == intentId {
+			return index
+		}
+	}
+	return -1
+}
\ No newline at end of file
@@ -0,0 +1,114 @@
+package bookings
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+	"go-api/pkg/models"
+)
+
+// Late cancellation penalties.
+const (
+	PenaltyNone   = "none"
+	PenaltyCredit = "credit"
+	PenaltyFee    = "fee"
+)
+
+// CancellationPolicy decides what cancelling a booking costs.
+//
+// Cancelling at least FreeWindow before the session starts is free. Later
+// cancellations are late and cost LatePenalty. Once the session has started
+// the booking can no longer be cancelled.
+type CancellationPolicy struct {
+	FreeWindow time.Duration `json:"free_window"`
+	// LatePenalty is "none", "credit" (the class credit is forfeited) or "fee".
+	LatePenalty string `json:"late_penalty"`
+	// LateFee is charged for late cancellations when LatePenalty is "fee", in cents.
+	LateFee int `json:"late_fee"`
+}
+
+// Policy is the cancellation policy applied by CancelBooking.
+var Policy = DefaultCancellationPolicy()
+
+// now is the clock cancellations are measured against, replaced in tests.
+var now = time.Now
+
+/**
+ * @brief DefaultCancellationPolicy allows free cancellations up to 12 hours before the session, and late ones without penalty.
+ */
+func DefaultCancellationPolicy() CancellationPolicy {
+	return CancellationPolicy{FreeWindow: 12 * time.Hour, LatePenalty: PenaltyNone}
+}
+
+// UnmarshalJSON accepts the free window as a Go duration string such as "12h".
+func (policy *CancellationPolicy) UnmarshalJSON(data []byte) error {
+	raw := struct {
+		FreeWindow  string `json:"free_window"`
+		LatePenalty string `json:"late_penalty"`
+		LateFee     int    `json:"late_fee"`
+	}{FreeWindow: policy.FreeWindow.String(), LatePenalty: policy.LatePenalty, LateFee: policy.LateFee}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	window, err := time.ParseDuration(raw.FreeWindow)
+	if err != nil {
+		return err
+	}
+
+	switch raw.LatePenalty {
+	case PenaltyNone, PenaltyCredit, PenaltyFee:
+	default:
+		return fmt.Errorf("unknown late cancellation penalty %q", raw.LatePenalty)
+	}
+
+	policy.FreeWindow = window
+	policy.LatePenalty = raw.LatePenalty
+	policy.LateFee = raw.LateFee
+	return nil
+}
+
+/**
+ * @brief Cancel applies the policy to a booking cancelled at the given time.
+ *
+ * @param booking models.Booking: The booking; its date is the start of the session.
+ * @param at time.Time: When the booking is cancelled.
+ * @return *models.Cancellation: The outcome, nil once the session has started.
+ */
+func (policy CancellationPolicy) Cancel(booking models.Booking, at time.Time) *models.Cancellation {
+	if !at.Before(booking.Date) {
+		return nil
+	}
+
+	cancellation := &models.Cancellation{CancelledAt: at.UTC(), Outcome: "free", Penalty: PenaltyNone}
+	if booking.Date.Sub(at) >= policy.FreeWindow {
+		return cancellation
+	}
+
+	cancellation.Outcome = "late"
+	cancellation.Penalty = policy.LatePenalty
+	if policy.LatePenalty == PenaltyFee {
+		cancellation.Fee = policy.LateFee
+	}
+	return cancellation
+}
+
+/**
+ * @brief Refund returns how much of a payment is given back for a cancellation.
+ *
+ * Free cancellations and late ones without penalty are refunded in full. A
+ * late fee is kept from the refund, and a forfeited class is not refunded.
+ *
+ * @param paid int: The amount paid, in cents.
+ * @param cancellation models.Cancellation: The outcome of Cancel.
+ * @return int: The amount to refund, in cents.
+ */
+func (policy CancellationPolicy) Refund(paid int, cancellation models.Cancellation) int {
+	switch {
+	case cancellation.Outcome == "free" || cancellation.Penalty == PenaltyNone:
+		return paid
+	case cancellation.Penalty == PenaltyFee && paid > cancellation.Fee:
+		return paid - cancellation.Fee
+	}
+	return 0
+}
\ No newline at end of file
@@ -0,0 +1,207 @@
+package bookings
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+	"go-api/pkg/credits"
+	"go-api/pkg/models"
+	"go-api/pkg/payments"
+	"go-api/pkg/api/audit"
+	"go-api/pkg/api/etag"
+	"go-api/pkg/mockDatabase"
+	"go-api/pkg/events"
+	"github.com/gin-gonic/gin"
+)
+
+// ErrClassFull is returned by Reinstate when the session of a booking holding a seat has no free seat left.
+var ErrClassFull = errors.New("class is full")
+
+// ErrPaidBookings is returned by DeleteWithClass when a booking of the class was paid after Withdraw.
+var ErrPaidBookings = errors.New("class has paid bookings")
+
+/**
+ * @brief Withdraw cancels the bookings of a class about to be deleted that were paid through the payment provider.
+ *
+ * Call it before the transaction deleting the class, then DeleteWithClass in
+ * it. Those bookings cannot be charged again when the class is restored, so
+ * open ones are cancelled for free, each in its own transaction (see cancel):
+ * a succeeded payment is refunded in full and a pending one abandoned. Their
+ * seats do not go to the waitlist.
+ *
+ * @param c *gin.Context: The request deleting the class.
+ * @param classId int: The class being deleted.
+ * @return error: refundFailed when the payment provider could not refund a payment.
+ */
+func Withdraw(c *gin.Context, classId int) error {
+	var paid []int
+	database.Transaction(func(tx *database.Tx) error {
+		for _, booking := range database.Bookings {
+			if booking.ClassId == classId && booking.DeletedAt == nil && booking.Payment != nil && len(models.BookingTransitions[booking.Status]) > 0 {
+				paid = append(paid, booking.ID)
+			}
+		}
+		return nil
+	})
+
+	for _, id := range paid {
+		_, err := cancel(c, id, "", false, func(booking models.Booking, at time.Time) (*models.Cancellation, error) {
+			return &models.Cancellation{CancelledAt: at, Outcome: "free", Penalty: PenaltyNone}, nil
+		})
+		// Bookings closed or being refunded since are left to DeleteWithClass.
+		if err != nil && !errors.As(err, new(conflict)) && !errors.Is(err, database.ErrNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+/**
+ * @brief DeleteWithClass soft-deletes the bookings of a class in the transaction deleting it.
+ *
+ * Each booking records booking.deleted, and the class credit of open
+ * bookings is refunded; Reinstate takes it again. An open booking still
+ * waiting for its payment is cancelled for free, the payment completing
+ * anyway being refunded by PaymentWebhook; one paid since Withdraw stops the
+ * deletion.
+ *
+ * @param c *gin.Context: The request deleting the class, recorded in the audit log.
+ * @param tx *database.Tx: The transaction deleting the class.
+ * @param classId int: The class being deleted.
+ * @param at time.Time: When the class is deleted.
+ * @return error: ErrPaidBookings when an open booking has a payment to refund.
+ */
+func DeleteWithClass(c *gin.Context, tx *database.Tx, classId int, at time.Time) error {
+	for index, current := range database.Bookings {
+		if current.ClassId != classId || current.DeletedAt != nil {
+			continue
+		}
+
+		booking := current
+		booking.DeletedAt = &at
+		booking.Version++
+		open := len(models.BookingTransitions[current.Status]) > 0
+		if open && booking.Payment != nil {
+			if booking.Payment.Status != payments.StatusRequiresPayment {
+				return ErrPaidBookings
+			}
+			payment := *booking.Payment
+			payment.Status = payments.StatusCancelled
+			booking.Payment = &payment
+			booking.Cancellation = &models.Cancellation{CancelledAt: at, Outcome: "free", Penalty: PenaltyNone}
+			booking.Transition(models.BookingCancelled, at)
+			if err := tx.Record(events.BookingCancelled, booking.ID, booking); err != nil {
+				return err
+			}
+		}
+		if err := tx.Record(events.BookingDeleted, booking.ID, booking); err != nil {
+			return err
+		}
+		if err := audit.Record(c, tx, events.BookingDeleted, booking.ID, current, booking); err != nil {
+			return err
+		}
+		database.Bookings[index] = booking
+		if open {
+			credits.Refund(booking, at)
+		}
+	}
+	return nil
+}
+
+/**
+ * @brief Reinstate brings back a soft-deleted booking in the transaction restoring it.
+ *
+ * The booking is restored as it was deleted, provided its session still has
+ * a seat for it and, for open bookings paid with a class credit, the member
+ * can pay again: the credit refunded by DeleteWithClass is taken anew, or
+ * the member's current membership covers the booking. The booking's class
+ * must be live.
+ *
+ * @param c *gin.Context: The restoring request, recorded in the audit log.
+ * @param tx *database.Tx: The transaction restoring the booking.
+ * @param booking models.Booking: The stored, soft-deleted booking.
+ * @return models.Booking: The restored booking.
+ * @return error: ErrClassFull, or credits.ErrNoCredits when the member has neither credits nor a membership.
+ */
+func Reinstate(c *gin.Context, tx *database.Tx, booking models.Booking) (models.Booking, error) {
+	index, err := database.Locate(database.Bookings, booking.ID)
+	if err != nil {
+		return booking, err
+	}
+
+	current := database.Bookings[index]
+	restored := current
+	restored.DeletedAt = nil
+	restored.Version++
+	if restored.HoldsSeat() && !hasFreeSeat(restored.ClassId, restored.Date) {
+		return current, ErrClassFull
+	}
+	open := len(models.BookingTransitions[restored.Status]) > 0
+	if open && restored.Entitlement == models.EntitlementCredit {
+		entitlement, err := credits.Entitlement(restored.MemberId, now())
+		if err != nil {
+			return current, err
+		}
+		restored.Entitlement = entitlement
+	}
+
+	if err := tx.Record(events.BookingRestored, restored.ID, restored); err != nil {
+		return current, err
+	}
+	if err := audit.Record(c, tx, events.BookingRestored, restored.ID, current, restored); err != nil {
+		return current, err
+	}
+	database.Bookings[index] = restored
+	if open {
+		credits.Spend(restored, now())
+	}
+	return restored, nil
+}
+
+/**
+ * @brief RestoreBooking brings back a booking soft-deleted with its class.
+ *
+ * RestoreClass leaves deleted the bookings it cannot reinstate; once their
+ * session has a seat, or their member has credits again, they are restored
+ * one by one here (see Reinstate).
+ *
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func RestoreBooking(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var restored models.Booking
+	err = database.Transaction(func(tx *database.Tx) error {
+		index, err := database.Locate(database.Bookings, id)
+		if err != nil {
+			return err
+		}
+
+		current := database.Bookings[index]
+		if err := etag.Check(c.GetHeader("If-Match"), "booking", current.ID, current.Version); err != nil {
+			return err
+		}
+		if current.DeletedAt == nil {
+			return conflict("Booking is not deleted")
+		}
+		if class, _ := database.FindItemByID(database.Classes, current.ClassId); class == nil {
+			return conflict("Class is deleted")
+		}
+
+		restored, err = Reinstate(c, tx, current)
+		return err
+	})
+	if err != nil {
+		respond(c, err)
+		return
+	}
+
+	c.Header("ETag", etag.For("booking", restored.ID, restored.Version, ""))
+	c.IndentedJSON(http.StatusOK, restored)
+}
\ No newline at end of file
@@ -0,0 +1,442 @@
+package bookings
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+	"go-api/pkg/credits"
+	"go-api/pkg/models"
+	"go-api/pkg/payments"
+	"go-api/pkg/api/audit"
+	"go-api/pkg/api/etag"
+	"go-api/pkg/mockDatabase"
+	"go-api/pkg/events"
+	"github.com/gin-gonic/gin"
+)
+
+// conflict is returned inside a transaction when the stored booking cannot change as requested; it is the message of the 409 response.
+type conflict string
+
+func (err conflict) Error() string {
+	return string(err)
+}
+
+/**
+ * @brief ConfirmBooking confirms a pending or waitlisted booking.
+ *
+ * A waitlisted booking is only confirmed while its session has a free seat,
+ * and a booking with a payment only once the payment has succeeded.
+ *
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func ConfirmBooking(c *gin.Context) {
+	id, ok := bookingID(c)
+	if !ok {
+		return
+	}
+
+	saveTransition(c, id, models.BookingConfirmed, func(booking *models.Booking, at time.Time) (string, error) {
+		if booking.Payment != nil && booking.Payment.Status != payments.StatusSucceeded {
+			return "", conflict("Booking has not been paid")
+		}
+		if booking.Status != models.BookingWaitlisted {
+			return events.BookingConfirmed, nil
+		}
+		if !hasFreeSeat(booking.ClassId, booking.Date) {
+			return "", ErrClassFull
+		}
+		return events.BookingPromoted, nil
+	})
+}
+
+/**
+ * @brief CancelBooking cancels a booking by its ID.
+ *
+ * The booking is kept with status "cancelled" and the outcome of the
+ * cancellation Policy: free, or late with its penalty. Bookings whose session
+ * has started cannot be cancelled. The seat freed goes to the first
+ * waitlisted booking of the session, and the class credit or payment spent
+ * on the booking is refunded as the policy allows (see CancellationPolicy.Refund).
+ * Through DELETE, booking.deleted is recorded after booking.cancelled for the
+ * subscribers of the event DELETE emitted before bookings were kept.
+ *
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func CancelBooking(c *gin.Context) {
+	id, ok := bookingID(c)
+	if !ok {
+		return
+	}
+
+	var aliases []string
+	if c.Request.Method == http.MethodDelete {
+		aliases = append(aliases, events.BookingDeleted)
+	}
+	booking, err := cancel(c, id, c.GetHeader("If-Match"), true, func(booking models.Booking, at time.Time) (*models.Cancellation, error) {
+		cancellation := Policy.Cancel(booking, at)
+		if cancellation == nil {
+			return nil, conflict("Class has already started")
+		}
+		if !booking.HoldsSeat() {
+			// Leaving the waitlist never costs anything.
+			cancellation.Outcome = "free"
+			cancellation.Penalty = PenaltyNone
+			cancellation.Fee = 0
+		}
+		return cancellation, nil
+	}, aliases...)
+	if err != nil {
+		respond(c, err)
+		return
+	}
+
+	c.Header("ETag", etag.For("booking", booking.ID, booking.Version, ""))
+	c.IndentedJSON(http.StatusOK, booking)
+}
+
+/**
+ * @brief AttendBooking marks a confirmed booking as attended.
+ *
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func AttendBooking(c *gin.Context) {
+	id, ok := bookingID(c)
+	if !ok {
+		return
+	}
+
+	saveTransition(c, id, models.BookingAttended, func(booking *models.Booking, at time.Time) (string, error) {
+		return events.BookingAttended, nil
+	})
+}
+
+/**
+ * @brief MarkNoShow marks a confirmed booking whose member did not come.
+ *
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func MarkNoShow(c *gin.Context) {
+	id, ok := bookingID(c)
+	if !ok {
+		return
+	}
+
+	saveTransition(c, id, models.BookingNoShow, func(booking *models.Booking, at time.Time) (string, error) {
+		return events.BookingNoShow, nil
+	})
+}
+
+// bookingID parses the booking ID of the request, writing 400 when it is invalid.
+func bookingID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return 0, false
+	}
+	return id, true
+}
+
+/**
+ * @brief locateBooking finds the booking of a request under the database lock.
+ *
+ * Call it inside the transaction changing the booking: the If-Match of the
+ * request is checked against the stored version, so that two requests sent
+ * with the same ETag cannot both change it. A booking whose payment is being
+ * refunded cannot change until the refund is recorded.
+ *
+ * @param id int: The booking ID.
+ * @param ifMatch string: The If-Match header of the request.
+ * @return int: The index of the booking in database.Bookings.
+ * @return error: database.ErrNotFound for missing and soft-deleted bookings, etag.ErrModified or a conflict.
+ */
+func locateBooking(id int, ifMatch string) (int, error) {
+	index, err := database.Locate(database.Bookings, id)
+	if err != nil {
+		return index, err
+	}
+
+	booking := database.Bookings[index]
+	if booking.DeletedAt != nil {
+		return index, database.ErrNotFound
+	}
+	if err := etag.Check(ifMatch, "booking", booking.ID, booking.Version); err != nil {
+		return index, err
+	}
+	if booking.Payment != nil && booking.Payment.Status == payments.StatusRefunding {
+		return index, conflict("Booking is being refunded")
+	}
+	return index, nil
+}
+
+/**
+ * @brief saveTransition changes the status of a booking and records the event.
+ *
+ * The booking is read again under the database lock, checked against the
+ * If-Match of the request and BookingTransitions, and change builds the new
+ * booking from the stored one.
+ *
+ * @param c *gin.Context: The request changing the booking.
+ * @param id int: The booking ID.
+ * @param to string: The new status.
+ * @param change func(booking *models.Booking, at time.Time) (string, error): Applies the change to a copy of the stored booking and returns the event to record, or a conflict refusing it.
+ * @param aliases ...string: More events recorded for the same change, e.g. the deprecated booking.deleted.
+ */
+func saveTransition(c *gin.Context, id int, to string, change func(booking *models.Booking, at time.Time) (string, error), aliases ...string) {
+	var booking models.Booking
+	err := database.Transaction(func(tx *database.Tx) error {
+		index, err := locateBooking(id, c.GetHeader("If-Match"))
+		if err != nil {
+			return err
+		}
+
+		booking = database.Bookings[index]
+		if !booking.CanTransition(to) {
+			return conflict("Booking cannot change from " + booking.Status + " to " + to)
+		}
+
+		at := now()
+		eventType, err := change(&booking, at)
+		if err != nil {
+			return err
+		}
+		freed := booking.HoldsSeat() && to == models.BookingCancelled
+		booking.Transition(to, at)
+		booking.Version++
+		return store(c, tx, index, booking, freed, eventType, aliases...)
+	})
+	if err != nil {
+		respond(c, err)
+		return
+	}
+
+	c.Header("ETag", etag.For("booking", booking.ID, booking.Version, ""))
+	c.IndentedJSON(http.StatusOK, booking)
+}
+
+/**
+ * @brief cancel cancels a booking, refunding its payment through the payment provider.
+ *
+ * A refund due is claimed first: the payment is set "refunding" in one
+ * transaction, so that no other request changes the booking or refunds it
+ * again, then refunded outside the lock, and the cancellation recorded in a
+ * second transaction (see refund). A payment still to be made is abandoned.
+ *
+ * @param c *gin.Context: The request cancelling the booking.
+ * @param id int: The booking ID.
+ * @param ifMatch string: The If-Match header checked against the booking.
+ * @param freed bool: Whether the seat freed goes to the waitlist of the session.
+ * @param decide func(booking models.Booking, at time.Time) (*models.Cancellation, error): The outcome of the cancellation of the stored booking, or an error refusing it.
+ * @param aliases ...string: More events recorded with booking.cancelled.
+ * @return models.Booking: The cancelled booking.
+ * @return error: The error refusing the cancellation, or refundFailed.
+ */
+func cancel(c *gin.Context, id int, ifMatch string, freed bool, decide func(booking models.Booking, at time.Time) (*models.Cancellation, error), aliases ...string) (models.Booking, error) {
+	var booking models.Booking
+	var cancellation *models.Cancellation
+	abandoned, refunded := "", 0
+	at := now()
+	err := database.Transaction(func(tx *database.Tx) error {
+		index, err := locateBooking(id, ifMatch)
+		if err != nil {
+			return err
+		}
+
+		booking = database.Bookings[index]
+		if !booking.CanTransition(models.BookingCancelled) {
+			return conflict("Booking cannot change from " + booking.Status + " to " + models.BookingCancelled)
+		}
+		if cancellation, err = decide(booking, at); err != nil {
+			return err
+		}
+
+		if booking.Payment != nil {
+			payment := *booking.Payment
+			booking.Payment = &payment
+			switch payment.Status {
+			case payments.StatusRequiresPayment:
+				payment.Status = payments.StatusCancelled
+				abandoned = payment.IntentId
+			case payments.StatusSucceeded:
+				if refunded = Policy.Refund(payment.Amount, *cancellation); refunded > 0 {
+					payment.Status = payments.StatusRefunding
+					database.Bookings[index].Payment = &payment
+					return nil
+				}
+			}
+		}
+		freed = freed && booking.HoldsSeat()
+		booking.Cancellation = cancellation
+		booking.Transition(models.BookingCancelled, at)
+		booking.Version++
+		return store(c, tx, index, booking, freed, events.BookingCancelled, aliases...)
+	})
+	if err != nil {
+		return booking, err
+	}
+
+	if abandoned != "" {
+		// A payment completing anyway is refunded by PaymentWebhook.
+		payments.Default.Cancel(c.Request.Context(), abandoned)
+	}
+	if refunded == 0 {
+		return booking, nil
+	}
+	return refund(c, booking, refunded, payments.StatusSucceeded, func(booking *models.Booking) (string, bool) {
+		freed = freed && booking.HoldsSeat()
+		booking.Cancellation = cancellation
+		booking.Transition(models.BookingCancelled, at)
+		return events.BookingCancelled, freed
+	}, aliases...)
+}
+
+/**
+ * @brief refund refunds a payment claimed by a transaction that set it "refunding", then records the refund.
+ *
+ * The refund is recorded in a new transaction, with the change apply makes
+ * to the booking stored. When the provider fails, the claim is released: the
+ * payment gets back the status it had, so that it can be refunded again.
+ *
+ * @param c *gin.Context: The request refunding the payment.
+ * @param claimed models.Booking: The booking whose payment was claimed.
+ * @param amount int: The amount to refund, in cents.
+ * @param released string: The status the payment had before the claim.
+ * @param apply func(booking *models.Booking) (string, bool): Changes the stored booking with the refund recorded; returns the event to record and whether a seat was freed.
+ * @param aliases ...string: More events recorded for the same change.
+ * @return models.Booking: The booking stored.
+ * @return error: refundFailed when the provider could not refund.
+ */
+func refund(c *gin.Context, claimed models.Booking, amount int, released string, apply func(booking *models.Booking) (string, bool), aliases ...string) (models.Booking, error) {
+	failed := payments.Default.Refund(c.Request.Context(), claimed.Payment.IntentId, amount)
+
+	var booking models.Booking
+	err := database.Transaction(func(tx *database.Tx) error {
+		index, err := database.Locate(database.Bookings, claimed.ID)
+		if err != nil {
+			return err
+		}
+
+		booking = database.Bookings[index]
+		payment := *booking.Payment
+		booking.Payment = &payment
+		if failed != nil {
+			payment.Status = released
+			database.Bookings[index].Payment = &payment
+			return nil
+		}
+
+		payment.Status = payments.StatusRefunded
+		payment.Refunded = amount
+		eventType, freed := apply(&booking)
+		booking.Version++
+		return store(c, tx, index, booking, freed, eventType, aliases...)
+	})
+	if failed != nil {
+		return booking, refundFailed{failed}
+	}
+	return booking, err
+}
+
+/**
+ * @brief store replaces a stored booking in the transaction changing it and records its events.
+ *
+ * A cancelled booking gets back its class credit unless the cancellation
+ * policy forfeits it.
+ *
+ * @param c *gin.Context: The request changing the booking, recorded in the audit log.
+ * @param tx *database.Tx: The transaction changing the booking.
+ * @param index int: The index of the booking in database.Bookings.
+ * @param booking models.Booking: The new booking.
+ * @param freed bool: Whether a seat was freed, to be given to the waitlist of the session.
+ * @param eventType string: The event recorded in the outbox.
+ * @param aliases ...string: More events recorded for the same change.
+ */
+func store(c *gin.Context, tx *database.Tx, index int, booking models.Booking, freed bool, eventType string, aliases ...string) error {
+	current := database.Bookings[index]
+	for _, recorded := range append([]string{eventType}, aliases...) {
+		if err := tx.Record(recorded, booking.ID, booking); err != nil {
+			return err
+		}
+	}
+	if err := audit.Record(c, tx, eventType, booking.ID, current, booking); err != nil {
+		return err
+	}
+	database.Bookings[index] = booking
+	if booking.Cancellation != nil && current.Cancellation == nil && booking.Cancellation.Penalty != PenaltyCredit {
+		credits.Refund(booking, booking.Cancellation.CancelledAt)
+	}
+	if freed {
+		return promote(c, tx, booking.ClassId, booking.Date)
+	}
+	return nil
+}
+
+// errUnpaid is returned when a booking without entitlement would move into a priced class.
+var errUnpaid = errors.New("booking has not been paid")
+
+// refundFailed wraps the error of the payment provider refunding a booking.
+type refundFailed struct {
+	error
+}
+
+// respond writes the response of a booking change whose transaction failed.
+func respond(c *gin.Context, err error) {
+	var refused conflict
+	switch {
+	case errors.Is(err, database.ErrNotFound):
+		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
+	case errors.Is(err, etag.ErrModified):
+		c.IndentedJSON(http.StatusPreconditionFailed, gin.H{"error": "Booking has been modified"})
+	case errors.As(err, &refused):
+		c.IndentedJSON(http.StatusConflict, gin.H{"error": string(refused)})
+	case errors.Is(err, ErrClassFull):
+		c.IndentedJSON(http.StatusConflict, gin.H{"error": "Class is full"})
+	case errors.Is(err, credits.ErrNoCredits):
+		c.IndentedJSON(http.StatusPaymentRequired, gin.H{"error": "No class credits left"})
+	case errors.Is(err, errUnpaid):
+		c.IndentedJSON(http.StatusPaymentRequired, gin.H{"error": "Priced classes have to be paid for"})
+	case errors.As(err, &refundFailed{}):
+		c.IndentedJSON(http.StatusBadGateway, gin.H{"error": "Could not refund payment"})
+	default:
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Could not save Booking"})
+	}
+}
+
+/**
+ * @brief promote confirms the oldest waitlisted bookings of a session while it has free seats.
+ *
+ * @param c *gin.Context: The request that freed the seat, recorded in the audit log.
+ * @param tx *database.Tx: The transaction that freed the seat.
+ * @param classId int: The class of the session.
+ * @param date time.Time: The start of the session.
+ */
+func promote(c *gin.Context, tx *database.Tx, classId int, date time.Time) error {
+	for i := range database.Bookings {
+		if !hasFreeSeat(classId, date) {
+			return nil
+		}
+
+		waiting := database.Bookings[i]
+		if waiting.ClassId != classId || !waiting.Date.Equal(date) || waiting.Status != models.BookingWaitlisted {
+			continue
+		}
+
+		waiting.Transition(models.BookingConfirmed, now())
+		waiting.Version++
+		if err := tx.Record(events.BookingPromoted, waiting.ID, waiting); err != nil {
+			return err
+		}
+		if err := audit.Record(c, tx, events.BookingPromoted, waiting.ID, database.Bookings[i], waiting); err != nil {
+			return err
+		}
+		database.Bookings[i] = waiting
+	}
+	return nil
+}
+
+// hasFreeSeat reports whether a session of a class has fewer bookings holding a seat than its capacity.
+func hasFreeSeat(classId int, date time.Time) bool {
+	class, _ := database.FindItemByID(database.Classes, classId)
+	return class != nil && database.SeatsTaken(classId, date) < class.(*models.Class).Capacity
+}
\ No newline at end of file
@@ -0,0 +1,156 @@
+package calendar
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"go-api/pkg/ical"
+	"go-api/pkg/models"
+	"go-api/pkg/mockDatabase"
+	"github.com/gin-gonic/gin"
+)
+
+const productID = "-//go-api//schedule//EN"
+
+// now is the DTSTAMP of generated events; tests replace it.
+var now = time.Now
+
+/**
+ * @brief GetStudioSchedule returns the classes of a studio as a public iCalendar feed.
+ *
+ * Soft-deleted classes stay in the feed with STATUS:CANCELLED so that
+ * subscribed calendars remove them.
+ * 
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func GetStudioSchedule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	existingStudio, _ := database.FindItemByID(database.Studios, id)
+	if existingStudio == nil {
+		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Studio not found"})
+		return
+	}
+	studio := existingStudio.(*models.Studio)
+
+	calendar := ical.Calendar{ProductID: productID, Name: studio.Name}
+	for _, class := range database.Classes {
+		if class.StudioId != studio.ID {
+			continue
+		}
+		status := ical.StatusConfirmed
+		if class.DeletedAt != nil {
+			status = ical.StatusCancelled
+		}
+		calendar.Events = append(calendar.Events, ical.Event{
+			UID:      "class-" + strconv.Itoa(class.ID) + "@go-api",
+			Sequence: class.Version - 1,
+			Stamp:    now(),
+			Start:    class.StartDate,
+			End:      class.EndDate,
+			Summary:  class.Name,
+			Location: studio.Name,
+			Status:   status,
+		})
+	}
+
+	writeCalendar(c, calendar)
+}
+
+/**
+ * @brief GetMemberFeed returns the bookings of a member as a private iCalendar feed.
+ *
+ * The path holds the member's feed token, optionally followed by ".ics".
+ * Cancelled bookings, and those deleted with their class, stay in the feed with
+ * STATUS:CANCELLED so that subscribed calendars remove them; pending and
+ * waitlisted ones are TENTATIVE.
+ * 
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func GetMemberFeed(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+	member := memberByFeedToken(token)
+	if member == nil {
+		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Feed not found"})
+		return
+	}
+
+	calendar := ical.Calendar{ProductID: productID, Name: member.Name}
+	for _, booking := range database.Bookings {
+		if !strings.EqualFold(booking.Name, member.Name) {
+			continue
+		}
+		status := ical.StatusConfirmed
+		switch {
+		case booking.Status == models.BookingCancelled, booking.DeletedAt != nil:
+			status = ical.StatusCancelled
+		case booking.Status == models.BookingPending, booking.Status == models.BookingWaitlisted:
+			status = ical.StatusTentative
+		}
+		calendar.Events = append(calendar.Events, bookingEvent(booking, status))
+	}
+
+	writeCalendar(c, calendar)
+}
+
+/**
+ * @brief bookingEvent describes the class session a booking is for.
+ *
+ * The session starts at the booking date and lasts as long as the class does on
+ * one day. SEQUENCE grows with both the booking and the class versions, so a
+ * moved class is picked up as an update.
+ */
+func bookingEvent(booking models.Booking, status string) ical.Event {
+	event := ical.Event{
+		UID:      "booking-" + strconv.Itoa(booking.ID) + "@go-api",
+		Sequence: booking.Version - 1,
+		Stamp:    now(),
+		Start:    booking.Date,
+		End:      booking.Date.Add(time.Hour),
+		Status:   status,
+	}
+
+	class, _ := database.FindAnyItemByID(database.Classes, booking.ClassId)
+	if class == nil {
+		event.Summary = "Class " + strconv.Itoa(booking.ClassId)
+		return event
+	}
+
+	event.Summary = class.(*models.Class).Name
+	event.Sequence += class.(*models.Class).Version - 1
+	event.End = booking.Date.Add(class.(*models.Class).SessionLength())
+
+	studio, _ := database.FindItemByID(database.Studios, class.(*models.Class).StudioId)
+	if studio != nil {
+		event.Location = studio.(*models.Studio).Name
+	}
+	return event
+}
+
+func memberByFeedToken(token string) *models.Member {
+	if token == "" {
+		return nil
+	}
+	for i, member := range database.Members {
+		if member.FeedToken != "" && subtle.ConstantTimeCompare([]byte(member.FeedToken), []byte(token)) == 1 {
+			return &database.Members[i]
+		}
+	}
+	return nil
+}
+
+func writeCalendar(c *gin.Context, calendar ical.Calendar) {
+	c.Header("Content-Type", ical.MediaType+"; charset=utf-8")
+	c.Status(http.StatusOK)
+	if err := calendar.Write(c.Writer); err != nil {
+		c.Error(err)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,85 @@
+package calendar
+
+import (
+	"go-api/pkg/models"
+	"net/http"
+	"net/http/httptest"
+	"go-api/pkg/mockDatabase"
+	"strings"
+	"time"
+	"testing"
+	"go-api/pkg/api/openapi"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// newRouter returns a test Gin router that fails the test on responses not matching the OpenAPI spec.
+func newRouter(t *testing.T) *gin.Engine {
+	router := gin.Default()
+	router.Use(openapi.ValidateResponses(func(violation string) { t.Error(violation) }))
+	return router
+}
+
+func TestGetStudioSchedule(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.GET("/studios/:id/schedule.ics", GetStudioSchedule)
+
+	req, _ := http.NewRequest(http.MethodGet, "/studios/1/schedule.ics", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert that the HTTP status code is OK (200) and the body is a calendar
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/calendar; charset=utf-8", w.Header().Get("Content-Type"))
+
+	body := w.Body.String()
+	assert.True(t, strings.HasPrefix(body, "BEGIN:VCALENDAR\r\n"))
+	assert.Contains(t, body, "UID:class-1@go-api\r\n")
+	assert.Contains(t, body, "DTSTART:20231006T160000Z\r\n")
+	assert.Contains(t, body, "SUMMARY:Yoga\r\n")
+	assert.Contains(t, body, "LOCATION:Centro\r\n")
+
+	// Unknown studios are Not Found (404)
+	req, _ = http.NewRequest(http.MethodGet, "/studios/99/schedule.ics", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestGetMemberFeed(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.GET("/calendar/:token", GetMemberFeed)
+
+	database.Members[1].FeedToken = "martin-token"
+	database.Bookings = append(database.Bookings, models.Booking{ID: 42, Name: "Martin", ClassId: 2, Date: time.Date(2023, 10, 8, 20, 0, 0, 0, time.UTC), Version: 3, Status: models.BookingCancelled})
+
+	req, _ := http.NewRequest(http.MethodGet, "/calendar/martin-token.ics", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert that the HTTP status code is OK (200)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// The active booking lasts one Pilates session, the cancelled one is announced
+	body := w.Body.String()
+	assert.Contains(t, body, "UID:booking-2@go-api\r\nSEQUENCE:0\r\n")
+	assert.Contains(t, body, "DTSTART:20231007T200000Z\r\nDTEND:20231007T210000Z\r\n")
+	assert.Contains(t, body, "UID:booking-42@go-api\r\nSEQUENCE:2\r\n")
+	assert.Contains(t, body, "STATUS:CANCELLED\r\n")
+	assert.NotContains(t, body, "Diego")
+
+	// Unknown tokens are Not Found (404)
+	req, _ = http.NewRequest(http.MethodGet, "/calendar/guess.ics", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSessionLength(t *testing.T) {
+	class := models.Class{StartDate: time.Date(2023, 10, 6, 16, 0, 0, 0, time.UTC), EndDate: time.Date(2023, 10, 16, 17, 30, 0, 0, time.UTC)}
+	assert.Equal(t, 90*time.Minute, class.SessionLength())
+}
\ No newline at end of file
@@ -0,0 +1,72 @@
+package classes
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"go-api/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+// filter holds the query parameters of GetClasses.
+type filter struct {
+	studioId int
+	name     string
+	from     time.Time
+	to       time.Time
+	// includeDeleted also lists soft-deleted classes.
+	includeDeleted bool
+}
+
+/**
+ * @brief parseFilter reads ?studio_id=, ?name=, ?from= and ?to=.
+ *
+ * from and to are RFC 3339 instants bounding the class start date; from is
+ * inclusive and to exclusive. name matches case-insensitively.
+ *
+ * @param c *gin.Context: The Gin HTTP context.
+ * @return filter: The parsed filter.
+ * @return bool: false when a parameter is malformed.
+ */
+func parseFilter(c *gin.Context) (filter, bool) {
+	var f filter
+	var err error
+
+	if value := c.Query("studio_id"); value != "" {
+		if f.studioId, err = strconv.Atoi(value); err != nil {
+			return f, false
+		}
+	}
+	if value := c.Query("from"); value != "" {
+		if f.from, err = time.Parse(time.RFC3339, value); err != nil {
+			return f, false
+		}
+	}
+	if value := c.Query("to"); value != "" {
+		if f.to, err = time.Parse(time.RFC3339, value); err != nil {
+			return f, false
+		}
+	}
+	f.name = c.Query("name")
+
+	return f, true
+}
+
+func (f filter) matches(class models.Class) bool {
+	if class.DeletedAt != nil && !f.includeDeleted {
+		return false
+	}
+	if f.studioId != 0 && class.StudioId != f.studioId {
+		return false
+	}
+	if f.name != "" && !strings.EqualFold(class.Name, f.name) {
+		return false
+	}
+	if !f.from.IsZero() && class.StartDate.Before(f.from) {
+		return false
+	}
+	if !f.to.IsZero() && !class.StartDate.Before(f.to) {
+		return false
+	}
+	return true
+}
\ No newline at end of file
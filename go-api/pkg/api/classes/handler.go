@@ -1,20 +1,64 @@
 package classes
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 	"go-api/pkg/models"
+	"go-api/pkg/credits"
+	"go-api/pkg/api/bookings"
+	"go-api/pkg/api/audit"
+	"go-api/pkg/api/etag"
+	"go-api/pkg/api/middleware"
 	"go-api/pkg/mockDatabase"
+	"go-api/pkg/timezone"
+	"go-api/pkg/events"
 	"github.com/gin-gonic/gin"
 )
 
 /**
- * @brief GetClasses returns a list of all classes.
+ * @brief GetClasses returns a list of all classes, optionally filtered (see parseFilter).
+ *
+ * Soft-deleted classes are only listed to admins asking for ?include_deleted=true.
  * 
  * @param c *gin.Context: The Gin HTTP context.
  */
 func GetClasses(c *gin.Context) {
-	c.IndentedJSON(http.StatusOK, database.Classes)
+	classes := make([]models.Class, 0, len(database.Classes))
+	tags := make([]string, 0, len(database.Classes))
+
+	f, ok := parseFilter(c)
+	if !ok {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid filter"})
+		return
+	}
+
+	if f.includeDeleted, ok = middleware.IncludeDeleted(c); !ok {
+		return
+	}
+
+	for _, class := range database.Classes {
+		if !f.matches(class) {
+			continue
+		}
+		rendered, err := renderClass(class, c.Query("tz"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid time zone"})
+			return
+		}
+		classes = append(classes, rendered)
+		tags = append(tags, etag.For("class", rendered.ID, rendered.Version, c.Query("tz")))
+	}
+
+	listTag := etag.ForList("classes", tags)
+	c.Header("ETag", listTag)
+	if etag.IfNoneMatch(c.GetHeader("If-None-Match"), listTag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, classes)
 }
 
 /**
@@ -30,24 +74,36 @@ func PostClasses(c *gin.Context) {
 		return
 	}
 
-	if err := models.ClassValidate.Struct(newClass); err != nil {
-		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid Class"})
+	if status, message := prepareClass(&newClass); status != 0 {
+		c.IndentedJSON(status, gin.H{"error": message})
 		return
 	}
 
-	if newClass.StartDate.After(newClass.EndDate) {
-		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "StartDate must be before EndDate"})
+	var class models.Class
+	err := database.Transaction(func(tx *database.Tx) error {
+		class = database.CreateClass(newClass)
+		if err := tx.Record(events.ClassCreated, class.ID, class); err != nil {
+			return err
+		}
+		if err := audit.Record(c, tx, events.ClassCreated, class.ID, nil, class); err != nil {
+			return err
+		}
+		database.Classes = append(database.Classes, class)
+		return nil
+	})
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Could not save Class"})
 		return
 	}
 
-	var class models.Class = database.CreateClass(newClass)
-
-	database.Classes = append(database.Classes, class)
+	c.Header("ETag", etag.For("class", class.ID, class.Version, ""))
 	c.IndentedJSON(http.StatusCreated, class)
 }
 
 /**
  * @brief GetClassesByID returns a class by its ID.
+ *
+ * Soft-deleted classes are not found unless an admin asks for ?include_deleted=true.
  * 
  * @param c *gin.Context: The Gin HTTP context.
  */
@@ -59,13 +115,36 @@ func GetClassesByID(c *gin.Context) {
 		return
 	}
 
-	existingClass, index := database.FindItemByID(database.Classes, id)
+	include, ok := middleware.IncludeDeleted(c)
+	if !ok {
+		return
+	}
+
+	find := database.FindItemByID
+	if include {
+		find = database.FindAnyItemByID
+	}
+
+	existingClass, _ := find(database.Classes, id)
 	if existingClass == nil {
 		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Class not found"})
 		return
 	}
 
-	c.IndentedJSON(http.StatusOK, database.Classes[index])
+	class, err := renderClass(*existingClass.(*models.Class), c.Query("tz"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid time zone"})
+		return
+	}
+
+	tag := etag.For("class", class.ID, class.Version, c.Query("tz"))
+	c.Header("ETag", tag)
+	if etag.IfNoneMatch(c.GetHeader("If-None-Match"), tag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, class)
 }
 
 /**
@@ -93,24 +172,62 @@ func UpdateClass(c *gin.Context) {
 		return
 	}
 
-	existingClass, index := database.FindItemByID(database.Classes, id)
-	if existingClass == nil {
+	if existingClass, _ := database.FindItemByID(database.Classes, id); existingClass == nil {
 		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Class not found"})
 		return
 	}
 
+	studio, ok := findStudio(c, &updatedClass.StudioId)
+	if !ok {
+		return
+	}
+
+	if err := applyLocalDates(studio, updatedClass.LocalStartDate, updatedClass.LocalEndDate, &updatedClass.StartDate, &updatedClass.EndDate); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid local date"})
+		return
+	}
+
 	if updatedClass.StartDate.After(updatedClass.EndDate) {
 		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "StartDate must be before EndDate"})
 		return
 	}
 
-	newclass := database.UpdateClass(updatedClass, id)
-	database.Classes[index] = newclass
+	var newclass models.Class
+	err = database.Transaction(func(tx *database.Tx) error {
+		index, err := locateClass(c, id, false)
+		if err != nil {
+			return err
+		}
+
+		current := database.Classes[index]
+		newclass = database.UpdateClass(updatedClass, id, current.Version+1)
+		newclass.SourceUID = current.SourceUID
+		newclass.SourceRecurrenceId = current.SourceRecurrenceId
+		if err := tx.Record(events.ClassUpdated, newclass.ID, newclass); err != nil {
+			return err
+		}
+		if err := audit.Record(c, tx, events.ClassUpdated, newclass.ID, current, newclass); err != nil {
+			return err
+		}
+		database.Classes[index] = newclass
+		return nil
+	})
+	if err != nil {
+		respond(c, err, "Could not save Class")
+		return
+	}
+
+	c.Header("ETag", etag.For("class", newclass.ID, newclass.Version, ""))
 	c.IndentedJSON(http.StatusOK, newclass)
 }
 
 /**
- * @brief DeleteClass deletes a class by its ID.
+ * @brief DeleteClass soft-deletes a class by its ID, together with its bookings.
+ *
+ * The class and its bookings are hidden but kept with their deleted_at, so
+ * RestoreClass can bring them back until the purge job removes them for good.
+ * The class credits of open bookings are refunded, and those paid through
+ * the payment provider are cancelled and refunded (see bookings.Withdraw).
  * 
  * @param c *gin.Context: The Gin HTTP context.
  */
@@ -122,12 +239,330 @@ func DeleteClass(c *gin.Context) {
 		return
 	}
 
-	existingClass, index := database.FindItemByID(database.Classes, id)
+	existingClass, _ := database.FindItemByID(database.Classes, id)
 	if existingClass == nil {
 		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Class not found"})
 		return
 	}
 
-	database.Classes = append(database.Classes[:index], database.Classes[index+1:]...)
+	// Checked before the payments are refunded, and again under the lock.
+	current := existingClass.(*models.Class)
+	if !etag.IfMatch(c.GetHeader("If-Match"), etag.For("class", current.ID, current.Version, "")) {
+		c.IndentedJSON(http.StatusPreconditionFailed, gin.H{"error": "Class has been modified"})
+		return
+	}
+
+	if err := bookings.Withdraw(c, id); err != nil {
+		c.IndentedJSON(http.StatusBadGateway, gin.H{"error": "Could not refund payment"})
+		return
+	}
+	at := time.Now().UTC()
+	err = database.Transaction(func(tx *database.Tx) error {
+		index, err := locateClass(c, id, false)
+		if err != nil {
+			return err
+		}
+
+		deleted := database.Classes[index]
+		deleted.DeletedAt = &at
+		deleted.Version++
+		if err := tx.Record(events.ClassDeleted, deleted.ID, deleted); err != nil {
+			return err
+		}
+		if err := audit.Record(c, tx, events.ClassDeleted, deleted.ID, database.Classes[index], deleted); err != nil {
+			return err
+		}
+		database.Classes[index] = deleted
+		return bookings.DeleteWithClass(c, tx, id, at)
+	})
+	if err != nil {
+		respond(c, err, "Could not delete Class")
+		return
+	}
+
 	c.IndentedJSON(http.StatusOK, gin.H{"message": "Class deleted"})
+}
+
+/**
+ * @brief RestoreClass brings back a soft-deleted class and the bookings deleted with it.
+ *
+ * Bookings deleted at the same time as the class are restored as they were
+ * when they still have a seat and their member can pay the class credit
+ * again (see bookings.Reinstate); the others stay deleted, to be restored
+ * one by one with bookings.RestoreBooking.
+ *
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func RestoreClass(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var restored models.Class
+	err = database.Transaction(func(tx *database.Tx) error {
+		index, err := locateClass(c, id, true)
+		if err != nil {
+			return err
+		}
+
+		current := database.Classes[index]
+		restored = current
+		restored.DeletedAt = nil
+		restored.Version++
+		if err := tx.Record(events.ClassRestored, restored.ID, restored); err != nil {
+			return err
+		}
+		if err := audit.Record(c, tx, events.ClassRestored, restored.ID, current, restored); err != nil {
+			return err
+		}
+		database.Classes[index] = restored
+		for _, booking := range database.Bookings {
+			if booking.ClassId != restored.ID || booking.DeletedAt == nil || !booking.DeletedAt.Equal(*current.DeletedAt) {
+				continue
+			}
+			_, err := bookings.Reinstate(c, tx, booking)
+			if err != nil && !errors.Is(err, bookings.ErrClassFull) && !errors.Is(err, credits.ErrNoCredits) {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		respond(c, err, "Could not save Class")
+		return
+	}
+
+	c.Header("ETag", etag.For("class", restored.ID, restored.Version, ""))
+	c.IndentedJSON(http.StatusOK, restored)
+}
+
+// errNotDeleted is returned by locateClass when the class to restore is not deleted.
+var errNotDeleted = errors.New("class is not deleted")
+
+/**
+ * @brief locateClass finds the class of a request under the database lock.
+ *
+ * Call it inside the transaction changing the class: the If-Match of the
+ * request is checked against the stored version, so that two requests sent
+ * with the same ETag cannot both change it.
+ *
+ * @param c *gin.Context: The request changing the class.
+ * @param id int: The class ID.
+ * @param deleted bool: Whether the class must be soft-deleted, to be restored.
+ * @return int: The index of the class in database.Classes.
+ * @return error: database.ErrNotFound, etag.ErrModified or errNotDeleted.
+ */
+func locateClass(c *gin.Context, id int, deleted bool) (int, error) {
+	index, err := database.Locate(database.Classes, id)
+	if err != nil {
+		return index, err
+	}
+
+	class := database.Classes[index]
+	if class.DeletedAt != nil && !deleted {
+		return index, database.ErrNotFound
+	}
+	if err := etag.Check(c.GetHeader("If-Match"), "class", class.ID, class.Version); err != nil {
+		return index, err
+	}
+	if class.DeletedAt == nil && deleted {
+		return index, errNotDeleted
+	}
+	return index, nil
+}
+
+// respond writes the response of a class change whose transaction failed.
+func respond(c *gin.Context, err error, failure string) {
+	switch {
+	case errors.Is(err, database.ErrNotFound):
+		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Class not found"})
+	case errors.Is(err, etag.ErrModified):
+		c.IndentedJSON(http.StatusPreconditionFailed, gin.H{"error": "Class has been modified"})
+	case errors.Is(err, errNotDeleted):
+		c.IndentedJSON(http.StatusConflict, gin.H{"error": "Class is not deleted"})
+	case errors.Is(err, bookings.ErrPaidBookings):
+		c.IndentedJSON(http.StatusConflict, gin.H{"error": "Class has bookings paid in the meantime"})
+	default:
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": failure})
+	}
+}
+
+/**
+ * @brief PostRecurringClasses creates one class per occurrence of a recurring schedule.
+ *
+ * The schedule is expressed in the studio's wall-clock time, so occurrences
+ * keep their local start time across DST changes.
+ *
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func PostRecurringClasses(c *gin.Context) {
+	var recurring models.CreateRecurringClass
+
+	if err := c.ShouldBindJSON(&recurring); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid Class"})
+		return
+	}
+
+	if err := models.ClassValidate.Struct(recurring); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid Class"})
+		return
+	}
+
+	studio, ok := findStudio(c, &recurring.StudioId)
+	if !ok {
+		return
+	}
+
+	first, err := time.Parse(timezone.LocalLayout, recurring.LocalStartDate)
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid local date"})
+		return
+	}
+
+	days := 1
+	if recurring.Frequency == "weekly" {
+		days = 7
+	}
+	if recurring.Interval > 0 {
+		days *= recurring.Interval
+	}
+
+	duration := time.Duration(recurring.DurationMinutes) * time.Minute
+	classes := []models.Class{}
+
+	err = database.Transaction(func(tx *database.Tx) error {
+		for _, start := range timezone.Occurrences(first, studio.Location(), days, recurring.Count) {
+			class := database.CreateClass(models.CreateClass{
+				Name:      recurring.Name,
+				StudioId:  studio.ID,
+				StartDate: start,
+				EndDate:   start.Add(duration),
+				Capacity:  recurring.Capacity,
+				Price:     recurring.Price,
+			})
+			if err := tx.Record(events.ClassCreated, class.ID, class); err != nil {
+				return err
+			}
+			if err := audit.Record(c, tx, events.ClassCreated, class.ID, nil, class); err != nil {
+				return err
+			}
+			classes = append(classes, class)
+		}
+		database.Classes = append(database.Classes, classes...)
+		return nil
+	})
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Could not save Classes"})
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, classes)
+}
+
+/**
+ * @brief prepareClass validates a new class and resolves its studio and local dates.
+ *
+ * These are the rules of PostClasses, shared with the bulk import.
+ *
+ * @param newClass *models.CreateClass: The class to check, updated with its studio and UTC dates.
+ * @return int, string: The HTTP status and error message, 0 when the class is valid.
+ */
+func prepareClass(newClass *models.CreateClass) (int, string) {
+	if err := models.ClassValidate.Struct(newClass); err != nil {
+		return http.StatusBadRequest, "Invalid Class"
+	}
+
+	studio := lookupStudio(&newClass.StudioId)
+	if studio == nil {
+		return http.StatusNotFound, "Studio not found"
+	}
+
+	if err := applyLocalDates(studio, newClass.LocalStartDate, newClass.LocalEndDate, &newClass.StartDate, &newClass.EndDate); err != nil {
+		return http.StatusBadRequest, "Invalid local date"
+	}
+
+	if newClass.StartDate.After(newClass.EndDate) {
+		return http.StatusBadRequest, "StartDate must be before EndDate"
+	}
+
+	return 0, ""
+}
+
+/**
+ * @brief lookupStudio finds the studio of a class, defaulting to the main studio.
+ *
+ * @param studioId *int: The requested studio ID, replaced by the default when zero.
+ * @return *models.Studio: nil when the studio does not exist.
+ */
+func lookupStudio(studioId *int) *models.Studio {
+	if *studioId == 0 {
+		*studioId = database.DefaultStudioID
+	}
+
+	studio, _ := database.FindItemByID(database.Studios, *studioId)
+	if studio == nil {
+		return nil
+	}
+	return studio.(*models.Studio)
+}
+
+/**
+ * @brief findStudio looks up the studio of a class, defaulting to the main studio.
+ *
+ * @param c *gin.Context: The Gin HTTP context, answered with 404 when the studio does not exist.
+ * @param studioId *int: The requested studio ID, replaced by the default when zero.
+ * @return *models.Studio, bool: The studio and whether it was found.
+ */
+func findStudio(c *gin.Context, studioId *int) (*models.Studio, bool) {
+	studio := lookupStudio(studioId)
+	if studio == nil {
+		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Studio not found"})
+		return nil, false
+	}
+
+	return studio, true
+}
+
+/**
+ * @brief applyLocalDates replaces the class dates with the wall-clock ones, when given.
+ *
+ * @param studio *models.Studio: The studio whose time zone the wall-clock dates belong to.
+ * @param localStart string: The local start date, or "" to keep start.
+ * @param localEnd string: The local end date, or "" to keep end.
+ */
+func applyLocalDates(studio *models.Studio, localStart string, localEnd string, start *time.Time, end *time.Time) error {
+	if localStart != "" {
+		instant, err := timezone.ParseLocal(localStart, studio.Location())
+		if err != nil {
+			return err
+		}
+		*start = instant
+	}
+
+	if localEnd != "" {
+		instant, err := timezone.ParseLocal(localEnd, studio.Location())
+		if err != nil {
+			return err
+		}
+		*end = instant
+	}
+
+	return nil
+}
+
+/**
+ * @brief renderClass expresses the class dates in the zone requested through ?tz=.
+ *
+ * @param class models.Class: The stored class.
+ * @param tz string: The requested zone, "studio" or an IANA name; "" keeps the stored dates.
+ */
+func renderClass(class models.Class, tz string) (models.Class, error) {
+	loc, err := timezone.Resolve(tz, database.StudioTimeZone(class.StudioId))
+	if err != nil || loc == nil {
+		return class, err
+	}
+	return class.In(loc), nil
 }
\ No newline at end of file
@@ -2,20 +2,35 @@ package classes
 
 import (
 	"bytes"
+	"context"
 	"go-api/pkg/models"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"go-api/pkg/mockDatabase"
+	"strconv"
+	"strings"
 	"time"
 	"testing"
+	"go-api/pkg/api/middleware"
+	"go-api/pkg/api/openapi"
+	"go-api/pkg/credits"
+	"go-api/pkg/events"
+	"go-api/pkg/payments"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 )
 
+// newRouter returns a test Gin router that fails the test on responses not matching the OpenAPI spec.
+func newRouter(t *testing.T) *gin.Engine {
+	router := gin.Default()
+	router.Use(openapi.ValidateResponses(func(violation string) { t.Error(violation) }))
+	return router
+}
+
 func TestGetClassesByID(t *testing.T) {
 	// Create a test Gin router
-	router := gin.Default()
+	router := newRouter(t)
 	router.GET("/classes/:id", GetClassesByID)
 
 	// Create a GET request to retrieve booking with ID 1
@@ -44,7 +59,7 @@ func TestGetClassesByID(t *testing.T) {
 
 func TestGetClasses(t *testing.T) {
 	// Create a test Gin router
-	router := gin.Default()
+	router := newRouter(t)
 	router.GET("/classes", GetClasses)
 
 	// Create a GET request to retrieve all classes
@@ -76,7 +91,7 @@ func TestGetClasses(t *testing.T) {
 
 func TestPostClasses(t *testing.T) {
 	// Create a test Gin router
-	router := gin.Default()
+	router := newRouter(t)
 	router.POST("/classes", PostClasses)
 
 	// Define a new class  for testing
@@ -120,7 +135,7 @@ func TestPostClasses(t *testing.T) {
 
 func TestUpdateClass(t *testing.T) {
 	// Create a test Gin router
-	router := gin.Default()
+	router := newRouter(t)
 	router.PUT("/classes/:id", UpdateClass)
 
 	// Define the updated class  for testing
@@ -163,7 +178,7 @@ func TestUpdateClass(t *testing.T) {
 
 func TestDeleteClass(t *testing.T) {
 	// Create a test Gin router
-	router := gin.Default()
+	router := newRouter(t)
 	router.DELETE("/classes/:id", DeleteClass)
 
 	// Create a DELETE request to delete an existing class
@@ -178,7 +193,7 @@ func TestDeleteClass(t *testing.T) {
 
 func TestPostClassInvalidDateOrder(t *testing.T) {
 	// Create a test Gin router
-	router := gin.Default()
+	router := newRouter(t)
 	router.POST("/classes", PostClasses)
 
 	// Define a new class with StartDate after EndDate for testing
@@ -204,7 +219,7 @@ func TestPostClassInvalidDateOrder(t *testing.T) {
 
 func TestUpdateClassInvalidDateOrde(t *testing.T) {
 	// Create a test Gin router
-	router := gin.Default()
+	router := newRouter(t)
 	router.PUT("/classes/:id", UpdateClass)
 
 	// Define a new class with StartDate after EndDate for testing
@@ -227,4 +242,721 @@ func TestUpdateClassInvalidDateOrde(t *testing.T) {
 
 	// Assert that the HTTP status code is Bad Request (404)
 	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+func TestPostClassesLocalTime(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.POST("/classes", PostClasses)
+
+	// Define a class in Madrid wall-clock time, during summer time (UTC+2)
+	var newClass = models.CreateClass{
+		Name:           "TestClassLocal",
+		StudioId:       2,
+		LocalStartDate: "2023-07-10T18:00:00",
+		LocalEndDate:   "2023-07-10T19:00:00",
+		Capacity:       8,
+	}
+
+	newClassJSON, _ := json.Marshal(newClass)
+
+	// Create a POST request to create a new class
+	req, _ := http.NewRequest(http.MethodPost, "/classes", bytes.NewReader(newClassJSON))
+	req.Header.Add("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert that the HTTP status code is Created (201)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var createdClass models.Class
+	err := json.Unmarshal(w.Body.Bytes(), &createdClass)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The class is stored as an instant in UTC
+	assert.Equal(t, 2, createdClass.StudioId)
+	assert.Equal(t, time.Date(2023, 7, 10, 16, 0, 0, 0, time.UTC), createdClass.StartDate)
+	assert.Equal(t, time.Date(2023, 7, 10, 17, 0, 0, 0, time.UTC), createdClass.EndDate)
+}
+
+func TestPostClassesNonexistentLocalTime(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.POST("/classes", PostClasses)
+
+	// 02:30 does not exist in Madrid on the day clocks spring forward
+	var newClass = models.CreateClass{
+		Name:           "TestClassGap",
+		StudioId:       2,
+		LocalStartDate: "2023-03-26T02:30:00",
+		LocalEndDate:   "2023-03-26T04:00:00",
+		Capacity:       8,
+	}
+
+	newClassJSON, _ := json.Marshal(newClass)
+
+	req, _ := http.NewRequest(http.MethodPost, "/classes", bytes.NewReader(newClassJSON))
+	req.Header.Add("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert that the HTTP status code is Bad Request (400)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetClassesByIDStudioTimeZone(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.GET("/classes/:id", GetClassesByID)
+
+	// Create a GET request rendering class 2 in its studio zone (America/Montevideo)
+	req, _ := http.NewRequest(http.MethodGet, "/classes/2?tz=studio", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert that the HTTP status code is OK (200)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		StartDate string `json:"start_date"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "2023-10-07T17:00:00-03:00", response.StartDate)
+
+	// An unknown zone is rejected
+	req, _ = http.NewRequest(http.MethodGet, "/classes/2?tz=Mars/Olympus", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestPostRecurringClassesAcrossDST(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.POST("/classes/recurring", PostRecurringClasses)
+
+	// A weekly 18:00 class in Madrid, spanning the end of summer time on 2023-10-29
+	var recurring = models.CreateRecurringClass{
+		Name:            "TestRecurring",
+		StudioId:        2,
+		LocalStartDate:  "2023-10-16T18:00:00",
+		DurationMinutes: 60,
+		Capacity:        8,
+		Frequency:       "weekly",
+		Count:           3,
+	}
+
+	recurringJSON, _ := json.Marshal(recurring)
+
+	req, _ := http.NewRequest(http.MethodPost, "/classes/recurring", bytes.NewReader(recurringJSON))
+	req.Header.Add("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert that the HTTP status code is Created (201)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var created []models.Class
+	err := json.Unmarshal(w.Body.Bytes(), &created)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every occurrence starts at 18:00 Madrid time, whatever the UTC offset
+	assert.Len(t, created, 3)
+	assert.Equal(t, time.Date(2023, 10, 16, 16, 0, 0, 0, time.UTC), created[0].StartDate)
+	assert.Equal(t, time.Date(2023, 10, 23, 16, 0, 0, 0, time.UTC), created[1].StartDate)
+	assert.Equal(t, time.Date(2023, 10, 30, 17, 0, 0, 0, time.UTC), created[2].StartDate)
+	assert.Equal(t, time.Date(2023, 10, 30, 18, 0, 0, 0, time.UTC), created[2].EndDate)
+}
+func TestGetClassesByIDIfNoneMatch(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.GET("/classes/:id", GetClassesByID)
+
+	// Create a GET request to read the current ETag of class 3
+	req, _ := http.NewRequest(http.MethodGet, "/classes/3", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	tag := w.Header().Get("ETag")
+	assert.NotEmpty(t, tag)
+
+	// Sending the ETag back returns Not Modified (304) without a body
+	req, _ = http.NewRequest(http.MethodGet, "/classes/3", nil)
+	req.Header.Add("If-None-Match", tag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.Bytes())
+}
+
+func TestUpdateClassIfMatch(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.GET("/classes/:id", GetClassesByID)
+	router.PUT("/classes/:id", UpdateClass)
+
+	// Read the current ETag of class 3
+	req, _ := http.NewRequest(http.MethodGet, "/classes/3", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	tag := w.Header().Get("ETag")
+
+	var updatedClass = models.UpdateClass{
+		Name:      "TestClassVersioned",
+		StartDate: time.Date(2023, 10, 11, 11, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2023, 10, 12, 12, 0, 0, 0, time.UTC),
+		Capacity:  12,
+	}
+	updatedClassJSON, _ := json.Marshal(updatedClass)
+
+	// The first update with the current ETag succeeds and bumps the version
+	req, _ = http.NewRequest(http.MethodPut, "/classes/3", bytes.NewBuffer(updatedClassJSON))
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("If-Match", tag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, tag, w.Header().Get("ETag"))
+
+	var updated models.Class
+	err := json.Unmarshal(w.Body.Bytes(), &updated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 2, updated.Version)
+
+	// A second update with the stale ETag is rejected with Precondition Failed (412)
+	req, _ = http.NewRequest(http.MethodPut, "/classes/3", bytes.NewBuffer(updatedClassJSON))
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("If-Match", tag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+}
+
+func TestUpdateClassIfMatchConcurrent(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.GET("/classes/:id", GetClassesByID)
+	router.PUT("/classes/:id", UpdateClass)
+
+	start := time.Date(2023, 11, 3, 18, 0, 0, 0, time.UTC)
+	class := database.CreateClass(models.CreateClass{Name: "Contended", StudioId: 1, StartDate: start, EndDate: start.Add(time.Hour), Capacity: 10})
+	database.Classes = append(database.Classes, class)
+	path := "/classes/" + strconv.Itoa(class.ID)
+
+	// Read the current ETag of the class
+	req, _ := http.NewRequest(http.MethodGet, path, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	tag := w.Header().Get("ETag")
+
+	// Two updates are sent at once with the same ETag
+	codes := make(chan int, 2)
+	for _, name := range []string{"TestClassFirst", "TestClassSecond"} {
+		body, _ := json.Marshal(models.UpdateClass{
+			Name:      name,
+			StudioId:  1,
+			StartDate: start,
+			EndDate:   start.Add(time.Hour),
+			Capacity:  12,
+		})
+		go func() {
+			req, _ := http.NewRequest(http.MethodPut, path, bytes.NewReader(body))
+			req.Header.Add("Content-Type", "application/json")
+			req.Header.Add("If-Match", tag)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			codes <- w.Code
+		}()
+	}
+
+	// Only one of them is applied; the other gets Precondition Failed (412)
+	assert.ElementsMatch(t, []int{http.StatusOK, http.StatusPreconditionFailed}, []int{<-codes, <-codes})
+	stored, _ := database.FindItemByID(database.Classes, class.ID)
+	assert.Equal(t, class.Version+1, stored.(*models.Class).Version)
+}
+
+func TestDeleteClassIfMatchMismatch(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.DELETE("/classes/:id", DeleteClass)
+
+	// Create a DELETE request with an ETag that does not match class 2
+	req, _ := http.NewRequest(http.MethodDelete, "/classes/2", nil)
+	req.Header.Add("If-Match", `"class-2-v99"`)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert that the HTTP status code is Precondition Failed (412)
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+
+	_, index := database.FindItemByID(database.Classes, 2)
+	assert.NotEqual(t, -1, index)
+}
+func TestImportClassesDryRun(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.POST("/classes/import", ImportClasses)
+
+	before := len(database.Classes)
+
+	body := "name,start_date,end_date,capacity\n" +
+		"DryRunA,2030-01-01T10:00:00Z,2030-01-01T11:00:00Z,10\n" +
+		"DryRunB,2030-01-02T11:00:00Z,2030-01-02T10:00:00Z,10\n"
+
+	req, _ := http.NewRequest(http.MethodPost, "/classes/import?dry_run=true", bytes.NewReader([]byte(body)))
+	req.Header.Add("Content-Type", "text/csv")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert that the HTTP status code is OK (200) and nothing was written
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, database.Classes, before)
+
+	var report models.ClassImportReport
+	err := json.Unmarshal(w.Body.Bytes(), &report)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 2, report.Total)
+	assert.Equal(t, 1, report.Invalid)
+	assert.Equal(t, "valid", report.Rows[0].Status)
+	assert.Equal(t, "invalid", report.Rows[1].Status)
+	assert.NotEmpty(t, report.Rows[1].Error)
+}
+
+func TestImportClassesAtomicRejectsInvalidRows(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.POST("/classes/import", ImportClasses)
+
+	before := len(database.Classes)
+
+	var rows = []models.CreateClass{
+		{Name: "AtomicA", StartDate: time.Date(2030, 1, 1, 10, 0, 0, 0, time.UTC), EndDate: time.Date(2030, 1, 1, 11, 0, 0, 0, time.UTC), Capacity: 10},
+		{Name: "AtomicB", StudioId: 99, StartDate: time.Date(2030, 1, 2, 10, 0, 0, 0, time.UTC), EndDate: time.Date(2030, 1, 2, 11, 0, 0, 0, time.UTC), Capacity: 10},
+	}
+
+	rowsJSON, _ := json.Marshal(rows)
+
+	req, _ := http.NewRequest(http.MethodPost, "/classes/import", bytes.NewReader(rowsJSON))
+	req.Header.Add("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert that the HTTP status code is Unprocessable Entity (422) and nothing was written
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Len(t, database.Classes, before)
+
+	var report models.ClassImportReport
+	err := json.Unmarshal(w.Body.Bytes(), &report)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 0, report.Created)
+	assert.Equal(t, 1, report.Invalid)
+	assert.NotEmpty(t, report.Error)
+}
+
+func TestImportClassesBestEffort(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.POST("/classes/import", ImportClasses)
+
+	before := len(database.Classes)
+
+	body := "name,studio_id,local_start_date,local_end_date,capacity\n" +
+		"BestEffortA,2,2030-03-01T18:00:00,2030-03-01T19:00:00,12\n" +
+		"BestEffortB,2,not a date,2030-03-02T19:00:00,12\n"
+
+	req, _ := http.NewRequest(http.MethodPost, "/classes/import?mode=best_effort", bytes.NewReader([]byte(body)))
+	req.Header.Add("Content-Type", "text/csv")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert that the HTTP status code is Created (201) and only the valid row was written
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Len(t, database.Classes, before+1)
+
+	var report models.ClassImportReport
+	err := json.Unmarshal(w.Body.Bytes(), &report)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, "created", report.Rows[0].Status)
+	assert.Equal(t, time.Date(2030, 3, 1, 17, 0, 0, 0, time.UTC), report.Rows[0].Class.StartDate)
+	assert.Equal(t, "invalid", report.Rows[1].Status)
+}
+
+func TestImportClassesUnknownColumn(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.POST("/classes/import", ImportClasses)
+
+	req, _ := http.NewRequest(http.MethodPost, "/classes/import", bytes.NewReader([]byte("name,colour\nA,red\n")))
+	req.Header.Add("Content-Type", "text/csv")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert that the HTTP status code is Bad Request (400)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetClassesFilter(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.GET("/classes", GetClasses)
+
+	req, _ := http.NewRequest(http.MethodGet, "/classes?studio_id=1&from=2023-10-07T00:00:00Z&to=2023-10-08T00:00:00Z", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert that the HTTP status code is OK (200)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var classes []models.Class
+	err := json.Unmarshal(w.Body.Bytes(), &classes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only Pilates starts on 2023-10-07
+	assert.Len(t, classes, 1)
+	assert.Equal(t, "Pilates", classes[0].Name)
+
+	// A malformed filter returns Bad Request (400)
+	req, _ = http.NewRequest(http.MethodGet, "/classes?studio_id=centro", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+const partnerCalendar = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//Partner//EN\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:spin@partner\r\n" +
+	"SUMMARY:Spin\r\n" +
+	"DTSTART;TZID=Europe/Madrid:20231016T180000\r\n" +
+	"DTEND;TZID=Europe/Madrid:20231016T190000\r\n" +
+	"RRULE:FREQ=WEEKLY;BYDAY=MO;COUNT=3\r\n" +
+	"EXDATE;TZID=Europe/Madrid:20231023T180000\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:yoga@partner\r\n" +
+	"SUMMARY:Morning Yoga\\, all levels\r\n" +
+	"DTSTART:20231017T090000\r\n" +
+	"DURATION:PT45M\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"ACTION:DISPLAY\r\n" +
+	"END:VALARM\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:monthly@partner\r\n" +
+	"SUMMARY:Monthly\r\n" +
+	"DTSTART:20231001T100000Z\r\n" +
+	"DTEND:20231001T110000Z\r\n" +
+	"RRULE:FREQ=MONTHLY;COUNT=2\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func importCalendar(router *gin.Engine, calendar string) (int, models.ClassImportReport) {
+	req, _ := http.NewRequest(http.MethodPost, "/classes/import?mode=best_effort&capacity=8&studio_id=2", bytes.NewReader([]byte(calendar)))
+	req.Header.Add("Content-Type", "text/calendar")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var report models.ClassImportReport
+	json.Unmarshal(w.Body.Bytes(), &report)
+	return w.Code, report
+}
+
+func TestImportClassesICalendar(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.POST("/classes/import", ImportClasses)
+
+	before := len(database.Classes)
+
+	status, report := importCalendar(router, partnerCalendar)
+
+	// Assert that the HTTP status code is Created (201)
+	assert.Equal(t, http.StatusCreated, status)
+	assert.Equal(t, 3, report.Created)
+	assert.Equal(t, 1, report.Invalid)
+	assert.Len(t, database.Classes, before+3)
+
+	// The weekly class keeps 18:00 Madrid time across the end of summer time, minus the excluded date
+	assert.Equal(t, "spin@partner", report.Rows[0].UID)
+	assert.Equal(t, time.Date(2023, 10, 16, 16, 0, 0, 0, time.UTC), report.Rows[0].Class.StartDate)
+	assert.Equal(t, time.Date(2023, 10, 30, 17, 0, 0, 0, time.UTC), report.Rows[1].Class.StartDate)
+	assert.Equal(t, time.Date(2023, 10, 30, 18, 0, 0, 0, time.UTC), report.Rows[1].Class.EndDate)
+
+	// Floating times are read in the studio zone and the summary is made a valid name
+	assert.Equal(t, "MorningYogaalllevels", report.Rows[2].Class.Name)
+	assert.Equal(t, time.Date(2023, 10, 17, 7, 0, 0, 0, time.UTC), report.Rows[2].Class.StartDate)
+	assert.Equal(t, 45*time.Minute, report.Rows[2].Class.EndDate.Sub(report.Rows[2].Class.StartDate))
+	assert.Len(t, report.Rows[2].Warnings, 2)
+
+	// Unsupported constructs are reported
+	assert.Equal(t, "invalid", report.Rows[3].Status)
+	assert.Contains(t, report.Rows[3].Error, "MONTHLY")
+
+	// Importing the same file again is a no-op, changed events are updated in place
+	status, report = importCalendar(router, strings.Replace(partnerCalendar, "SUMMARY:Spin", "SUMMARY:SpinClass", 1))
+
+	assert.Equal(t, http.StatusCreated, status)
+	assert.Equal(t, 0, report.Created)
+	assert.Equal(t, 2, report.Updated)
+	assert.Equal(t, 1, report.Unchanged)
+	assert.Len(t, database.Classes, before+3)
+	assert.Equal(t, "SpinClass", report.Rows[0].Class.Name)
+	assert.Equal(t, 2, report.Rows[0].Class.Version)
+}
+
+const overridesCalendar = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//Partner//EN\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:pilates@partner\r\n" +
+	"SUMMARY:Pilates\r\n" +
+	"DTSTART:20231108T100000Z\r\n" +
+	"DTEND:20231108T110000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:boxing@partner\r\n" +
+	"SUMMARY:Boxing\r\n" +
+	"DTSTART:20231106T180000Z\r\n" +
+	"DTEND:20231106T190000Z\r\n" +
+	"RRULE:FREQ=WEEKLY;COUNT=3\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:boxing@partner\r\n" +
+	"RECURRENCE-ID:20231113T180000Z\r\n" +
+	"SUMMARY:Boxing\r\n" +
+	"DTSTART:20231113T190000Z\r\n" +
+	"DTEND:20231113T200000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:boxing@partner\r\n" +
+	"RECURRENCE-ID:20231120T180000Z\r\n" +
+	"STATUS:CANCELLED\r\n" +
+	"DTSTART:20231120T180000Z\r\n" +
+	"DTEND:20231120T190000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestImportClassesICalendarOverrides(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.POST("/classes/import", ImportClasses)
+
+	before := len(database.Classes)
+
+	status, report := importCalendar(router, overridesCalendar)
+
+	// The moved occurrence replaces the one of the series and the cancelled one is left out
+	assert.Equal(t, http.StatusCreated, status)
+	assert.Equal(t, 3, report.Created)
+	assert.Equal(t, 0, report.Invalid)
+	assert.Equal(t, time.Date(2023, 11, 6, 18, 0, 0, 0, time.UTC), report.Rows[1].Class.StartDate)
+	assert.Contains(t, report.Rows[1].Warnings, "occurrence 2023-11-20T18:00:00Z cancelled")
+	assert.Equal(t, time.Date(2023, 11, 13, 19, 0, 0, 0, time.UTC), report.Rows[2].Class.StartDate)
+	assert.Equal(t, time.Date(2023, 11, 13, 18, 0, 0, 0, time.UTC), *report.Rows[2].Class.SourceRecurrenceId)
+
+	// Moving an event updates its class in place instead of duplicating it
+	moved := strings.Replace(overridesCalendar, "20231108T100000Z", "20231109T100000Z", 1)
+	moved = strings.Replace(moved, "20231108T110000Z", "20231109T110000Z", 1)
+	status, report = importCalendar(router, moved)
+
+	assert.Equal(t, http.StatusCreated, status)
+	assert.Equal(t, 0, report.Created)
+	assert.Equal(t, 1, report.Updated)
+	assert.Equal(t, 2, report.Unchanged)
+	assert.Len(t, database.Classes, before+3)
+	assert.Equal(t, time.Date(2023, 11, 9, 10, 0, 0, 0, time.UTC), report.Rows[0].Class.StartDate)
+}
+
+func TestDeleteAndRestoreClass(t *testing.T) {
+	// Create a test Gin router authenticating an admin and a staff member
+	router := newRouter(t)
+	router.Use(middleware.Authenticate(map[string]middleware.Principal{
+		"admin-key": {Name: "owner", Role: "admin"},
+		"staff-key": {Name: "frontdesk", Role: "staff"},
+	}))
+	router.GET("/classes", GetClasses)
+	router.GET("/classes/:id", GetClassesByID)
+	router.DELETE("/classes/:id", DeleteClass)
+	router.POST("/classes/:id/restore", middleware.RequireRole("admin"), RestoreClass)
+
+	start := time.Date(2031, 5, 6, 18, 0, 0, 0, time.UTC)
+	class := database.CreateClass(models.CreateClass{Name: "Trash", StudioId: 1, StartDate: start, EndDate: start.Add(time.Hour), Capacity: 10})
+	database.Classes = append(database.Classes, class)
+	booking := database.CreateBooking(models.CreateBooking{Name: "Diego", ClassId: class.ID, Date: start})
+	database.Bookings = append(database.Bookings, booking)
+	path := "/classes/" + strconv.Itoa(class.ID)
+
+	request := func(method string, path string, key string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, nil)
+		req.Header.Add(middleware.APIKeyHeader, key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request(http.MethodDelete, path, "staff-key")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// The class and its booking are hidden but kept
+	assert.Equal(t, http.StatusNotFound, request(http.MethodGet, path, "staff-key").Code)
+	assert.NotContains(t, request(http.MethodGet, "/classes?name=Trash", "staff-key").Body.String(), "Trash")
+	found, _ := database.FindItemByID(database.Bookings, booking.ID)
+	assert.Nil(t, found)
+	found, _ = database.FindAnyItemByID(database.Bookings, booking.ID)
+	assert.NotNil(t, found.(*models.Booking).DeletedAt)
+
+	// Only admins can see deleted classes
+	assert.Equal(t, http.StatusForbidden, request(http.MethodGet, "/classes?include_deleted=true", "staff-key").Code)
+	w = request(http.MethodGet, "/classes?name=Trash&include_deleted=true", "admin-key")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var listed []models.Class
+	if err := json.Unmarshal(w.Body.Bytes(), &listed); err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, listed, 1) {
+		assert.NotNil(t, listed[0].DeletedAt)
+	}
+	assert.Equal(t, http.StatusOK, request(http.MethodGet, path+"?include_deleted=true", "admin-key").Code)
+
+	// Deleting again finds nothing
+	assert.Equal(t, http.StatusNotFound, request(http.MethodDelete, path, "admin-key").Code)
+
+	// Restoring brings back the class and its booking
+	assert.Equal(t, http.StatusForbidden, request(http.MethodPost, path+"/restore", "staff-key").Code)
+	w = request(http.MethodPost, path+"/restore", "admin-key")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var restored models.Class
+	if err := json.Unmarshal(w.Body.Bytes(), &restored); err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, restored.DeletedAt)
+	assert.Equal(t, 3, restored.Version)
+	found, _ = database.FindItemByID(database.Bookings, booking.ID)
+	assert.NotNil(t, found)
+	assert.Equal(t, http.StatusOK, request(http.MethodGet, path, "staff-key").Code)
+
+	// A class that is not deleted cannot be restored
+	assert.Equal(t, http.StatusConflict, request(http.MethodPost, path+"/restore", "admin-key").Code)
+
+	// Both changes are in the audit log with who made them
+	var actions []string
+	for _, entry := range database.AuditLog() {
+		if entry.Resource == "class" && entry.ResourceId == class.ID {
+			actions = append(actions, entry.Actor+" "+entry.Action)
+			if entry.Action == "class.deleted" {
+				assert.Contains(t, entry.Changes, "deleted_at")
+			}
+		}
+	}
+	assert.Equal(t, []string{"frontdesk class.deleted", "owner class.restored"}, actions)
+
+	// So are those of the booking deleted and restored with the class
+	actions = nil
+	for _, entry := range database.AuditLog() {
+		if entry.Resource == "booking" && entry.ResourceId == booking.ID {
+			actions = append(actions, entry.Actor+" "+entry.Action)
+		}
+	}
+	assert.Equal(t, []string{"frontdesk booking.deleted", "owner booking.restored"}, actions)
+}
+
+func TestDeleteClassRefundsItsBookings(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.DELETE("/classes/:id", DeleteClass)
+	router.POST("/classes/:id/restore", RestoreClass)
+
+	fake := payments.NewFake([]byte("secret"))
+	payments.Default = fake
+	defer func() { payments.Default = payments.NewFake(nil) }()
+
+	start := time.Date(2031, 6, 6, 18, 0, 0, 0, time.UTC)
+	class := database.CreateClass(models.CreateClass{Name: "Refunded", StudioId: 1, StartDate: start, EndDate: start.Add(time.Hour), Capacity: 10, Price: 1500})
+	database.Classes = append(database.Classes, class)
+
+	// Martin (member 2) paid with a class credit, a drop-in through the provider
+	credited := database.CreateBooking(models.CreateBooking{Name: "Martin", ClassId: class.ID, Date: start})
+	credited.MemberId = 2
+	credited.Entitlement = models.EntitlementCredit
+	credits.Spend(credited, start)
+	balance := credits.Balance(2)
+
+	intent, _ := fake.CreateIntent(context.Background(), 1500, "usd", "Refunded")
+	fake.Complete(intent.ID, true)
+	paid := database.CreateBooking(models.CreateBooking{Name: "Walkin", ClassId: class.ID, Date: start})
+	paid.Payment = &models.Payment{IntentId: intent.ID, Amount: 1500, Currency: "usd", Status: payments.StatusSucceeded}
+	database.Bookings = append(database.Bookings, credited, paid)
+	path := "/classes/" + strconv.Itoa(class.ID)
+
+	req, _ := http.NewRequest(http.MethodDelete, path, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// The credit comes back, the payment is refunded and the paid booking cancelled
+	assert.Equal(t, balance+1, credits.Balance(2))
+	assert.Equal(t, 1500, fake.Refunded(intent.ID))
+	stored, _ := database.FindAnyItemByID(database.Bookings, paid.ID)
+	assert.Equal(t, models.BookingCancelled, stored.(*models.Booking).Status)
+	assert.Equal(t, payments.StatusRefunded, stored.(*models.Booking).Payment.Status)
+	var recorded []string
+	for _, event := range database.Outbox {
+		if event.AggregateID == paid.ID && event.AggregateType == "booking" {
+			recorded = append(recorded, event.Type)
+		}
+	}
+	assert.Equal(t, []string{events.BookingCancelled, events.BookingDeleted}, recorded)
+
+	// Restoring takes the credit again; the refunded booking comes back cancelled
+	req, _ = http.NewRequest(http.MethodPost, path+"/restore", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, balance, credits.Balance(2))
+	stored, _ = database.FindItemByID(database.Bookings, credited.ID)
+	if assert.NotNil(t, stored) {
+		assert.Equal(t, models.BookingConfirmed, stored.(*models.Booking).Status)
+		assert.Equal(t, 3, stored.(*models.Booking).Version)
+	}
+	stored, _ = database.FindItemByID(database.Bookings, paid.ID)
+	if assert.NotNil(t, stored) {
+		assert.Equal(t, models.BookingCancelled, stored.(*models.Booking).Status)
+	}
+	assert.Equal(t, events.BookingRestored, database.Outbox[len(database.Outbox)-1].Type)
 }
\ No newline at end of file
@@ -0,0 +1,266 @@
+package classes
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"go-api/pkg/ical"
+	"go-api/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+// maxOccurrences bounds the expansion of one recurring event.
+const maxOccurrences = 366
+
+/**
+ * @brief decodeCalendar turns the VEVENTs of an iCalendar body into import rows.
+ *
+ * SUMMARY becomes the class name and DTSTART/DTEND (or DURATION) its dates.
+ * Recurring events (RRULE, EXDATE) are expanded into one row per occurrence;
+ * VEVENTs with a RECURRENCE-ID override that occurrence, or drop it when
+ * cancelled.
+ * Times with a TZID are read in that zone and floating times in the zone of
+ * the studio given by ?studio_id=. iCalendar has no capacity, so ?capacity=
+ * is required. Unsupported constructs make the event's row invalid.
+ *
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func decodeCalendar(c *gin.Context) ([]importRow, error) {
+	capacity, err := strconv.Atoi(c.Query("capacity"))
+	if err != nil || capacity < 1 {
+		return nil, fmt.Errorf("capacity is required for iCalendar imports")
+	}
+
+	studioId := 0
+	if value := c.Query("studio_id"); value != "" {
+		if studioId, err = strconv.Atoi(value); err != nil {
+			return nil, fmt.Errorf("Invalid studio_id")
+		}
+	}
+	studio := lookupStudio(&studioId)
+	if studio == nil {
+		return nil, fmt.Errorf("Studio not found")
+	}
+
+	calendar, err := ical.Parse(c.Request.Body)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid iCalendar: %v", err)
+	}
+
+	// Overridden occurrences by UID and start, true when cancelled.
+	overrides := map[string]map[int64]bool{}
+	cancelled := map[*ical.Component]bool{}
+	for _, component := range calendar.Components {
+		uid, property := component.Get("UID"), component.Get("RECURRENCE-ID")
+		if component.Name != "VEVENT" || uid == nil || property == nil {
+			continue
+		}
+		recurrenceId, err := recurrenceTime(*property, studio)
+		if err != nil {
+			continue
+		}
+		if overrides[uid.Value] == nil {
+			overrides[uid.Value] = map[int64]bool{}
+		}
+		overrides[uid.Value][recurrenceId.Unix()] = isCancelled(component)
+		cancelled[component] = isCancelled(component)
+	}
+
+	var rows []importRow
+	for _, component := range calendar.Components {
+		if component.Name != "VEVENT" || cancelled[component] {
+			continue
+		}
+		var eventOverrides map[int64]bool
+		if uid := component.Get("UID"); uid != nil {
+			eventOverrides = overrides[uid.Value]
+		}
+		eventRows, err := eventRows(component, studio, capacity, eventOverrides)
+		if err != nil {
+			uid := ""
+			if property := component.Get("UID"); property != nil {
+				uid = property.Value
+			}
+			rows = append(rows, importRow{uid: uid, err: err.Error()})
+			continue
+		}
+		rows = append(rows, eventRows...)
+		if len(rows) > maxImportRows {
+			break
+		}
+	}
+	return rows, nil
+}
+
+/**
+ * @brief eventRows turns one VEVENT into the rows of its occurrences.
+ *
+ * @param overrides map[int64]bool: The occurrences, by Unix start, replaced by
+ * another VEVENT (false) or cancelled (true); they are left out.
+ */
+func eventRows(event *ical.Component, studio *models.Studio, capacity int, overrides map[int64]bool) ([]importRow, error) {
+	uid := event.Get("UID")
+	if uid == nil || uid.Value == "" {
+		return nil, fmt.Errorf("missing UID")
+	}
+	for _, name := range []string{"RDATE", "EXRULE"} {
+		if event.Get(name) != nil {
+			return nil, fmt.Errorf("unsupported %s", name)
+		}
+	}
+	if isCancelled(event) {
+		return nil, fmt.Errorf("cancelled events are not imported")
+	}
+
+	var recurrenceId *time.Time
+	if property := event.Get("RECURRENCE-ID"); property != nil {
+		if event.Get("RRULE") != nil {
+			return nil, fmt.Errorf("unsupported RRULE on a RECURRENCE-ID override")
+		}
+		date, err := recurrenceTime(*property, studio)
+		if err != nil {
+			return nil, err
+		}
+		recurrenceId = &date
+	}
+
+	var warnings []string
+	for _, component := range event.Components {
+		warnings = append(warnings, component.Name+" ignored")
+	}
+
+	summary := ""
+	if property := event.Get("SUMMARY"); property != nil {
+		summary = ical.Unescape(property.Value)
+	}
+	name := className(summary)
+	if name != summary {
+		warnings = append(warnings, fmt.Sprintf("SUMMARY %q imported as %q", summary, name))
+	}
+
+	dtstart := event.Get("DTSTART")
+	if dtstart == nil {
+		return nil, fmt.Errorf("missing DTSTART")
+	}
+	loc, err := dtstart.Location(studio.Location())
+	if err != nil {
+		return nil, err
+	}
+	starts, err := ical.Times(*dtstart, loc)
+	if err != nil {
+		return nil, err
+	}
+	start := starts[0]
+
+	var length time.Duration
+	if dtend := event.Get("DTEND"); dtend != nil {
+		ends, err := ical.Times(*dtend, loc)
+		if err != nil {
+			return nil, err
+		}
+		length = ends[0].Sub(start)
+	} else if duration := event.Get("DURATION"); duration != nil {
+		if length, err = ical.Duration(duration.Value); err != nil {
+			return nil, err
+		}
+	} else {
+		return nil, fmt.Errorf("missing DTEND")
+	}
+
+	occurrences := []time.Time{start}
+	recurring := false
+	if property := event.Get("RRULE"); property != nil {
+		recurring = true
+		rule, err := ical.ParseRule(property.Value)
+		if err != nil {
+			return nil, err
+		}
+		var truncated bool
+		occurrences, truncated = rule.Expand(start, loc, maxOccurrences)
+		if truncated {
+			warnings = append(warnings, fmt.Sprintf("RRULE expanded to its first %d occurrences", maxOccurrences))
+		}
+	}
+
+	excluded := map[int64]bool{}
+	for _, property := range event.All("EXDATE") {
+		dates, err := ical.Times(property, loc)
+		if err != nil {
+			return nil, err
+		}
+		for _, date := range dates {
+			excluded[date.Unix()] = true
+		}
+	}
+
+	var rows []importRow
+	for _, occurrence := range occurrences {
+		if excluded[occurrence.Unix()] {
+			continue
+		}
+		if cancelled, ok := overrides[occurrence.Unix()]; ok && recurring {
+			if cancelled {
+				warnings = append(warnings, fmt.Sprintf("occurrence %s cancelled", occurrence.Format(time.RFC3339)))
+			}
+			continue
+		}
+
+		occurrence, occurrenceId := occurrence, recurrenceId
+		if recurring {
+			occurrenceId = &occurrence
+		}
+		rows = append(rows, importRow{
+			uid:          uid.Value,
+			recurrenceId: occurrenceId,
+			class: models.CreateClass{
+				Name:      name,
+				StudioId:  studio.ID,
+				StartDate: occurrence,
+				EndDate:   occurrence.Add(length),
+				Capacity:  capacity,
+			},
+		})
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("every occurrence is excluded")
+	}
+	rows[0].warnings = warnings
+	return rows, nil
+}
+
+// isCancelled reports whether the STATUS of event is CANCELLED.
+func isCancelled(event *ical.Component) bool {
+	status := event.Get("STATUS")
+	return status != nil && strings.EqualFold(status.Value, ical.StatusCancelled)
+}
+
+// recurrenceTime parses the RECURRENCE-ID of an occurrence override.
+func recurrenceTime(property ical.Property, studio *models.Studio) (time.Time, error) {
+	if property.Params["RANGE"] != "" {
+		return time.Time{}, fmt.Errorf("unsupported RECURRENCE-ID RANGE")
+	}
+	loc, err := property.Location(studio.Location())
+	if err != nil {
+		return time.Time{}, err
+	}
+	dates, err := ical.Times(property, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return dates[0], nil
+}
+
+// className keeps the characters of summary allowed in a class name.
+func className(summary string) string {
+	var name strings.Builder
+	for _, r := range summary {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			name.WriteRune(r)
+		}
+	}
+	if name.Len() > 20 {
+		return name.String()[:20]
+	}
+	return name.String()
+}
\ No newline at end of file
@@ -0,0 +1,303 @@
+package classes
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"go-api/pkg/ical"
+	"go-api/pkg/api/audit"
+	"go-api/pkg/models"
+	"go-api/pkg/mockDatabase"
+	"go-api/pkg/events"
+	"github.com/gin-gonic/gin"
+)
+
+const maxImportRows = 1000
+
+// importRow is a parsed row, or the reason it could not be parsed.
+type importRow struct {
+	class models.CreateClass
+	err   string
+	// uid is the iCalendar UID the row comes from, "" for JSON and CSV rows.
+	uid      string
+	// recurrenceId identifies the occurrence of a recurring event, nil for single events.
+	recurrenceId *time.Time
+	warnings []string
+}
+
+/**
+ * @brief ImportClasses creates many classes from a JSON array, a CSV file or an iCalendar file.
+ *
+ * Every row is checked with the rules of PostClasses. Rows imported from
+ * iCalendar keep their UID, so importing the same events again updates the
+ * classes instead of duplicating them. With ?mode=atomic (the
+ * default) nothing is written unless every row is valid; with
+ * ?mode=best_effort valid rows are created and invalid ones reported.
+ * ?dry_run=true only returns the per-row report.
+ *
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func ImportClasses(c *gin.Context) {
+	mode := c.DefaultQuery("mode", "atomic")
+	if mode != "atomic" && mode != "best_effort" {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid mode"})
+		return
+	}
+
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid dry_run"})
+		return
+	}
+
+	rows, err := decodeImport(c)
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report := models.ClassImportReport{Mode: mode, DryRun: dryRun, Total: len(rows), Rows: []models.ClassImportRow{}}
+	valid := make([]*models.ClassImportRow, 0, len(rows))
+
+	for i := range rows {
+		row := models.ClassImportRow{Row: i + 1, Status: "valid", UID: rows[i].uid, Warnings: rows[i].warnings}
+		if rows[i].err == "" {
+			if status, message := prepareClass(&rows[i].class); status != 0 {
+				rows[i].err = message
+			}
+		}
+		if rows[i].err != "" {
+			row.Status = "invalid"
+			row.Error = rows[i].err
+			report.Invalid++
+		}
+		report.Rows = append(report.Rows, row)
+	}
+
+	for i := range report.Rows {
+		if report.Rows[i].Status == "valid" {
+			valid = append(valid, &report.Rows[i])
+		}
+	}
+
+	if dryRun {
+		c.IndentedJSON(http.StatusOK, report)
+		return
+	}
+
+	if mode == "atomic" && report.Invalid > 0 {
+		report.Error = fmt.Sprintf("%d of %d rows are invalid, nothing was imported", report.Invalid, report.Total)
+		c.IndentedJSON(http.StatusUnprocessableEntity, report)
+		return
+	}
+
+	err = database.Transaction(func(tx *database.Tx) error {
+		for _, row := range valid {
+			class, status, err := storeImportRow(c, tx, rows[row.Row-1])
+			if err != nil {
+				return err
+			}
+			row.Status = status
+			row.Class = &class
+
+			switch status {
+			case "created":
+				report.Created++
+			case "updated":
+				report.Updated++
+			case "unchanged":
+				report.Unchanged++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Could not save Classes"})
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, report)
+}
+
+/**
+ * @brief storeImportRow creates the class of a valid row.
+ *
+ * A row with a UID updates the class previously imported from the same event,
+ * found by UID and, for occurrences of recurring events, RECURRENCE-ID,
+ * instead of creating a new one; moved events get their new dates in place.
+ *
+ * @param c *gin.Context: The import request, recorded in the audit log.
+ * @return string: "created", "updated" or "unchanged".
+ */
+func storeImportRow(c *gin.Context, tx *database.Tx, row importRow) (models.Class, string, error) {
+	if row.uid != "" {
+		for index, existing := range database.Classes {
+			if existing.SourceUID != row.uid || existing.DeletedAt != nil || !sameRecurrence(existing.SourceRecurrenceId, row.recurrenceId) {
+				continue
+			}
+			if existing.Name == row.class.Name && existing.StartDate.Equal(row.class.StartDate) && existing.StudioId == row.class.StudioId && existing.EndDate.Equal(row.class.EndDate) && existing.Capacity == row.class.Capacity && existing.Price == row.class.Price {
+				return existing, "unchanged", nil
+			}
+
+			updated := database.UpdateClass(models.UpdateClass{
+				Name:      row.class.Name,
+				StudioId:  row.class.StudioId,
+				StartDate: row.class.StartDate,
+				EndDate:   row.class.EndDate,
+				Capacity:  row.class.Capacity,
+				Price:     row.class.Price,
+			}, existing.ID, existing.Version+1)
+			updated.SourceUID = row.uid
+			updated.SourceRecurrenceId = row.recurrenceId
+			if err := tx.Record(events.ClassUpdated, updated.ID, updated); err != nil {
+				return existing, "", err
+			}
+			if err := audit.Record(c, tx, events.ClassUpdated, updated.ID, existing, updated); err != nil {
+				return existing, "", err
+			}
+			database.Classes[index] = updated
+			return updated, "updated", nil
+		}
+	}
+
+	class := database.CreateClass(row.class)
+	class.SourceUID = row.uid
+	class.SourceRecurrenceId = row.recurrenceId
+	if err := tx.Record(events.ClassCreated, class.ID, class); err != nil {
+		return class, "", err
+	}
+	if err := audit.Record(c, tx, events.ClassCreated, class.ID, nil, class); err != nil {
+		return class, "", err
+	}
+	database.Classes = append(database.Classes, class)
+	return class, "created", nil
+}
+
+// sameRecurrence reports whether two RECURRENCE-IDs name the same occurrence.
+func sameRecurrence(a *time.Time, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
+
+/**
+ * @brief decodeImport reads the rows of a JSON array or, for text/csv bodies, of a CSV file with a header.
+ *
+ * text/calendar bodies are decoded by decodeCalendar.
+ *
+ * Rows that cannot be parsed are kept with their error so that they show up in the report.
+ */
+func decodeImport(c *gin.Context) ([]importRow, error) {
+	req := c.Request
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+
+	var rows []importRow
+	switch mediaType {
+	case "application/json", "":
+		var raw []json.RawMessage
+		if err := json.NewDecoder(req.Body).Decode(&raw); err != nil {
+			return nil, fmt.Errorf("Invalid JSON array")
+		}
+		for _, item := range raw {
+			var row importRow
+			if err := json.Unmarshal(item, &row.class); err != nil {
+				row.err = "Invalid Class"
+			}
+			rows = append(rows, row)
+		}
+
+	case "text/csv":
+		reader := csv.NewReader(req.Body)
+		reader.FieldsPerRecord = -1
+		header, err := reader.Read()
+		if err != nil {
+			return nil, fmt.Errorf("Invalid CSV header")
+		}
+		for i := range header {
+			header[i] = strings.TrimSpace(header[i])
+			if !knownColumn(header[i]) {
+				return nil, fmt.Errorf("Unknown CSV column %q", header[i])
+			}
+		}
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("Invalid CSV: %v", err)
+			}
+			rows = append(rows, parseCSVRow(header, record))
+			if len(rows) > maxImportRows {
+				break
+			}
+		}
+
+	case ical.MediaType:
+		var err error
+		if rows, err = decodeCalendar(c); err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("Unsupported Content-Type %q", mediaType)
+	}
+
+	if len(rows) > maxImportRows {
+		return nil, fmt.Errorf("Too many rows, the limit is %d", maxImportRows)
+	}
+	return rows, nil
+}
+
+var csvColumns = []string{"name", "studio_id", "start_date", "end_date", "local_start_date", "local_end_date", "capacity", "price"}
+
+func knownColumn(column string) bool {
+	for _, known := range csvColumns {
+		if column == known {
+			return true
+		}
+	}
+	return false
+}
+
+func parseCSVRow(header []string, record []string) importRow {
+	var row importRow
+	for i, column := range header {
+		if i >= len(record) || strings.TrimSpace(record[i]) == "" {
+			continue
+		}
+		value := strings.TrimSpace(record[i])
+
+		var err error
+		switch column {
+		case "name":
+			row.class.Name = value
+		case "studio_id":
+			row.class.StudioId, err = strconv.Atoi(value)
+		case "capacity":
+			row.class.Capacity, err = strconv.Atoi(value)
+		case "price":
+			row.class.Price, err = strconv.Atoi(value)
+		case "start_date":
+			row.class.StartDate, err = time.Parse(time.RFC3339, value)
+		case "end_date":
+			row.class.EndDate, err = time.Parse(time.RFC3339, value)
+		case "local_start_date":
+			row.class.LocalStartDate = value
+		case "local_end_date":
+			row.class.LocalEndDate = value
+		}
+		if err != nil {
+			row.err = "Invalid " + column
+			return row
+		}
+	}
+	return row
+}
\ No newline at end of file
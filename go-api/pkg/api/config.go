@@ -0,0 +1,145 @@
+package api
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+	"go-api/pkg/api/bookings"
+	"go-api/pkg/api/middleware"
+	"go-api/pkg/notifications"
+	"go-api/pkg/payments"
+	"go-api/pkg/retention"
+	"go-api/pkg/scheduler"
+)
+
+type Config struct {
+	// IdempotencyStore keeps responses of POST requests sent with an Idempotency-Key.
+	IdempotencyStore middleware.IdempotencyStore `json:"-"`
+	// IdempotencyTTL is how long an Idempotency-Key can be replayed.
+	IdempotencyTTL time.Duration `json:"-"`
+
+	// APIKeys maps the accepted API keys to their principal.
+	APIKeys map[string]middleware.Principal `json:"api_keys"`
+	// Limiter counts requests for rate limiting.
+	Limiter middleware.Limiter `json:"-"`
+	// RateLimits holds the rate limit policy of each route group, "default" for the others.
+	RateLimits map[string]middleware.RateLimitPolicy `json:"rate_limits"`
+	// TrustedProxies lists the proxy addresses or CIDRs whose X-Forwarded-For header gives the client IP.
+	// None are trusted by default, so anonymous clients are limited by the address they connect from.
+	TrustedProxies []string `json:"trusted_proxies"`
+
+	// Cancellation decides when bookings can be cancelled and what late cancellations cost.
+	Cancellation bookings.CancellationPolicy `json:"cancellation"`
+
+	// CheckInSecret signs the check-in tokens shown as QR codes; a random one is used when empty,
+	// so codes stop working on restart and are not shared between instances.
+	CheckInSecret string `json:"checkin_secret"`
+	// CheckInTokenTTL is how long a check-in code stays valid.
+	CheckInTokenTTL time.Duration `json:"-"`
+
+	// Payments takes the payments of drop-in bookings of priced classes.
+	// DefaultConfig uses a fake provider signing its events with PaymentsSecret.
+	Payments payments.Provider `json:"-"`
+	// PaymentsSecret is the signing key of the fake provider's events; a random one is used when empty.
+	PaymentsSecret string `json:"payments_secret"`
+	// Currency is the ISO 4217 code of class prices.
+	Currency string `json:"currency"`
+
+	// Retention decides when soft-deleted classes and bookings are purged for good.
+	Retention retention.Policy `json:"retention"`
+
+	// WebhookAttempts is how many times an event is posted before it becomes a dead letter.
+	WebhookAttempts int `json:"webhook_attempts"`
+	// WebhookBackoff is the wait before the first webhook retry, doubled after each failure.
+	WebhookBackoff time.Duration `json:"-"`
+	// WebhookHistory is how many finished webhook deliveries, dead letters included, are kept in memory.
+	WebhookHistory int `json:"webhook_history"`
+
+	// SMTP is the server notification emails are sent through; none are sent without an address.
+	SMTP notifications.SMTPConfig `json:"smtp"`
+
+	// Jobs holds the pending background jobs, such as class reminders.
+	Jobs scheduler.Store `json:"-"`
+	// JobsDir keeps the jobs in a file in this directory, so that they survive restarts.
+	// Instances sharing the directory never run the same job twice.
+	JobsDir string `json:"jobs_dir"`
+
+	CORS            middleware.CORSConfig            `json:"cors"`
+	SecurityHeaders middleware.SecurityHeadersConfig `json:"security_headers"`
+
+	// ValidateRequests rejects request bodies that do not match the OpenAPI document.
+	ValidateRequests bool `json:"validate_requests"`
+	// ValidateResponses logs responses that do not match the OpenAPI document.
+	ValidateResponses bool `json:"validate_responses"`
+}
+
+/**
+ * @brief DefaultConfig returns the configuration used by InitRouter, backed by in-memory stores.
+ */
+func DefaultConfig() Config {
+	return Config{
+		IdempotencyStore: middleware.NewMemoryIdempotencyStore(),
+		IdempotencyTTL:   24 * time.Hour,
+		APIKeys:          map[string]middleware.Principal{},
+		Limiter:          middleware.NewMemoryLimiter(),
+		RateLimits: map[string]middleware.RateLimitPolicy{
+			"default": {
+				APIKey: middleware.RateLimit{Requests: 600, Period: time.Minute},
+				Member: middleware.RateLimit{Requests: 300, Period: time.Minute},
+				IP:     middleware.RateLimit{Requests: 300, Period: time.Minute},
+			},
+			"bookings": {
+				APIKey: middleware.RateLimit{Requests: 300, Period: time.Minute},
+				Member: middleware.RateLimit{Requests: 60, Period: time.Minute},
+				IP:     middleware.RateLimit{Requests: 60, Period: time.Minute},
+			},
+		},
+		TrustedProxies:  []string{},
+		Cancellation:    bookings.DefaultCancellationPolicy(),
+		CheckInTokenTTL: 2 * time.Minute,
+		Payments:        payments.NewFake(nil),
+		Currency:        "usd",
+		Retention:       retention.DefaultPolicy(),
+		WebhookAttempts: 6,
+		WebhookBackoff:  time.Second,
+		WebhookHistory:  1000,
+		SMTP:            notifications.SMTPConfig{From: "Studio <no-reply@example.com>"},
+		Jobs:            scheduler.NewMemoryStore(),
+		CORS:            middleware.DefaultCORSConfig(),
+		SecurityHeaders: middleware.DefaultSecurityHeadersConfig(),
+	}
+}
+
+/**
+ * @brief LoadConfig reads a JSON configuration file on top of DefaultConfig.
+ *
+ * @param path string: The configuration file, "" for the defaults.
+ */
+func LoadConfig(path string) (Config, error) {
+	config := DefaultConfig()
+	if path == "" {
+		return config, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, err
+	}
+
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, err
+	}
+
+	if config.PaymentsSecret != "" {
+		config.Payments = payments.NewFake([]byte(config.PaymentsSecret))
+	}
+
+	if config.JobsDir != "" {
+		store, err := scheduler.NewFileStore(config.JobsDir)
+		if err != nil {
+			return config, err
+		}
+		config.Jobs = store
+	}
+	return config, nil
+}
\ No newline at end of file
@@ -0,0 +1,119 @@
+package etag
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrModified is returned by Check when the resource changed since the client read it.
+var ErrModified = errors.New("resource has been modified")
+
+/**
+ * @brief For returns the strong ETag of one version of a resource.
+ *
+ * @param kind string: The resource kind, e.g. "class".
+ * @param id int: The resource ID.
+ * @param version int: The resource version.
+ * @param variant string: The representation variant (e.g. the ?tz= zone), "" for the default one.
+ * @return string: The quoted entity tag.
+ */
+func For(kind string, id int, version int, variant string) string {
+	tag := fmt.Sprintf("%s-%d-v%d", kind, id, version)
+	if variant != "" {
+		tag += ";" + variant
+	}
+	return `"` + tag + `"`
+}
+
+/**
+ * @brief ForList returns the strong ETag of a list built from the tags of its items.
+ *
+ * @param kind string: The resource kind, e.g. "classes".
+ * @param tags []string: The ETags of the items, in order.
+ * @return string: The quoted entity tag.
+ */
+func ForList(kind string, tags []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(tags, ",")))
+	return `"` + kind + "-" + hex.EncodeToString(sum[:8]) + `"`
+}
+
+/**
+ * @brief IfMatch reports whether an If-Match header allows modifying the resource.
+ *
+ * Any representation variant of the current version matches, so a tag read
+ * with ?tz= can be sent back unchanged. Weak tags never match.
+ *
+ * @param header string: The If-Match header value.
+ * @param current string: The ETag of the current version, without variant.
+ * @return bool: true when the header is empty, "*" or lists the current version.
+ */
+func IfMatch(header string, current string) bool {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return true
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if strings.HasPrefix(tag, "W/") {
+			continue
+		}
+		if withoutVariant(tag) == current {
+			return true
+		}
+	}
+	return false
+}
+
+/**
+ * @brief Check compares an If-Match header with the version of a resource read under the database lock.
+ *
+ * Handlers check If-Match again inside the transaction writing the resource,
+ * so that two requests sent with the same ETag cannot both succeed.
+ *
+ * @param header string: The If-Match header value.
+ * @param kind string: The resource kind, e.g. "class".
+ * @param id int: The resource ID.
+ * @param version int: The stored version.
+ * @return error: ErrModified when the header does not match (see IfMatch).
+ */
+func Check(header string, kind string, id int, version int) error {
+	if !IfMatch(header, For(kind, id, version, "")) {
+		return ErrModified
+	}
+	return nil
+}
+
+/**
+ * @brief IfNoneMatch reports whether an If-None-Match header already holds the representation.
+ *
+ * @param header string: The If-None-Match header value.
+ * @param current string: The ETag of the representation about to be sent.
+ * @return bool: true when the client copy is current and 304 should be returned.
+ */
+func IfNoneMatch(header string, current string) bool {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return false
+	}
+	if header == "*" {
+		return true
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == current {
+			return true
+		}
+	}
+	return false
+}
+
+func withoutVariant(tag string) string {
+	if i := strings.Index(tag, ";"); i >= 0 {
+		return tag[:i] + `"`
+	}
+	return tag
+}
\ No newline at end of file
@@ -0,0 +1,191 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Formats accepted by New.
+const (
+	CSV  = "csv"
+	XLSX = "xlsx"
+)
+
+// MediaTypes maps each format to the Content-Type of the response.
+var MediaTypes = map[string]string{
+	CSV:  "text/csv",
+	XLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// flushEvery is the number of rows buffered before they are sent to the client.
+const flushEvery = 100
+
+// Writer streams the rows of a table to an HTTP response.
+type Writer interface {
+	Write(row []string) error
+	Close() error
+}
+
+/**
+ * @brief New starts a download of a table in the given format.
+ *
+ * It sets the Content-Type and Content-Disposition headers; rows are flushed
+ * to the client as they are written so large tables are not buffered in memory.
+ *
+ * @param w http.ResponseWriter: The response to write to.
+ * @param format string: CSV or XLSX.
+ * @param name string: The file name, without extension.
+ * @return Writer: The table writer; Close must be called to finish the file.
+ */
+func New(w http.ResponseWriter, format string, name string) (Writer, error) {
+	mediaType, ok := MediaTypes[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown export format %q", format)
+	}
+
+	w.Header().Set("Content-Type", mediaType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, name, format))
+	w.WriteHeader(http.StatusOK)
+
+	if format == XLSX {
+		return newSheetWriter(w, name)
+	}
+	return &csvWriter{csv: csv.NewWriter(w), w: w}, nil
+}
+
+type csvWriter struct {
+	csv  *csv.Writer
+	w    http.ResponseWriter
+	rows int
+}
+
+func (writer *csvWriter) Write(row []string) error {
+	if err := writer.csv.Write(row); err != nil {
+		return err
+	}
+	writer.rows++
+	if writer.rows%flushEvery == 0 {
+		writer.csv.Flush()
+		flush(writer.w)
+	}
+	return writer.csv.Error()
+}
+
+func (writer *csvWriter) Close() error {
+	writer.csv.Flush()
+	return writer.csv.Error()
+}
+
+// sheetWriter writes a single-sheet XLSX workbook. Cells are inline strings, so
+// no shared string table has to be built before the rows are streamed.
+type sheetWriter struct {
+	zip   *zip.Writer
+	sheet io.Writer
+	w     http.ResponseWriter
+	rows  int
+}
+
+func newSheetWriter(w http.ResponseWriter, name string) (*sheetWriter, error) {
+	archive := zip.NewWriter(w)
+
+	parts := []struct{ path, content string }{
+		{"[Content_Types].xml", contentTypesXML},
+		{"_rels/.rels", relsXML},
+		{"xl/workbook.xml", fmt.Sprintf(workbookXML, escape(name))},
+		{"xl/_rels/workbook.xml.rels", workbookRelsXML},
+	}
+	for _, part := range parts {
+		file, err := archive.Create(part.path)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(file, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	sheet, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(sheet, sheetHeaderXML); err != nil {
+		return nil, err
+	}
+
+	return &sheetWriter{zip: archive, sheet: sheet, w: w}, nil
+}
+
+func (writer *sheetWriter) Write(row []string) error {
+	writer.rows++
+
+	var line strings.Builder
+	line.WriteString(`<row r="` + strconv.Itoa(writer.rows) + `">`)
+	for _, value := range row {
+		line.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">` + escape(value) + `</t></is></c>`)
+	}
+	line.WriteString(`</row>`)
+
+	if _, err := io.WriteString(writer.sheet, line.String()); err != nil {
+		return err
+	}
+	if writer.rows%flushEvery == 0 {
+		if err := writer.zip.Flush(); err != nil {
+			return err
+		}
+		flush(writer.w)
+	}
+	return nil
+}
+
+func (writer *sheetWriter) Close() error {
+	if _, err := io.WriteString(writer.sheet, sheetFooterXML); err != nil {
+		return err
+	}
+	return writer.zip.Close()
+}
+
+func flush(w http.ResponseWriter) {
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func escape(value string) string {
+	var escaped strings.Builder
+	xml.EscapeText(&escaped, []byte(value))
+	return escaped.String()
+}
+
+const contentTypesXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+</Types>`
+
+const relsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+const workbookXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`
+
+const workbookRelsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+</Relationships>`
+
+const sheetHeaderXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+
+const sheetFooterXML = `</sheetData></worksheet>`
\ No newline at end of file
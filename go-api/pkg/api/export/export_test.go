@@ -0,0 +1,114 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSVQuoting(t *testing.T) {
+	w := httptest.NewRecorder()
+	writer, err := New(w, CSV, "bookings")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows := [][]string{
+		{"id", "name", "notes"},
+		{"1", "O'Brien, Ana", `Said "hi"`},
+		{"2", "Diego", "two\nlines"},
+	}
+	for _, row := range rows {
+		assert.NoError(t, writer.Write(row))
+	}
+	assert.NoError(t, writer.Close())
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="bookings.csv"`, w.Header().Get("Content-Disposition"))
+
+	// Commas, quotes and newlines are quoted, and read back as written
+	assert.Contains(t, w.Body.String(), `1,"O'Brien, Ana","Said ""hi"""`)
+	assert.Contains(t, w.Body.String(), "2,Diego,\"two\nlines\"")
+	read, err := csv.NewReader(bytes.NewReader(w.Body.Bytes())).ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, rows, read)
+}
+
+func TestXLSXSheet(t *testing.T) {
+	w := httptest.NewRecorder()
+	writer, err := New(w, XLSX, "R&D <bookings>")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NoError(t, writer.Write([]string{"id", "name"}))
+	assert.NoError(t, writer.Write([]string{"1", `Tom & Jerry <"yoga">`}))
+	for i := 2; i <= 150; i++ {
+		assert.NoError(t, writer.Write([]string{strconv.Itoa(i), "Member"}))
+	}
+	assert.NoError(t, writer.Close())
+
+	assert.Equal(t, MediaTypes[XLSX], w.Header().Get("Content-Type"))
+
+	// The download is a zip archive holding the workbook parts
+	archive, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := map[string]string{}
+	for _, file := range archive.File {
+		reader, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := io.ReadAll(reader)
+		reader.Close()
+		parts[file.Name] = string(content)
+	}
+	for _, path := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml"} {
+		assert.Contains(t, parts, path)
+	}
+	assert.Contains(t, parts["xl/workbook.xml"], `<sheet name="R&amp;D &lt;bookings&gt;"`)
+
+	// Cell values are escaped, and the sheet is well-formed XML
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet, `<row r="2"><c t="inlineStr"><is><t xml:space="preserve">1</t></is></c><c t="inlineStr"><is><t xml:space="preserve">Tom &amp; Jerry &lt;&#34;yoga&#34;&gt;</t></is></c></row>`)
+
+	var parsed struct {
+		Rows []struct {
+			R     int      `xml:"r,attr"`
+			Cells []string `xml:"c>is>t"`
+		} `xml:"sheetData>row"`
+	}
+	if err := xml.Unmarshal([]byte(sheet), &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, parsed.Rows, 151) {
+		assert.Equal(t, []string{"id", "name"}, parsed.Rows[0].Cells)
+		assert.Equal(t, []string{"1", `Tom & Jerry <"yoga">`}, parsed.Rows[1].Cells)
+		assert.Equal(t, 151, parsed.Rows[150].R)
+	}
+}
+
+func TestUnknownFormat(t *testing.T) {
+	w := httptest.NewRecorder()
+	writer, err := New(w, "pdf", "bookings")
+
+	// Nothing is written, so the caller can still answer with an error
+	assert.Error(t, err)
+	assert.Nil(t, writer)
+	assert.Empty(t, w.Header())
+	assert.False(t, w.Flushed)
+	assert.Zero(t, w.Body.Len())
+
+	w.WriteHeader(http.StatusBadRequest)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
\ No newline at end of file
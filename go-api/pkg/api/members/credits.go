@@ -0,0 +1,91 @@
+package members
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+	"go-api/pkg/models"
+	"go-api/pkg/api/middleware"
+	"go-api/pkg/credits"
+	"go-api/pkg/mockDatabase"
+	"github.com/gin-gonic/gin"
+)
+
+/**
+ * @brief GetMemberCredits returns the class credit balance of a member, their active membership and their credit ledger.
+ *
+ * Members can only read their own credits.
+ * 
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func GetMemberCredits(c *gin.Context) {
+	id, ok := creditsMemberID(c)
+	if !ok {
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, credits.Ledger(id, time.Now()))
+}
+
+/**
+ * @brief PostMemberPlan gives a plan to a member: a pack adds class credits, a membership covers bookings for its days.
+ * 
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func PostMemberPlan(c *gin.Context) {
+	id, ok := creditsMemberID(c)
+	if !ok {
+		return
+	}
+
+	var purchase models.PurchasePlan
+
+	if err := c.ShouldBindJSON(&purchase); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid Plan"})
+		return
+	}
+
+	if err := models.PlanValidate.Struct(purchase); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid Plan"})
+		return
+	}
+
+	plan, _ := database.FindItemByID(database.Plans, purchase.PlanId)
+	if plan == nil {
+		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Plan not found"})
+		return
+	}
+
+	at := time.Now()
+	err := database.Transaction(func(tx *database.Tx) error {
+		credits.Purchase(id, *plan.(*models.Plan), at)
+		return nil
+	})
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Could not save Plan"})
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, credits.Ledger(id, at))
+}
+
+func creditsMemberID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return 0, false
+	}
+
+	if principal, _ := middleware.CurrentPrincipal(c); principal.Role == "member" && principal.MemberId != id {
+		c.IndentedJSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
+		return 0, false
+	}
+
+	existingMember, _ := database.FindItemByID(database.Members, id)
+	if existingMember == nil {
+		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Member not found"})
+		return 0, false
+	}
+	return id, true
+}
\ No newline at end of file
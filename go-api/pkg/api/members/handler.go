@@ -0,0 +1,144 @@
+package members
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"go-api/pkg/models"
+	"go-api/pkg/api/middleware"
+	"go-api/pkg/api/openapi"
+	"go-api/pkg/attendance"
+	"go-api/pkg/mockDatabase"
+	"github.com/gin-gonic/gin"
+)
+
+/**
+ * @brief GetMembers returns a list of all members, with their emails. Staff and admins only.
+ * 
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func GetMembers(c *gin.Context) {
+	c.IndentedJSON(http.StatusOK, database.Members)
+}
+
+/**
+ * @brief GetMemberByID returns a member by its ID, with their email. Staff and admins only.
+ * 
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func GetMemberByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	existingMember, index := database.FindItemByID(database.Members, id)
+	if existingMember == nil {
+		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Member not found"})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, database.Members[index])
+}
+
+/**
+ * @brief GetMemberAttendance returns the bookings of a member with their attendance and no-show counts.
+ * 
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func GetMemberAttendance(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	existingMember, _ := database.FindItemByID(database.Members, id)
+	if existingMember == nil {
+		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Member not found"})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, attendance.History(*existingMember.(*models.Member)))
+}
+
+/**
+ * @brief PostMembers creates a new member. Staff and admins only; names are unique because bookings refer to members by name.
+ * 
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func PostMembers(c *gin.Context) {
+	var newMember models.CreateMember
+
+	if err := c.ShouldBindJSON(&newMember); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid Member"})
+		return
+	}
+
+	if err := models.MemberValidate.Struct(newMember); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid Member"})
+		return
+	}
+
+	for _, member := range database.Members {
+		if strings.EqualFold(member.Name, newMember.Name) {
+			c.IndentedJSON(http.StatusConflict, gin.H{"error": "Member already exists"})
+			return
+		}
+	}
+
+	var member models.Member = database.CreateMember(newMember)
+
+	database.Members = append(database.Members, member)
+	c.IndentedJSON(http.StatusCreated, member)
+}
+
+/**
+ * @brief PostMemberFeed issues a new private calendar feed URL for a member.
+ *
+ * The previous URL stops working. Members can only issue their own feed; admins any.
+ * 
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func PostMemberFeed(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if principal, _ := middleware.CurrentPrincipal(c); principal.Role != "admin" && principal.MemberId != id {
+		c.IndentedJSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
+		return
+	}
+
+	existingMember, index := database.FindItemByID(database.Members, id)
+	if existingMember == nil {
+		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Member not found"})
+		return
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Could not create feed token"})
+		return
+	}
+	token := hex.EncodeToString(secret)
+	database.Members[index].FeedToken = token
+
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+
+	c.IndentedJSON(http.StatusCreated, models.MemberFeed{
+		Token: token,
+		URL:   scheme + "://" + c.Request.Host + openapi.BasePath + "/calendar/" + token + ".ics",
+	})
+}
\ No newline at end of file
@@ -0,0 +1,204 @@
+package members
+
+import (
+	"bytes"
+	"go-api/pkg/models"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"go-api/pkg/mockDatabase"
+	"testing"
+	"time"
+	"go-api/pkg/api/middleware"
+	"go-api/pkg/api/openapi"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// newRouter returns a test Gin router that fails the test on responses not matching the OpenAPI spec.
+func newRouter(t *testing.T) *gin.Engine {
+	router := gin.Default()
+	router.Use(openapi.ValidateResponses(func(violation string) { t.Error(violation) }))
+	return router
+}
+
+func TestPostMembers(t *testing.T) {
+	// Create a test Gin router authenticating a member and the front desk
+	router := newRouter(t)
+	router.Use(middleware.Authenticate(map[string]middleware.Principal{
+		"martin-key": {Name: "martin", Role: "member", MemberId: 2},
+		"staff-key":  {Name: "front desk", Role: "staff"},
+	}))
+	router.POST("/members", middleware.RequireRole("admin", "staff"), PostMembers)
+	router.GET("/members", middleware.RequireRole("admin", "staff"), GetMembers)
+	router.GET("/members/:id", middleware.RequireRole("admin", "staff"), GetMemberByID)
+
+	request := func(method string, path string, key string, body []byte) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Add("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Add(middleware.APIKeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	newMemberJSON, _ := json.Marshal(models.CreateMember{Name: "Lucia"})
+
+	// Create a POST request to create a new member
+	w := request(http.MethodPost, "/members", "staff-key", newMemberJSON)
+
+	// Assert that the HTTP status code is Created (201)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var member models.Member
+	err := json.Unmarshal(w.Body.Bytes(), &member)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "Lucia", member.Name)
+
+	// Member names are unique, so a second Lucia is a Conflict (409)
+	w = request(http.MethodPost, "/members", "staff-key", newMemberJSON)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	// Members and their emails are for the front desk only
+	w = request(http.MethodGet, "/members/1", "staff-key", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "diego@example.com")
+	for _, path := range []string{"/members", "/members/1"} {
+		assert.Equal(t, http.StatusUnauthorized, request(http.MethodGet, path, "", nil).Code)
+		assert.Equal(t, http.StatusForbidden, request(http.MethodGet, path, "martin-key", nil).Code)
+	}
+	assert.Equal(t, http.StatusUnauthorized, request(http.MethodPost, "/members", "", newMemberJSON).Code)
+	assert.Equal(t, http.StatusForbidden, request(http.MethodPost, "/members", "martin-key", newMemberJSON).Code)
+}
+
+func TestPostMemberFeed(t *testing.T) {
+	// Create a test Gin router authenticating a member and an admin
+	router := newRouter(t)
+	router.Use(middleware.Authenticate(map[string]middleware.Principal{
+		"diego-key": {Name: "diego", Role: "member", MemberId: 1},
+		"admin-key": {Name: "owner", Role: "admin"},
+	}))
+	router.POST("/members/:id/feed", middleware.RequireRole("admin", "member"), PostMemberFeed)
+
+	post := func(path string, key string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodPost, path, nil)
+		req.Header.Add(middleware.APIKeyHeader, key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// A member gets a private URL for their own feed
+	w := post("/members/1/feed", "diego-key")
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var feed models.MemberFeed
+	err := json.Unmarshal(w.Body.Bytes(), &feed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, feed.Token, 64)
+	assert.True(t, strings.HasSuffix(feed.URL, "/api/calendar/"+feed.Token+".ics"))
+	assert.Equal(t, feed.Token, database.Members[0].FeedToken)
+
+	// but not for someone else's, which only admins can issue
+	assert.Equal(t, http.StatusForbidden, post("/members/2/feed", "diego-key").Code)
+	assert.Equal(t, http.StatusCreated, post("/members/2/feed", "admin-key").Code)
+
+	// Issuing a new URL revokes the previous one
+	post("/members/1/feed", "admin-key")
+	assert.NotEqual(t, feed.Token, database.Members[0].FeedToken)
+}
+
+func TestGetMemberAttendance(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.GET("/members/:id/attendance", GetMemberAttendance)
+
+	attended := database.CreateBooking(models.CreateBooking{Name: "diego", ClassId: 1, Date: time.Date(2023, 10, 8, 16, 0, 0, 0, time.UTC)})
+	attended.Status = models.BookingAttended
+	missed := database.CreateBooking(models.CreateBooking{Name: "Diego", ClassId: 1, Date: time.Date(2023, 10, 9, 16, 0, 0, 0, time.UTC)})
+	missed.Status = models.BookingNoShow
+	database.Bookings = append(database.Bookings, attended, missed)
+
+	req, _ := http.NewRequest(http.MethodGet, "/members/1/attendance", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert that the HTTP status code is OK (200)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var attendance models.Attendance
+	if err := json.Unmarshal(w.Body.Bytes(), &attendance); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, attendance.Attended)
+	assert.Equal(t, 1, attendance.NoShows)
+	if assert.Len(t, attendance.History, 3) {
+		assert.Equal(t, missed.ID, attendance.History[0].BookingId)
+		assert.Equal(t, "Yoga", attendance.History[0].ClassName)
+	}
+
+	// Unknown members are Not Found (404)
+	req, _ = http.NewRequest(http.MethodGet, "/members/999/attendance", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestMemberPlans(t *testing.T) {
+	// Create a test Gin router authenticating a member and the front desk
+	router := newRouter(t)
+	router.Use(middleware.Authenticate(map[string]middleware.Principal{
+		"martin-key": {Name: "martin", Role: "member", MemberId: 2},
+		"staff-key":  {Name: "front desk", Role: "staff"},
+	}))
+	router.GET("/members/:id/credits", middleware.RequireRole("admin", "staff", "member"), GetMemberCredits)
+	router.POST("/members/:id/plans", middleware.RequireRole("admin", "staff"), PostMemberPlan)
+
+	request := func(method string, path string, key string, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Add("Content-Type", "application/json")
+		req.Header.Add(middleware.APIKeyHeader, key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// The front desk sells Martin, who starts with a 10-class pack, another pack and a monthly membership
+	w := request(http.MethodPost, "/members/2/plans", "staff-key", `{"plan_id": 1}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	w = request(http.MethodPost, "/members/2/plans", "staff-key", `{"plan_id": 2}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var credits models.Credits
+	if err := json.Unmarshal(w.Body.Bytes(), &credits); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 20, credits.Balance)
+	if assert.NotNil(t, credits.Membership) {
+		assert.Equal(t, "Unlimited monthly", credits.Membership.Name)
+	}
+
+	// Martin reads his own ledger, but cannot buy plans or read other ledgers
+	w = request(http.MethodGet, "/members/2/credits", "martin-key", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	if err := json.Unmarshal(w.Body.Bytes(), &credits); err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, credits.Ledger, 2) {
+		assert.Equal(t, 10, credits.Ledger[1].Amount)
+		assert.Equal(t, "purchase", credits.Ledger[1].Reason)
+	}
+	assert.Equal(t, http.StatusForbidden, request(http.MethodGet, "/members/1/credits", "martin-key", "").Code)
+	assert.Equal(t, http.StatusForbidden, request(http.MethodPost, "/members/2/plans", "martin-key", `{"plan_id": 1}`).Code)
+
+	// Unknown plans are Not Found (404)
+	assert.Equal(t, http.StatusNotFound, request(http.MethodPost, "/members/2/plans", "staff-key", `{"plan_id": 99}`).Code)
+}
\ No newline at end of file
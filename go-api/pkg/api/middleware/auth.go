@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHeader is the request header carrying the client API key.
+const APIKeyHeader = "X-API-Key"
+
+const principalKey = "principal"
+
+// Principal is the caller identified by an API key.
+type Principal struct {
+	Name     string `json:"name"`
+	Role     string `json:"role"`
+	MemberId int    `json:"member_id,omitempty"`
+}
+
+/**
+ * @brief Authenticate identifies the caller from its API key.
+ *
+ * The key is read from X-API-Key or an "Authorization: Bearer" header.
+ * Anonymous requests go through; unknown keys are rejected with 401.
+ *
+ * @param keys map[string]Principal: The known API keys.
+ */
+func Authenticate(keys map[string]Principal) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := apiKey(c)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		principal, ok := keys[key]
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+			return
+		}
+
+		c.Set(principalKey, principal)
+		c.Next()
+	}
+}
+
+/**
+ * @brief RequireRole rejects requests whose principal does not have one of the roles.
+ *
+ * @param roles ...string: The accepted roles.
+ */
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := CurrentPrincipal(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			return
+		}
+
+		for _, role := range roles {
+			if principal.Role == role {
+				c.Next()
+				return
+			}
+		}
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
+	}
+}
+
+/**
+ * @brief IncludeDeleted reads ?include_deleted=, which only admins may set.
+ *
+ * @param c *gin.Context: The Gin HTTP context, answered with 400 or 403 when the parameter is refused.
+ * @return bool: Whether soft-deleted classes and bookings are included.
+ * @return bool: false when the response has been written.
+ */
+func IncludeDeleted(c *gin.Context) (bool, bool) {
+	value := c.Query("include_deleted")
+	if value == "" {
+		return false, true
+	}
+
+	include, err := strconv.ParseBool(value)
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid filter"})
+		return false, false
+	}
+	if principal, _ := CurrentPrincipal(c); include && principal.Role != "admin" {
+		c.IndentedJSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
+		return false, false
+	}
+	return include, true
+}
+
+/**
+ * @brief CurrentPrincipal returns the authenticated caller, if any.
+ */
+func CurrentPrincipal(c *gin.Context) (Principal, bool) {
+	value, ok := c.Get(principalKey)
+	if !ok {
+		return Principal{}, false
+	}
+	principal, ok := value.(Principal)
+	return principal, ok
+}
+
+func apiKey(c *gin.Context) string {
+	if key := c.GetHeader(APIKeyHeader); key != "" {
+		return key
+	}
+	if auth := c.GetHeader("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimPrefix(auth, "Bearer ")
+	}
+	return ""
+}
\ No newline at end of file
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newAuthRouter() *gin.Engine {
+	keys := map[string]Principal{
+		"admin-key":  {Name: "owner", Role: "admin"},
+		"member-key": {Name: "diego", Role: "member", MemberId: 1},
+	}
+
+	router := gin.Default()
+	router.Use(Authenticate(keys))
+	router.GET("/public", func(c *gin.Context) { c.IndentedJSON(http.StatusOK, gin.H{}) })
+	router.GET("/admin", RequireRole("admin"), func(c *gin.Context) { c.IndentedJSON(http.StatusOK, gin.H{}) })
+	return router
+}
+
+func TestAuthenticate(t *testing.T) {
+	router := newAuthRouter()
+
+	// Anonymous requests go through
+	assert.Equal(t, http.StatusOK, get(router, "/public", "").Code)
+
+	// Unknown keys are rejected with Unauthorized (401)
+	assert.Equal(t, http.StatusUnauthorized, get(router, "/public", "wrong").Code)
+
+	// Bearer tokens are accepted as well
+	req, _ := http.NewRequest(http.MethodGet, "/admin", nil)
+	req.Header.Add("Authorization", "Bearer admin-key")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRequireRole(t *testing.T) {
+	router := newAuthRouter()
+
+	assert.Equal(t, http.StatusUnauthorized, get(router, "/admin", "").Code)
+	assert.Equal(t, http.StatusForbidden, get(router, "/admin", "member-key").Code)
+	assert.Equal(t, http.StatusOK, get(router, "/admin", "admin-key").Code)
+}
\ No newline at end of file
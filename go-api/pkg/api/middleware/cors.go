@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"github.com/gin-gonic/gin"
+)
+
+type CORSConfig struct {
+	// AllowedOrigins lists the browser origins allowed to call the API, "*" for any. Empty disables CORS.
+	AllowedOrigins   []string `json:"allowed_origins"`
+	AllowedMethods   []string `json:"allowed_methods"`
+	AllowedHeaders   []string `json:"allowed_headers"`
+	ExposedHeaders   []string `json:"exposed_headers"`
+	AllowCredentials bool     `json:"allow_credentials"`
+	// MaxAge is how long, in seconds, browsers may cache a preflight response.
+	MaxAge int `json:"max_age"`
+}
+
+/**
+ * @brief DefaultCORSConfig returns the methods and headers used by the API, with no origin allowed.
+ */
+func DefaultCORSConfig() CORSConfig {
+	return CORSConfig{
+		AllowedMethods: []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete},
+		AllowedHeaders: []string{"Content-Type", "Authorization", APIKeyHeader, IdempotencyHeader, RequestIDHeader, "If-Match", "If-None-Match"},
+		ExposedHeaders: []string{"ETag", "Idempotent-Replayed", RequestIDHeader, "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After"},
+		MaxAge:         600,
+	}
+}
+
+/**
+ * @brief CORS adds Cross-Origin Resource Sharing headers for allowed origins and answers preflight requests.
+ *
+ * Listed origins are echoed back rather than "*" so that credentials can be
+ * allowed. Origins only matched by "*" get a literal "*" and never
+ * credentials, so that any site cannot make credentialed requests.
+ * Preflights from other origins are rejected with 403.
+ *
+ * @param config CORSConfig: The allowed origins, methods and headers.
+ */
+func CORS(config CORSConfig) gin.HandlerFunc {
+	methods := strings.Join(config.AllowedMethods, ", ")
+	headers := strings.Join(config.AllowedHeaders, ", ")
+	exposed := strings.Join(config.ExposedHeaders, ", ")
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if len(config.AllowedOrigins) == 0 || origin == "" {
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Origin")
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+
+		allowed, wildcard := originAllowed(config.AllowedOrigins, origin)
+		if !allowed {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		if wildcard {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			c.Header("Access-Control-Allow-Origin", origin)
+		}
+		if config.AllowCredentials && !wildcard {
+			c.Header("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			if exposed != "" {
+				c.Header("Access-Control-Expose-Headers", exposed)
+			}
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Access-Control-Request-Method")
+		c.Writer.Header().Add("Vary", "Access-Control-Request-Headers")
+		c.Header("Access-Control-Allow-Methods", methods)
+		c.Header("Access-Control-Allow-Headers", headers)
+		if config.MaxAge > 0 {
+			c.Header("Access-Control-Max-Age", strconv.Itoa(config.MaxAge))
+		}
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+/**
+ * @brief originAllowed checks origin against the allowed origins.
+ *
+ * @return bool: Whether the origin is allowed.
+ * @return bool: Whether it is only allowed by "*".
+ */
+func originAllowed(allowed []string, origin string) (bool, bool) {
+	wildcard := false
+	for _, candidate := range allowed {
+		if strings.EqualFold(candidate, origin) {
+			return true, false
+		}
+		if candidate == "*" {
+			wildcard = true
+		}
+	}
+	return wildcard, wildcard
+}
\ No newline at end of file
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newCORSRouter() *gin.Engine {
+	config := DefaultCORSConfig()
+	config.AllowedOrigins = []string{"https://widget.example.com"}
+	config.AllowCredentials = true
+
+	router := gin.Default()
+	router.Use(CORS(config))
+	router.POST("/api/bookings", func(c *gin.Context) { c.IndentedJSON(http.StatusCreated, gin.H{}) })
+	return router
+}
+
+func preflight(router *gin.Engine, origin string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodOptions, "/api/bookings", nil)
+	req.Header.Add("Origin", origin)
+	req.Header.Add("Access-Control-Request-Method", http.MethodPost)
+	req.Header.Add("Access-Control-Request-Headers", "Content-Type, Idempotency-Key")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCORSPreflight(t *testing.T) {
+	router := newCORSRouter()
+
+	w := preflight(router, "https://widget.example.com")
+
+	// Assert that the preflight is answered with No Content (204) and the allowed methods and headers
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "https://widget.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Contains(t, w.Header().Get("Access-Control-Allow-Methods"), http.MethodPost)
+	assert.Contains(t, w.Header().Get("Access-Control-Allow-Headers"), IdempotencyHeader)
+	assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+}
+
+func TestCORSPreflightDisallowedOrigin(t *testing.T) {
+	router := newCORSRouter()
+
+	w := preflight(router, "https://evil.example.com")
+
+	// Assert that the HTTP status code is Forbidden (403) and no CORS header is sent
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORSSimpleRequest(t *testing.T) {
+	router := newCORSRouter()
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/bookings", nil)
+	req.Header.Add("Origin", "https://widget.example.com")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "https://widget.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Contains(t, w.Header().Get("Access-Control-Expose-Headers"), "ETag")
+	assert.Equal(t, "Origin", w.Header().Get("Vary"))
+}
+func TestCORSWildcardWithCredentials(t *testing.T) {
+	config := DefaultCORSConfig()
+	config.AllowedOrigins = []string{"https://widget.example.com", "*"}
+	config.AllowCredentials = true
+
+	router := gin.Default()
+	router.Use(CORS(config))
+	router.POST("/api/bookings", func(c *gin.Context) { c.IndentedJSON(http.StatusCreated, gin.H{}) })
+
+	// An origin only matched by "*" gets a literal "*" and no credentials
+	w := preflight(router, "https://evil.example.com")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+
+	// Listed origins keep their credentials
+	w = preflight(router, "https://widget.example.com")
+	assert.Equal(t, "https://widget.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+}
\ No newline at end of file
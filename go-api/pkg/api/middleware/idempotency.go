@@ -0,0 +1,188 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+	"github.com/gin-gonic/gin"
+)
+
+// IdempotencyHeader is the request header carrying the client-chosen key.
+const IdempotencyHeader = "Idempotency-Key"
+
+const maxIdempotencyKeyLength = 255
+
+type IdempotencyRecord struct {
+	Fingerprint string
+	Completed   bool
+	Status      int
+	ContentType string
+	Body        []byte
+	ExpiresAt   time.Time
+}
+
+// IdempotencyStore keeps the outcome of requests sent with an Idempotency-Key.
+// Implementations shared between instances must make Reserve atomic.
+type IdempotencyStore interface {
+	// Reserve stores record under key unless a live record already exists,
+	// in which case the existing record is returned with false.
+	Reserve(key string, record IdempotencyRecord) (IdempotencyRecord, bool)
+	// Complete replaces the reserved record with the final response.
+	Complete(key string, record IdempotencyRecord)
+	// Release forgets key so that the request can be retried.
+	Release(key string)
+}
+
+type MemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]IdempotencyRecord
+}
+
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{records: map[string]IdempotencyRecord{}}
+}
+
+func (store *MemoryIdempotencyStore) Reserve(key string, record IdempotencyRecord) (IdempotencyRecord, bool) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	now := time.Now()
+	if existing, ok := store.records[key]; ok && now.Before(existing.ExpiresAt) {
+		return existing, false
+	}
+
+	// Expired records are swept here so that the map does not grow forever.
+	for other, existing := range store.records {
+		if !now.Before(existing.ExpiresAt) {
+			delete(store.records, other)
+		}
+	}
+	store.records[key] = record
+	return record, true
+}
+
+func (store *MemoryIdempotencyStore) Complete(key string, record IdempotencyRecord) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.records[key] = record
+}
+
+func (store *MemoryIdempotencyStore) Release(key string) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	delete(store.records, key)
+}
+
+/**
+ * @brief Idempotency replays the stored response of requests repeated with the same Idempotency-Key.
+ *
+ * The first request runs normally and its status and body are kept for ttl.
+ * Replays get the stored response back; reusing a key with a different body
+ * returns 422 and reusing it while the first request runs returns 409.
+ * Keys are scoped to the caller, so that two clients sending the same key do
+ * not see each other's responses. Server errors and panics are not stored so
+ * that the client can retry them.
+ *
+ * @param store IdempotencyStore: Where responses are kept.
+ * @param ttl time.Duration: How long a key stays usable for replays.
+ */
+func Idempotency(store IdempotencyStore, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid Idempotency-Key"})
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		sum := sha256.Sum256(append([]byte(c.Request.Method+" "+c.Request.URL.RequestURI()+"\n"), body...))
+		fingerprint := hex.EncodeToString(sum[:])
+		scopedKey := idempotencyScope(c) + " " + c.Request.Method + " " + c.FullPath() + " " + key
+
+		existing, reserved := store.Reserve(scopedKey, IdempotencyRecord{
+			Fingerprint: fingerprint,
+			ExpiresAt:   time.Now().Add(ttl),
+		})
+		if !reserved {
+			switch {
+			case existing.Fingerprint != fingerprint:
+				c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency-Key was already used with a different request"})
+			case !existing.Completed:
+				c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "A request with this Idempotency-Key is still in progress"})
+			default:
+				c.Header("Idempotent-Replayed", "true")
+				c.Data(existing.Status, existing.ContentType, existing.Body)
+				c.Abort()
+			}
+			return
+		}
+
+		completed := false
+		defer func() {
+			if !completed {
+				store.Release(scopedKey)
+			}
+		}()
+
+		recorder := &bodyRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		if c.Writer.Status() >= http.StatusInternalServerError {
+			return
+		}
+
+		completed = true
+		store.Complete(scopedKey, IdempotencyRecord{
+			Fingerprint: fingerprint,
+			Completed:   true,
+			Status:      c.Writer.Status(),
+			ContentType: c.Writer.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+			ExpiresAt:   time.Now().Add(ttl),
+		})
+	}
+}
+
+/**
+ * @brief idempotencyScope names the caller owning an Idempotency-Key.
+ *
+ * @return string: The principal name, or the client IP for anonymous requests.
+ */
+func idempotencyScope(c *gin.Context) string {
+	if principal, ok := CurrentPrincipal(c); ok {
+		return "key:" + principal.Name
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// bodyRecorder copies everything written to the response.
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (recorder *bodyRecorder) Write(data []byte) (int, error) {
+	recorder.body.Write(data)
+	return recorder.ResponseWriter.Write(data)
+}
+
+func (recorder *bodyRecorder) WriteString(data string) (int, error) {
+	recorder.body.WriteString(data)
+	return recorder.ResponseWriter.WriteString(data)
+}
\ No newline at end of file
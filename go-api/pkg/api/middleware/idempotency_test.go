@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// newIdempotentRouter returns a router whose POST /items handler counts its calls.
+func newIdempotentRouter(calls *int, status int) *gin.Engine {
+	router := gin.Default()
+	router.POST("/items", Idempotency(NewMemoryIdempotencyStore(), time.Hour), func(c *gin.Context) {
+		*calls++
+		c.IndentedJSON(status, gin.H{"call": *calls})
+	})
+	return router
+}
+
+func postItem(router *gin.Engine, key string, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodPost, "/items", strings.NewReader(body))
+	req.Header.Add("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Add(IdempotencyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotencyReplaysStoredResponse(t *testing.T) {
+	calls := 0
+	router := newIdempotentRouter(&calls, http.StatusCreated)
+
+	first := postItem(router, "key-1", `{"name":"Diego"}`)
+	second := postItem(router, "key-1", `{"name":"Diego"}`)
+
+	// The handler runs once and the replay gets the same status and body
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, "true", second.Header().Get("Idempotent-Replayed"))
+}
+
+func TestIdempotencyRejectsDifferentBody(t *testing.T) {
+	calls := 0
+	router := newIdempotentRouter(&calls, http.StatusCreated)
+
+	postItem(router, "key-1", `{"name":"Diego"}`)
+	w := postItem(router, "key-1", `{"name":"Martin"}`)
+
+	// Assert that the HTTP status code is Unprocessable Entity (422)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotencyWithoutKey(t *testing.T) {
+	calls := 0
+	router := newIdempotentRouter(&calls, http.StatusCreated)
+
+	postItem(router, "", `{"name":"Diego"}`)
+	postItem(router, "", `{"name":"Diego"}`)
+
+	// Requests without a key are never deduplicated
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotencyDoesNotStoreServerErrors(t *testing.T) {
+	calls := 0
+	router := newIdempotentRouter(&calls, http.StatusInternalServerError)
+
+	postItem(router, "key-1", `{"name":"Diego"}`)
+	postItem(router, "key-1", `{"name":"Diego"}`)
+
+	// A failed request can be retried with the same key
+	assert.Equal(t, 2, calls)
+}
+func TestIdempotencyIsScopedToTheCaller(t *testing.T) {
+	calls := 0
+	router := gin.Default()
+	router.POST("/items", Authenticate(map[string]Principal{
+		"key-diego":  {Name: "diego", Role: "member"},
+		"key-martin": {Name: "martin", Role: "member"},
+	}), Idempotency(NewMemoryIdempotencyStore(), time.Hour), func(c *gin.Context) {
+		calls++
+		principal, _ := CurrentPrincipal(c)
+		c.IndentedJSON(http.StatusCreated, gin.H{"call": calls, "name": principal.Name})
+	})
+
+	post := func(apiKey string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodPost, "/items", strings.NewReader(`{}`))
+		req.Header.Add(APIKeyHeader, apiKey)
+		req.Header.Add(IdempotencyHeader, "key-1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	post("key-diego")
+	w := post("key-martin")
+
+	// Another caller reusing the key runs the handler and does not see the first response
+	assert.Equal(t, 2, calls)
+	assert.Empty(t, w.Header().Get("Idempotent-Replayed"))
+	assert.Contains(t, w.Body.String(), "martin")
+}
+
+func TestIdempotencyReleasesKeyOnPanic(t *testing.T) {
+	calls := 0
+	router := gin.Default()
+	router.POST("/items", Idempotency(NewMemoryIdempotencyStore(), time.Hour), func(c *gin.Context) {
+		calls++
+		if calls == 1 {
+			panic("boom")
+		}
+		c.IndentedJSON(http.StatusCreated, gin.H{"call": calls})
+	})
+
+	first := postItem(router, "key-1", `{"name":"Diego"}`)
+	second := postItem(router, "key-1", `{"name":"Diego"}`)
+
+	// The key is not left in progress after the handler panicked
+	assert.Equal(t, http.StatusInternalServerError, first.Code)
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, 2, calls)
+}
+
+func TestMemoryIdempotencyStoreSweepsExpiredRecords(t *testing.T) {
+	store := NewMemoryIdempotencyStore()
+	store.Reserve("old", IdempotencyRecord{ExpiresAt: time.Now().Add(-time.Minute)})
+	store.Reserve("new", IdempotencyRecord{ExpiresAt: time.Now().Add(time.Hour)})
+
+	// Reserving a key drops the records that already expired
+	assert.Len(t, store.records, 1)
+}
\ No newline at end of file
@@ -0,0 +1,209 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit allows Requests per Period, refilled continuously (token bucket).
+// A zero Requests disables the limit.
+type RateLimit struct {
+	Requests int           `json:"requests"`
+	Period   time.Duration `json:"period"`
+}
+
+// UnmarshalJSON accepts the period as a Go duration string such as "1m".
+// The period may be left out of a disabled limit ("requests": 0).
+func (limit *RateLimit) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Requests int    `json:"requests"`
+		Period   string `json:"period"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	limit.Requests = raw.Requests
+	limit.Period = 0
+	if raw.Period == "" && raw.Requests <= 0 {
+		return nil
+	}
+
+	period, err := time.ParseDuration(raw.Period)
+	if err != nil {
+		return err
+	}
+	limit.Period = period
+	return nil
+}
+
+// RateLimitPolicy holds the limits of one route group, per kind of client.
+type RateLimitPolicy struct {
+	APIKey RateLimit `json:"api_key"`
+	Member RateLimit `json:"member"`
+	IP     RateLimit `json:"ip"`
+}
+
+type RateLimitResult struct {
+	Allowed    bool
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// Limiter counts requests per key. Implementations backed by a shared store
+// let several instances enforce the same limits.
+type Limiter interface {
+	Allow(key string, limit RateLimit) RateLimitResult
+}
+
+// limiterSweepInterval is how often MemoryLimiter drops the buckets that refilled.
+const limiterSweepInterval = time.Minute
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	// full is when the bucket is back to its capacity; it can then be
+	// dropped, since a new bucket starts full.
+	full time.Time
+}
+
+type MemoryLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{buckets: map[string]*bucket{}, now: time.Now}
+}
+
+// sweep drops the buckets that refilled, at most once per limiterSweepInterval.
+func (limiter *MemoryLimiter) sweep(now time.Time) {
+	if now.Sub(limiter.lastSweep) < limiterSweepInterval {
+		return
+	}
+	limiter.lastSweep = now
+	for key, b := range limiter.buckets {
+		if !now.Before(b.full) {
+			delete(limiter.buckets, key)
+		}
+	}
+}
+
+func (limiter *MemoryLimiter) Allow(key string, limit RateLimit) RateLimitResult {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	now := limiter.now()
+	limiter.sweep(now)
+	capacity := float64(limit.Requests)
+	perSecond := capacity / limit.Period.Seconds()
+
+	b, ok := limiter.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, last: now}
+		limiter.buckets[key] = b
+	}
+
+	b.tokens = math.Min(capacity, b.tokens+now.Sub(b.last).Seconds()*perSecond)
+	b.last = now
+
+	result := RateLimitResult{Allowed: b.tokens >= 1}
+	if result.Allowed {
+		b.tokens--
+	} else {
+		result.RetryAfter = time.Duration((1 - b.tokens) / perSecond * float64(time.Second))
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = time.Duration((capacity - b.tokens) / perSecond * float64(time.Second))
+	b.full = now.Add(result.Reset)
+	return result
+}
+
+/**
+ * @brief RateLimiter applies the rate limit policy of the route group of each request.
+ *
+ * The group is the first path segment after /api (e.g. "bookings"); groups
+ * without a policy use the "default" one. Members are limited by member ID,
+ * other API key holders by key and anonymous clients by IP. Every response
+ * carries RateLimit-* headers; rejected requests get a 429 problem response
+ * with Retry-After.
+ *
+ * @param limiter Limiter: Where request counts are kept.
+ * @param policies map[string]RateLimitPolicy: The policies by route group.
+ */
+func RateLimiter(limiter Limiter, policies map[string]RateLimitPolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		group := routeGroup(c.FullPath())
+		policy, ok := policies[group]
+		if !ok {
+			group = "default"
+			policy = policies[group]
+		}
+
+		var client string
+		var limit RateLimit
+		principal, authenticated := CurrentPrincipal(c)
+		switch {
+		case authenticated && principal.MemberId != 0:
+			client, limit = fmt.Sprintf("member:%d", principal.MemberId), policy.Member
+		case authenticated:
+			client, limit = "key:"+principal.Name, policy.APIKey
+		default:
+			client, limit = "ip:"+c.ClientIP(), policy.IP
+		}
+
+		if limit.Requests <= 0 || limit.Period <= 0 {
+			c.Next()
+			return
+		}
+
+		result := limiter.Allow(group+"|"+client, limit)
+		c.Header("RateLimit-Limit", strconv.Itoa(limit.Requests))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(seconds(result.Reset)))
+
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(seconds(result.RetryAfter)))
+			Problem(c, http.StatusTooManyRequests, fmt.Sprintf("Rate limit of %d requests per %s exceeded", limit.Requests, limit.Period))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+/**
+ * @brief Problem aborts the request with an RFC 9457 problem details response.
+ *
+ * @param c *gin.Context: The Gin HTTP context.
+ * @param status int: The HTTP status code.
+ * @param detail string: A human readable explanation.
+ */
+func Problem(c *gin.Context, status int, detail string) {
+	c.Header("Content-Type", "application/problem+json")
+	c.AbortWithStatusJSON(status, gin.H{
+		"type":   "about:blank",
+		"title":  http.StatusText(status),
+		"status": status,
+		"detail": detail,
+	})
+}
+
+func routeGroup(fullPath string) string {
+	segments := strings.Split(strings.TrimPrefix(fullPath, "/api/"), "/")
+	return segments[0]
+}
+
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
\ No newline at end of file
@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// newRateLimitedRouter returns a router with two route groups allowing two requests per minute.
+func newRateLimitedRouter() *gin.Engine {
+	twoPerMinute := RateLimit{Requests: 2, Period: time.Minute}
+	policies := map[string]RateLimitPolicy{
+		"default":  {APIKey: RateLimit{Requests: 100, Period: time.Minute}, IP: twoPerMinute},
+		"bookings": {IP: twoPerMinute},
+	}
+	keys := map[string]Principal{"secret": {Name: "frontdesk", Role: "staff"}}
+
+	router := gin.Default()
+	api := router.Group("/api", Authenticate(keys), RateLimiter(NewMemoryLimiter(), policies))
+	api.GET("/classes", func(c *gin.Context) { c.IndentedJSON(http.StatusOK, gin.H{}) })
+	api.GET("/bookings", func(c *gin.Context) { c.IndentedJSON(http.StatusOK, gin.H{}) })
+	return router
+}
+
+func get(router *gin.Engine, path string, key string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodGet, path, nil)
+	if key != "" {
+		req.Header.Add(APIKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimiterRejectsExcessRequests(t *testing.T) {
+	router := newRateLimitedRouter()
+
+	first := get(router, "/api/classes", "")
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, "2", first.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", first.Header().Get("RateLimit-Remaining"))
+
+	get(router, "/api/classes", "")
+	w := get(router, "/api/classes", "")
+
+	// Assert that the HTTP status code is Too Many Requests (429) with a problem body
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "30", w.Header().Get("Retry-After"))
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+
+	var problem map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &problem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, float64(http.StatusTooManyRequests), problem["status"])
+	assert.Equal(t, "Too Many Requests", problem["title"])
+}
+
+func TestRateLimiterSeparatesGroupsAndClients(t *testing.T) {
+	router := newRateLimitedRouter()
+
+	get(router, "/api/classes", "")
+	get(router, "/api/classes", "")
+
+	// The bookings group has its own bucket
+	assert.Equal(t, http.StatusOK, get(router, "/api/bookings", "").Code)
+
+	// API key holders are limited by key, not by IP
+	w := get(router, "/api/classes", "secret")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "100", w.Header().Get("RateLimit-Limit"))
+}
+
+func TestMemoryLimiterRefills(t *testing.T) {
+	now := time.Date(2023, 10, 6, 16, 0, 0, 0, time.UTC)
+	limiter := NewMemoryLimiter()
+	limiter.now = func() time.Time { return now }
+	limit := RateLimit{Requests: 1, Period: time.Minute}
+
+	assert.True(t, limiter.Allow("ip:1", limit).Allowed)
+	assert.False(t, limiter.Allow("ip:1", limit).Allowed)
+
+	// After a full period the bucket holds a token again
+	now = now.Add(time.Minute)
+	assert.True(t, limiter.Allow("ip:1", limit).Allowed)
+}
+func TestMemoryLimiterDropsRefilledBuckets(t *testing.T) {
+	now := time.Date(2023, 10, 6, 16, 0, 0, 0, time.UTC)
+	limiter := NewMemoryLimiter()
+	limiter.now = func() time.Time { return now }
+	limit := RateLimit{Requests: 10, Period: time.Minute}
+
+	limiter.Allow("ip:1", limit)
+	limiter.Allow("ip:2", limit)
+	assert.Len(t, limiter.buckets, 2)
+
+	// Buckets that refilled are forgotten instead of piling up per client
+	now = now.Add(2 * time.Minute)
+	limiter.Allow("ip:3", limit)
+	assert.Len(t, limiter.buckets, 1)
+}
+
+func TestRateLimitUnmarshalJSON(t *testing.T) {
+	var limit RateLimit
+	assert.NoError(t, json.Unmarshal([]byte(`{"requests": 5, "period": "1m"}`), &limit))
+	assert.Equal(t, RateLimit{Requests: 5, Period: time.Minute}, limit)
+
+	// A disabled limit does not need a period, an enabled one does
+	assert.NoError(t, json.Unmarshal([]byte(`{"requests": 0}`), &limit))
+	assert.Equal(t, RateLimit{}, limit)
+	assert.Error(t, json.Unmarshal([]byte(`{"requests": 5}`), &limit))
+}
\ No newline at end of file
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the ID of a request, set by the client or generated.
+const RequestIDHeader = "X-Request-ID"
+
+const requestIDKey = "request_id"
+
+// validRequestID accepts client IDs that are safe to log and echo back.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+/**
+ * @brief RequestID identifies each request, for logs and the audit log.
+ *
+ * The client's X-Request-ID is kept when it is well formed; otherwise a random
+ * ID is generated. It is echoed in the X-Request-ID response header.
+ */
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = newRequestID()
+		}
+
+		c.Set(requestIDKey, id)
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+/**
+ * @brief CurrentRequestID returns the ID given to the request by RequestID, "" when there is none.
+ */
+func CurrentRequestID(c *gin.Context) string {
+	return c.GetString(requestIDKey)
+}
+
+func newRequestID() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(id)
+}
\ No newline at end of file
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	router := gin.Default()
+	router.Use(RequestID())
+	router.GET("/api/classes", func(c *gin.Context) { c.String(http.StatusOK, CurrentRequestID(c)) })
+
+	serve := func(id string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodGet, "/api/classes", nil)
+		if id != "" {
+			req.Header.Add(RequestIDHeader, id)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// The client's ID is kept
+	w := serve("req-42")
+	assert.Equal(t, "req-42", w.Header().Get(RequestIDHeader))
+	assert.Equal(t, "req-42", w.Body.String())
+
+	// Missing or malformed IDs are replaced
+	w = serve("")
+	assert.Len(t, w.Header().Get(RequestIDHeader), 32)
+	assert.Equal(t, w.Header().Get(RequestIDHeader), w.Body.String())
+	w = serve("bad id\n")
+	assert.NotEqual(t, "bad id\n", w.Header().Get(RequestIDHeader))
+}
\ No newline at end of file
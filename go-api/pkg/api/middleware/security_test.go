@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveWithSecurityHeaders(config SecurityHeadersConfig, forwardedProto string) *httptest.ResponseRecorder {
+	router := gin.Default()
+	router.Use(SecurityHeaders(config))
+	router.GET("/api/classes", func(c *gin.Context) { c.IndentedJSON(http.StatusOK, gin.H{}) })
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/classes", nil)
+	if forwardedProto != "" {
+		req.Header.Add("X-Forwarded-Proto", forwardedProto)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	w := serveWithSecurityHeaders(DefaultSecurityHeadersConfig(), "https")
+
+	assert.Equal(t, "max-age=31536000; includeSubDomains", w.Header().Get("Strict-Transport-Security"))
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "DENY", w.Header().Get("X-Frame-Options"))
+	assert.Equal(t, "no-referrer", w.Header().Get("Referrer-Policy"))
+
+	// HSTS is never sent over plain HTTP
+	w = serveWithSecurityHeaders(DefaultSecurityHeadersConfig(), "")
+	assert.Empty(t, w.Header().Get("Strict-Transport-Security"))
+}
+
+func TestSecurityHeadersDisabled(t *testing.T) {
+	config := DefaultSecurityHeadersConfig()
+	config.Enabled = false
+
+	w := serveWithSecurityHeaders(config, "https")
+
+	assert.Empty(t, w.Header().Get("Strict-Transport-Security"))
+	assert.Empty(t, w.Header().Get("X-Frame-Options"))
+}
\ No newline at end of file
@@ -0,0 +1,29 @@
+package openapi
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed docs.html
+var docsPage []byte
+
+/**
+ * @brief GetSpec returns the OpenAPI document.
+ *
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func GetSpec(c *gin.Context) {
+	c.IndentedJSON(http.StatusOK, Spec())
+}
+
+/**
+ * @brief GetDocs returns the embedded API documentation page, which renders /openapi.json without external assets.
+ *
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func GetDocs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", docsPage)
+}
\ No newline at end of file
@@ -0,0 +1,382 @@
+package openapi
+
+import (
+	"encoding/json"
+	"go-api/pkg/models"
+	"net/http"
+)
+
+// BasePath is the prefix of every documented route.
+const BasePath = "/api"
+
+// Operation documents one route registered in api.InitRouter.
+type Operation struct {
+	Method string
+	// Path is the Gin route relative to BasePath, e.g. "/classes/:id".
+	Path    string
+	ID      string
+	Summary string
+	// Description adds details to the summary, such as when the operation is refused.
+	Description string
+	Tag         string
+	// Role is the role required to call the operation, "" when anonymous calls are allowed.
+	Role       string
+	Parameters []Parameter
+	// Request is a value of the JSON request body type, nil when there is no body.
+	Request interface{}
+	// RequestTypes lists the accepted media types, application/json when empty.
+	// Other media types are documented as plain strings.
+	RequestTypes []string
+	// Responses maps status codes to a value of the JSON body type, a File or nil for empty bodies.
+	Responses map[int]interface{}
+}
+
+// Parameter documents a query, header or path parameter. Path parameters not listed are integer IDs.
+type Parameter struct {
+	Name        string
+	In          string
+	Description string
+	Required    bool
+}
+
+type Error struct {
+	Error string `json:"error" validate:"required"`
+}
+
+type Message struct {
+	Message string `json:"message" validate:"required"`
+}
+
+// File is a downloaded file body in one of MediaTypes.
+type File struct {
+	MediaTypes []string
+	// JSON is a value of the body type served as application/json instead of a file, nil when there is none.
+	JSON interface{}
+}
+
+type Problem struct {
+	Type   string `json:"type" validate:"required"`
+	Title  string `json:"title" validate:"required"`
+	Status int    `json:"status" validate:"required"`
+	Detail string `json:"detail"`
+}
+
+var (
+	tzParameter          = Parameter{Name: "tz", In: "query", Description: `Render dates in "studio" (the studio zone) or an IANA zone`}
+	ifNoneMatchParameter = Parameter{Name: "If-None-Match", In: "header", Description: "Return 304 when the ETag still matches"}
+	ifMatchParameter     = Parameter{Name: "If-Match", In: "header", Description: "Fail with 412 unless the ETag matches the current version"}
+	idempotencyParameter = Parameter{Name: "Idempotency-Key", In: "header", Description: "Replay the stored response of a previous request with the same key"}
+
+	classIdParameter  = Parameter{Name: "class_id", In: "query", Description: "Only bookings of this class"}
+	studioIdParameter = Parameter{Name: "studio_id", In: "query", Description: "Only items of this studio"}
+	nameParameter     = Parameter{Name: "name", In: "query", Description: "Only items with this name, case-insensitive"}
+	statusParameter   = Parameter{Name: "status", In: "query", Description: "Only bookings with these comma-separated statuses"}
+	fromParameter     = Parameter{Name: "from", In: "query", Description: "Only items dated at or after this RFC 3339 instant"}
+	toParameter       = Parameter{Name: "to", In: "query", Description: "Only items dated before this RFC 3339 instant"}
+	formatParameter   = Parameter{Name: "format", In: "query", Description: "csv (default) or xlsx"}
+
+	includeDeletedParameter = Parameter{Name: "include_deleted", In: "query", Description: "true to include soft-deleted items (admin)"}
+
+	kindParameter = Parameter{Name: "kind", In: "path", Description: "confirmation, change, cancellation, promotion or reminder", Required: true}
+)
+
+// calendarFile is the body of the iCalendar feeds.
+var calendarFile = File{MediaTypes: []string{"text/calendar"}}
+
+// checkInCodeFile is the check-in token, or its QR code.
+var checkInCodeFile = File{MediaTypes: []string{"image/png", "image/svg+xml"}, JSON: models.CheckInCode{}}
+
+// exportFile is the body of the CSV and XLSX downloads.
+var exportFile = File{MediaTypes: []string{"text/csv", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"}}
+
+// bookingTransitionResponses are the responses of the operations changing the status of a booking.
+var bookingTransitionResponses = map[int]interface{}{
+	http.StatusOK:                 models.Booking{},
+	http.StatusBadRequest:         Error{},
+	http.StatusNotFound:           Error{},
+	http.StatusConflict:           Error{},
+	http.StatusPreconditionFailed: Error{},
+	http.StatusBadGateway:         Error{},
+}
+
+// commonResponses are the responses any operation can return from the middleware.
+var commonResponses = map[int]interface{}{
+	http.StatusUnauthorized:    Error{},
+	http.StatusTooManyRequests: Problem{},
+}
+
+// Operations lists every route under BasePath. The router test fails when it drifts from api.InitRouter.
+var Operations = []Operation{
+	{
+		Method: http.MethodGet, Path: "/classes", ID: "getClasses", Summary: "Get all classes", Tag: "classes",
+		Parameters: []Parameter{studioIdParameter, nameParameter, fromParameter, toParameter, includeDeletedParameter, tzParameter, ifNoneMatchParameter},
+		Responses:  map[int]interface{}{http.StatusOK: []models.Class{}, http.StatusNotModified: nil, http.StatusBadRequest: Error{}, http.StatusForbidden: Error{}},
+	},
+	{
+		Method: http.MethodGet, Path: "/classes/:id", ID: "getClassByID", Summary: "Get a class by ID", Tag: "classes",
+		Parameters: []Parameter{includeDeletedParameter, tzParameter, ifNoneMatchParameter},
+		Responses:  map[int]interface{}{http.StatusOK: models.Class{}, http.StatusNotModified: nil, http.StatusBadRequest: Error{}, http.StatusForbidden: Error{}, http.StatusNotFound: Error{}},
+	},
+	{
+		Method: http.MethodGet, Path: "/classes/:id/roster", ID: "getClassRoster", Summary: "Download the bookings of a class as CSV or XLSX", Tag: "classes",
+		Parameters: []Parameter{nameParameter, statusParameter, fromParameter, toParameter, tzParameter, formatParameter},
+		Responses:  map[int]interface{}{http.StatusOK: exportFile, http.StatusBadRequest: Error{}, http.StatusNotFound: Error{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/classes/:id/check-in", ID: "checkInClass", Summary: "Mark a member or booking of a class as attended", Tag: "classes",
+		Request:   models.CheckIn{},
+		Responses: map[int]interface{}{http.StatusOK: models.Booking{}, http.StatusBadRequest: Error{}, http.StatusNotFound: Error{}, http.StatusConflict: Error{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/classes/:id/roster/check-in", ID: "bulkCheckInClass", Summary: "Check in several members of a class roster", Tag: "classes",
+		Request:   models.BulkCheckIn{},
+		Responses: map[int]interface{}{http.StatusOK: models.CheckInReport{}, http.StatusBadRequest: Error{}, http.StatusNotFound: Error{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/classes", ID: "postClasses", Summary: "Create a new class", Tag: "classes",
+		Parameters: []Parameter{idempotencyParameter},
+		Request:    models.CreateClass{},
+		Responses:  map[int]interface{}{http.StatusCreated: models.Class{}, http.StatusBadRequest: Error{}, http.StatusNotFound: Error{}, http.StatusConflict: Error{}, http.StatusUnprocessableEntity: Error{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/classes/recurring", ID: "postRecurringClasses", Summary: "Create a daily or weekly series of classes", Tag: "classes",
+		Request:   models.CreateRecurringClass{},
+		Responses: map[int]interface{}{http.StatusCreated: []models.Class{}, http.StatusBadRequest: Error{}, http.StatusNotFound: Error{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/classes/import", ID: "importClasses", Summary: "Create many classes from a JSON array, a CSV file or an iCalendar file", Tag: "classes",
+		Parameters: []Parameter{
+			{Name: "mode", In: "query", Description: "atomic (default): nothing is written unless every row is valid; best_effort: valid rows are created"},
+			{Name: "dry_run", In: "query", Description: "true to only validate the rows"},
+			{Name: "capacity", In: "query", Description: "Capacity of the classes imported from iCalendar, required for text/calendar"},
+			{Name: "studio_id", In: "query", Description: "Studio of the classes imported from iCalendar; floating times are read in its zone"},
+			idempotencyParameter,
+		},
+		Request:      []models.CreateClass{},
+		RequestTypes: []string{"application/json", "text/csv", "text/calendar"},
+		Responses:    map[int]interface{}{http.StatusOK: models.ClassImportReport{}, http.StatusCreated: models.ClassImportReport{}, http.StatusBadRequest: Error{}, http.StatusConflict: Error{}, http.StatusUnprocessableEntity: models.ClassImportReport{}},
+	},
+	{
+		Method: http.MethodPut, Path: "/classes/:id", ID: "updateClass", Summary: "Update a class by ID", Tag: "classes",
+		Parameters: []Parameter{ifMatchParameter},
+		Request:    models.UpdateClass{},
+		Responses:  map[int]interface{}{http.StatusOK: models.Class{}, http.StatusBadRequest: Error{}, http.StatusNotFound: Error{}, http.StatusPreconditionFailed: Error{}},
+	},
+	{
+		Method: http.MethodDelete, Path: "/classes/:id", ID: "deleteClass", Summary: "Soft-delete a class and its bookings by ID", Tag: "classes",
+		Parameters: []Parameter{ifMatchParameter},
+		Responses:  map[int]interface{}{http.StatusOK: Message{}, http.StatusBadRequest: Error{}, http.StatusNotFound: Error{}, http.StatusConflict: Error{}, http.StatusPreconditionFailed: Error{}, http.StatusBadGateway: Error{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/classes/:id/restore", ID: "restoreClass", Summary: "Restore a soft-deleted class and the bookings deleted with it", Tag: "classes",
+		Role:       "admin",
+		Parameters: []Parameter{ifMatchParameter},
+		Responses:  map[int]interface{}{http.StatusOK: models.Class{}, http.StatusBadRequest: Error{}, http.StatusForbidden: Error{}, http.StatusNotFound: Error{}, http.StatusConflict: Error{}, http.StatusPreconditionFailed: Error{}},
+	},
+	{
+		Method: http.MethodGet, Path: "/bookings", ID: "getBookings", Summary: "Get all bookings", Tag: "bookings",
+		Parameters: []Parameter{classIdParameter, studioIdParameter, nameParameter, statusParameter, fromParameter, toParameter, includeDeletedParameter, tzParameter, ifNoneMatchParameter},
+		Responses:  map[int]interface{}{http.StatusOK: []models.Booking{}, http.StatusNotModified: nil, http.StatusBadRequest: Error{}, http.StatusForbidden: Error{}},
+	},
+	{
+		Method: http.MethodGet, Path: "/bookings/export", ID: "exportBookings", Summary: "Download bookings as CSV or XLSX", Tag: "bookings",
+		Parameters: []Parameter{classIdParameter, studioIdParameter, nameParameter, statusParameter, fromParameter, toParameter, tzParameter, formatParameter},
+		Responses:  map[int]interface{}{http.StatusOK: exportFile, http.StatusBadRequest: Error{}},
+	},
+	{
+		Method: http.MethodGet, Path: "/bookings/:id", ID: "getBookingByID", Summary: "Get a booking by ID", Tag: "bookings",
+		Parameters: []Parameter{includeDeletedParameter, tzParameter, ifNoneMatchParameter},
+		Responses:  map[int]interface{}{http.StatusOK: models.Booking{}, http.StatusNotModified: nil, http.StatusBadRequest: Error{}, http.StatusForbidden: Error{}, http.StatusNotFound: Error{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/bookings", ID: "postBookings", Summary: "Create a new booking", Tag: "bookings",
+		Parameters: []Parameter{idempotencyParameter},
+		Request:    models.CreateBooking{},
+		Responses:  map[int]interface{}{http.StatusCreated: models.Booking{}, http.StatusBadRequest: Error{}, http.StatusPaymentRequired: Error{}, http.StatusNotFound: Error{}, http.StatusConflict: Error{}, http.StatusUnprocessableEntity: Error{}, http.StatusBadGateway: Error{}},
+	},
+	{
+		Method: http.MethodPut, Path: "/bookings/:id", ID: "updateBooking", Summary: "Update a booking by ID", Tag: "bookings",
+		Parameters: []Parameter{ifMatchParameter},
+		Request:    models.UpdateBooking{},
+		Responses:  map[int]interface{}{http.StatusOK: models.Booking{}, http.StatusBadRequest: Error{}, http.StatusPaymentRequired: Error{}, http.StatusNotFound: Error{}, http.StatusConflict: Error{}, http.StatusPreconditionFailed: Error{}},
+	},
+	{
+		Method: http.MethodDelete, Path: "/bookings/:id", ID: "deleteBooking", Summary: "Cancel a booking by ID under the cancellation policy", Tag: "bookings",
+		Parameters: []Parameter{ifMatchParameter},
+		Responses:  bookingTransitionResponses,
+	},
+	{
+		Method: http.MethodPost, Path: "/bookings/:id/confirm", ID: "confirmBooking", Summary: "Confirm a pending or waitlisted booking", Tag: "bookings",
+		Parameters: []Parameter{ifMatchParameter},
+		Responses:  bookingTransitionResponses,
+	},
+	{
+		Method: http.MethodPost, Path: "/bookings/:id/cancel", ID: "cancelBooking", Summary: "Cancel a booking under the cancellation policy", Tag: "bookings",
+		Parameters: []Parameter{ifMatchParameter},
+		Responses:  bookingTransitionResponses,
+	},
+	{
+		Method: http.MethodPost, Path: "/bookings/:id/attend", ID: "attendBooking", Summary: "Mark a confirmed booking as attended", Tag: "bookings",
+		Parameters: []Parameter{ifMatchParameter},
+		Responses:  bookingTransitionResponses,
+	},
+	{
+		Method: http.MethodPost, Path: "/bookings/:id/no-show", ID: "markNoShow", Summary: "Mark a confirmed booking as a no-show", Tag: "bookings",
+		Parameters: []Parameter{ifMatchParameter},
+		Responses:  bookingTransitionResponses,
+	},
+	{
+		Method: http.MethodPost, Path: "/bookings/:id/restore", ID: "restoreBooking", Summary: "Restore a booking soft-deleted with its class", Tag: "bookings",
+		Role:       "admin",
+		Parameters: []Parameter{ifMatchParameter},
+		Responses:  map[int]interface{}{http.StatusOK: models.Booking{}, http.StatusBadRequest: Error{}, http.StatusPaymentRequired: Error{}, http.StatusForbidden: Error{}, http.StatusNotFound: Error{}, http.StatusConflict: Error{}, http.StatusPreconditionFailed: Error{}},
+	},
+	{
+		Method: http.MethodGet, Path: "/bookings/:id/checkin-code", ID: "getCheckInCode", Summary: "Issue a short-lived check-in code for a confirmed booking", Tag: "bookings",
+		Role:       "member",
+		Parameters: []Parameter{{Name: "format", In: "query", Description: "json (default), png or svg for a QR code"}},
+		Responses:  map[int]interface{}{http.StatusOK: checkInCodeFile, http.StatusBadRequest: Error{}, http.StatusForbidden: Error{}, http.StatusNotFound: Error{}, http.StatusConflict: Error{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/kiosk/check-in", ID: "kioskCheckIn", Summary: "Check in the booking of a scanned check-in code", Tag: "bookings",
+		Role:      "kiosk",
+		Request:   models.KioskCheckIn{},
+		Responses: map[int]interface{}{http.StatusOK: models.Booking{}, http.StatusBadRequest: Error{}, http.StatusForbidden: Error{}, http.StatusNotFound: Error{}, http.StatusConflict: Error{}, http.StatusGone: Error{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/payments/webhook", ID: "paymentWebhook", Summary: "Receive the signed payment events of the payment provider", Tag: "bookings",
+		Request:   json.RawMessage{},
+		Responses: map[int]interface{}{http.StatusOK: models.Booking{}, http.StatusNoContent: nil, http.StatusBadRequest: Error{}, http.StatusBadGateway: Error{}},
+	},
+	{
+		Method: http.MethodGet, Path: "/studios", ID: "getStudios", Summary: "Get all studios", Tag: "studios",
+		Responses: map[int]interface{}{http.StatusOK: []models.Studio{}},
+	},
+	{
+		Method: http.MethodGet, Path: "/studios/:id", ID: "getStudioByID", Summary: "Get a studio by ID", Tag: "studios",
+		Responses: map[int]interface{}{http.StatusOK: models.Studio{}, http.StatusBadRequest: Error{}, http.StatusNotFound: Error{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/studios", ID: "postStudios", Summary: "Create a new studio", Tag: "studios",
+		Request:   models.CreateStudio{},
+		Responses: map[int]interface{}{http.StatusCreated: models.Studio{}, http.StatusBadRequest: Error{}},
+	},
+	{
+		Method: http.MethodGet, Path: "/studios/:id/schedule.ics", ID: "getStudioSchedule", Summary: "Get the classes of a studio as an iCalendar feed", Tag: "calendar",
+		Responses: map[int]interface{}{http.StatusOK: calendarFile, http.StatusBadRequest: Error{}, http.StatusNotFound: Error{}},
+	},
+	{
+		Method: http.MethodGet, Path: "/studios/:id/email-templates", ID: "getEmailTemplates", Summary: "Get the notification email templates of a studio", Tag: "studios",
+		Responses: map[int]interface{}{http.StatusOK: []models.EmailTemplate{}, http.StatusBadRequest: Error{}, http.StatusNotFound: Error{}},
+	},
+	{
+		Method: http.MethodPut, Path: "/studios/:id/email-templates/:kind", ID: "putEmailTemplate", Summary: "Override a notification email template for a studio", Tag: "studios",
+		Role:       "admin",
+		Parameters: []Parameter{kindParameter},
+		Request:    models.UpdateEmailTemplate{},
+		Responses:  map[int]interface{}{http.StatusOK: models.EmailTemplate{}, http.StatusBadRequest: Error{}, http.StatusForbidden: Error{}, http.StatusNotFound: Error{}},
+	},
+	{
+		Method: http.MethodDelete, Path: "/studios/:id/email-templates/:kind", ID: "deleteEmailTemplate", Summary: "Revert a notification email template of a studio to the default", Tag: "studios",
+		Role:       "admin",
+		Parameters: []Parameter{kindParameter},
+		Responses:  map[int]interface{}{http.StatusOK: models.EmailTemplate{}, http.StatusBadRequest: Error{}, http.StatusForbidden: Error{}, http.StatusNotFound: Error{}},
+	},
+	{
+		Method: http.MethodGet, Path: "/members", ID: "getMembers", Summary: "Get all members", Tag: "members",
+		Role:      "staff",
+		Responses: map[int]interface{}{http.StatusOK: []models.Member{}, http.StatusForbidden: Error{}},
+	},
+	{
+		Method: http.MethodGet, Path: "/members/:id", ID: "getMemberByID", Summary: "Get a member by ID", Tag: "members",
+		Role:      "staff",
+		Responses: map[int]interface{}{http.StatusOK: models.Member{}, http.StatusBadRequest: Error{}, http.StatusForbidden: Error{}, http.StatusNotFound: Error{}},
+	},
+	{
+		Method: http.MethodGet, Path: "/members/:id/attendance", ID: "getMemberAttendance", Summary: "Get the attendance history and no-show count of a member", Tag: "members",
+		Responses: map[int]interface{}{http.StatusOK: models.Attendance{}, http.StatusBadRequest: Error{}, http.StatusNotFound: Error{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/members", ID: "postMembers", Summary: "Create a new member", Tag: "members",
+		Role:      "staff",
+		Request:   models.CreateMember{},
+		Responses: map[int]interface{}{http.StatusCreated: models.Member{}, http.StatusBadRequest: Error{}, http.StatusForbidden: Error{}, http.StatusConflict: Error{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/members/:id/feed", ID: "postMemberFeed", Summary: "Issue a new private calendar feed URL for a member", Tag: "members",
+		Role:      "member",
+		Responses: map[int]interface{}{http.StatusCreated: models.MemberFeed{}, http.StatusBadRequest: Error{}, http.StatusForbidden: Error{}, http.StatusNotFound: Error{}},
+	},
+	{
+		Method: http.MethodGet, Path: "/members/:id/credits", ID: "getMemberCredits", Summary: "Get the class credit balance, active membership and credit ledger of a member", Tag: "members",
+		Role:      "member",
+		Responses: map[int]interface{}{http.StatusOK: models.Credits{}, http.StatusBadRequest: Error{}, http.StatusForbidden: Error{}, http.StatusNotFound: Error{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/members/:id/plans", ID: "postMemberPlan", Summary: "Give a member a class-credit pack or a membership", Tag: "members",
+		Role:      "staff",
+		Request:   models.PurchasePlan{},
+		Responses: map[int]interface{}{http.StatusCreated: models.Credits{}, http.StatusBadRequest: Error{}, http.StatusForbidden: Error{}, http.StatusNotFound: Error{}},
+	},
+	{
+		Method: http.MethodGet, Path: "/plans", ID: "getPlans", Summary: "Get the membership plans and class-credit packs", Tag: "plans",
+		Responses: map[int]interface{}{http.StatusOK: []models.Plan{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/plans", ID: "postPlans", Summary: "Create a new plan", Tag: "plans",
+		Role:      "admin",
+		Request:   models.CreatePlan{},
+		Responses: map[int]interface{}{http.StatusCreated: models.Plan{}, http.StatusBadRequest: Error{}, http.StatusForbidden: Error{}},
+	},
+	{
+		Method: http.MethodGet, Path: "/calendar/:token", ID: "getMemberFeed", Summary: "Get the bookings of a member as a private iCalendar feed", Tag: "calendar",
+		Parameters: []Parameter{{Name: "token", In: "path", Description: "The feed token, optionally followed by .ics", Required: true}},
+		Responses:  map[int]interface{}{http.StatusOK: calendarFile, http.StatusNotFound: Error{}},
+	},
+	{
+		Method: http.MethodGet, Path: "/webhooks", ID: "getWebhooks", Summary: "Get all webhooks, without their secrets", Tag: "webhooks",
+		Role:      "admin",
+		Responses: map[int]interface{}{http.StatusOK: []models.Webhook{}, http.StatusForbidden: Error{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/webhooks", ID: "postWebhooks", Summary: "Subscribe a URL to booking and class events", Tag: "webhooks",
+		Role:      "admin",
+		Request:   models.CreateWebhook{},
+		Responses: map[int]interface{}{http.StatusCreated: models.Webhook{}, http.StatusBadRequest: Error{}, http.StatusForbidden: Error{}},
+	},
+	{
+		Method: http.MethodDelete, Path: "/webhooks/:id", ID: "deleteWebhook", Summary: "Delete a webhook by ID", Tag: "webhooks",
+		Role:      "admin",
+		Responses: map[int]interface{}{http.StatusOK: Message{}, http.StatusBadRequest: Error{}, http.StatusForbidden: Error{}, http.StatusNotFound: Error{}},
+	},
+	{
+		Method: http.MethodGet, Path: "/webhooks/dead-letters", ID: "getWebhookDeadLetters", Summary: "Get the deliveries that failed every attempt", Tag: "webhooks",
+		Role:      "admin",
+		Responses: map[int]interface{}{http.StatusOK: []models.WebhookDelivery{}, http.StatusForbidden: Error{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/webhooks/dead-letters/:id/redeliver", ID: "redeliverWebhookDeadLetter", Summary: "Post a dead letter again", Tag: "webhooks",
+		Description: "Only dead letters can be redelivered: deliveries still pending, or delivered, are a Conflict (409).",
+		Role:        "admin",
+		Responses:   map[int]interface{}{http.StatusAccepted: models.WebhookDelivery{}, http.StatusBadRequest: Error{}, http.StatusForbidden: Error{}, http.StatusNotFound: Error{}, http.StatusConflict: Error{}},
+	},
+	{
+		Method: http.MethodGet, Path: "/audit", ID: "getAudit", Summary: "Get the audit log of class and booking changes, oldest first", Tag: "audit",
+		Role: "admin",
+		Parameters: []Parameter{
+			{Name: "actor", In: "query", Description: "Only changes made by this principal, case-insensitive"},
+			{Name: "action", In: "query", Description: "Only changes recorded as this event, e.g. class.updated"},
+			{Name: "resource", In: "query", Description: "class or booking"},
+			{Name: "resource_id", In: "query", Description: "Only changes of the resource with this ID"},
+			{Name: "request_id", In: "query", Description: "Only changes made by the request with this X-Request-ID"},
+			fromParameter,
+			toParameter,
+		},
+		Responses: map[int]interface{}{http.StatusOK: []models.AuditEntry{}, http.StatusBadRequest: Error{}, http.StatusForbidden: Error{}},
+	},
+	{
+		Method: http.MethodGet, Path: "/audit/verify", ID: "verifyAudit", Summary: "Check that no audit entry was changed or removed", Tag: "audit",
+		Role:      "admin",
+		Responses: map[int]interface{}{http.StatusOK: models.AuditVerification{}, http.StatusForbidden: Error{}},
+	},
+}
\ No newline at end of file
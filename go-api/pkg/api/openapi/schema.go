@@ -0,0 +1,186 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema is the subset of JSON Schema 2020-12 used by the API.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+}
+
+const localDateTimePattern = `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}$`
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// rawMessageType holds arbitrary JSON, documented as an unconstrained schema.
+var rawMessageType = reflect.TypeOf(json.RawMessage{})
+
+/**
+ * @brief schemaFor returns the schema of a Go type, registering named structs as components.
+ *
+ * @param t reflect.Type: The Go type.
+ * @param components map[string]*Schema: The component schemas, filled as structs are found.
+ * @return *Schema: A $ref for named structs, an inline schema otherwise.
+ */
+func schemaFor(t reflect.Type, components map[string]*Schema) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == rawMessageType:
+		return &Schema{}
+	case t.Kind() == reflect.Struct:
+		if _, ok := components[t.Name()]; !ok {
+			components[t.Name()] = nil
+			components[t.Name()] = structSchema(t, components)
+		}
+		return &Schema{Ref: "#/components/schemas/" + t.Name()}
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: schemaFor(t.Elem(), components)}
+	case t.Kind() == reflect.Map:
+		return &Schema{Type: "object"}
+	case t.Kind() == reflect.String:
+		return &Schema{Type: "string"}
+	case t.Kind() == reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		return &Schema{Type: "number"}
+	}
+	return &Schema{}
+}
+
+func structSchema(t reflect.Type, components map[string]*Schema) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, omitempty := jsonName(field)
+		if name == "" {
+			continue
+		}
+
+		property := schemaFor(field.Type, components)
+		required := applyValidateTag(property, field.Tag.Get("validate"))
+		if required && !omitempty {
+			schema.Required = append(schema.Required, name)
+		}
+		schema.Properties[name] = property
+	}
+
+	return schema
+}
+
+func jsonName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+
+	parts := strings.Split(tag, ",")
+	name := parts[0]
+	if name == "" {
+		name = field.Name
+	}
+
+	omitempty := false
+	for _, option := range parts[1:] {
+		omitempty = omitempty || option == "omitempty"
+	}
+	return name, omitempty
+}
+
+/**
+ * @brief applyValidateTag translates go-playground validator rules into schema constraints.
+ *
+ * @param schema *Schema: The property schema to constrain.
+ * @param tag string: The validate struct tag.
+ * @return bool: Whether the property is required.
+ */
+func applyValidateTag(schema *Schema, tag string) bool {
+	required := false
+
+	rules := strings.Split(tag, ",")
+	for i, rule := range rules {
+		name, param, _ := strings.Cut(rule, "=")
+
+		switch name {
+		case "dive":
+			// The remaining rules apply to the elements of a slice.
+			if schema.Items != nil {
+				applyValidateTag(schema.Items, strings.Join(rules[i+1:], ","))
+			}
+			return required
+		case "required":
+			required = true
+		case "alphanum":
+			schema.Pattern = "^[a-zA-Z0-9]+$"
+		case "timezone":
+			schema.Description = "IANA time zone name, e.g. Europe/Madrid"
+		case "datetime":
+			schema.Pattern = localDateTimePattern
+			schema.Description = "Local wall-clock time, without offset"
+		case "oneof":
+			schema.Enum = strings.Fields(param)
+		case "email":
+			schema.Format = "email"
+		case "url":
+			schema.Format = "uri"
+		case "min", "max", "gte", "lte":
+			limit, err := strconv.Atoi(param)
+			if err != nil {
+				continue
+			}
+			applyBound(schema, name == "min" || name == "gte", limit)
+		}
+	}
+
+	return required
+}
+
+func applyBound(schema *Schema, lower bool, limit int) {
+	switch schema.Type {
+	case "string":
+		if lower {
+			schema.MinLength = &limit
+		} else {
+			schema.MaxLength = &limit
+		}
+	case "integer", "number":
+		bound := float64(limit)
+		if lower {
+			schema.Minimum = &bound
+		} else {
+			schema.Maximum = &bound
+		}
+	}
+}
\ No newline at end of file
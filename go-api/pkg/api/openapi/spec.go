@@ -0,0 +1,231 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Servers    []Server             `json:"servers"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Server struct {
+	URL string `json:"url"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type string `json:"type"`
+	In   string `json:"in"`
+	Name string `json:"name"`
+}
+
+// PathItem maps lower-case HTTP methods to operations.
+type PathItem map[string]*OperationObject
+
+type OperationObject struct {
+	OperationID string                     `json:"operationId"`
+	Summary     string                     `json:"summary"`
+	Description string                     `json:"description,omitempty"`
+	Tags        []string                   `json:"tags"`
+	Parameters  []ParameterObject          `json:"parameters,omitempty"`
+	RequestBody *RequestBody               `json:"requestBody,omitempty"`
+	Responses   map[string]*ResponseObject `json:"responses"`
+	Security    []map[string][]string      `json:"security,omitempty"`
+}
+
+type ParameterObject struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type ResponseObject struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+var (
+	documentOnce sync.Once
+	document     *Document
+)
+
+/**
+ * @brief Spec returns the OpenAPI document of every operation in Operations.
+ */
+func Spec() *Document {
+	documentOnce.Do(func() {
+		document = build(Operations)
+	})
+	return document
+}
+
+func build(operations []Operation) *Document {
+	components := map[string]*Schema{}
+	doc := &Document{
+		OpenAPI: "3.1.0",
+		Info: Info{
+			Title:       "go-api",
+			Version:     "1.0.0",
+			Description: "Studio classes and bookings API.",
+		},
+		Servers: []Server{{URL: BasePath}},
+		Paths:   map[string]*PathItem{},
+		Components: Components{
+			Schemas: components,
+			SecuritySchemes: map[string]*SecurityScheme{
+				"apiKey": {Type: "apiKey", In: "header", Name: "X-API-Key"},
+			},
+		},
+	}
+
+	for _, operation := range operations {
+		path := SpecPath(operation.Path)
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = &PathItem{}
+			doc.Paths[path] = item
+		}
+		(*item)[strings.ToLower(operation.Method)] = operationObject(operation, components)
+	}
+
+	return doc
+}
+
+func operationObject(operation Operation, components map[string]*Schema) *OperationObject {
+	object := &OperationObject{
+		OperationID: operation.ID,
+		Summary:     operation.Summary,
+		Description: operation.Description,
+		Tags:        []string{operation.Tag},
+		Responses:   map[string]*ResponseObject{},
+	}
+
+	documented := map[string]bool{}
+	for _, parameter := range operation.Parameters {
+		if parameter.In == "path" {
+			documented[parameter.Name] = true
+		}
+	}
+
+	for _, segment := range strings.Split(operation.Path, "/") {
+		if strings.HasPrefix(segment, ":") && !documented[strings.TrimPrefix(segment, ":")] {
+			object.Parameters = append(object.Parameters, ParameterObject{
+				Name:     strings.TrimPrefix(segment, ":"),
+				In:       "path",
+				Required: true,
+				Schema:   &Schema{Type: "integer"},
+			})
+		}
+	}
+	for _, parameter := range operation.Parameters {
+		object.Parameters = append(object.Parameters, ParameterObject{
+			Name:        parameter.Name,
+			In:          parameter.In,
+			Description: parameter.Description,
+			Required:    parameter.Required,
+			Schema:      &Schema{Type: "string"},
+		})
+	}
+
+	if operation.Request != nil {
+		object.RequestBody = &RequestBody{Required: true, Content: map[string]*MediaType{}}
+
+		mediaTypes := operation.RequestTypes
+		if len(mediaTypes) == 0 {
+			mediaTypes = []string{"application/json"}
+		}
+		for _, mediaType := range mediaTypes {
+			schema := &Schema{Type: "string"}
+			if mediaType == "application/json" {
+				schema = schemaFor(reflect.TypeOf(operation.Request), components)
+			}
+			object.RequestBody.Content[mediaType] = &MediaType{Schema: schema}
+		}
+	}
+
+	if operation.Role != "" {
+		object.Security = []map[string][]string{{"apiKey": {}}}
+	}
+
+	responses := map[int]interface{}{}
+	for status, body := range commonResponses {
+		responses[status] = body
+	}
+	for status, body := range operation.Responses {
+		responses[status] = body
+	}
+
+	statuses := make([]int, 0, len(responses))
+	for status := range responses {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+
+	for _, status := range statuses {
+		response := &ResponseObject{Description: http.StatusText(status)}
+		if file, ok := responses[status].(File); ok {
+			response.Content = map[string]*MediaType{}
+			for _, mediaType := range file.MediaTypes {
+				response.Content[mediaType] = &MediaType{Schema: &Schema{Type: "string", Format: "binary"}}
+			}
+			if file.JSON != nil {
+				response.Content["application/json"] = &MediaType{Schema: schemaFor(reflect.TypeOf(file.JSON), components)}
+			}
+		} else if body := responses[status]; body != nil {
+			mediaType := "application/json"
+			if _, ok := body.(Problem); ok {
+				mediaType = "application/problem+json"
+			}
+			response.Content = map[string]*MediaType{mediaType: {Schema: schemaFor(reflect.TypeOf(body), components)}}
+		}
+		object.Responses[strconv.Itoa(status)] = response
+	}
+
+	return object
+}
+
+/**
+ * @brief SpecPath converts a Gin route relative to BasePath into an OpenAPI path template.
+ *
+ * @param path string: e.g. "/classes/:id".
+ * @return string: e.g. "/classes/{id}".
+ */
+func SpecPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + strings.TrimPrefix(segment, ":") + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
\ No newline at end of file
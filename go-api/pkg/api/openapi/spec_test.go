@@ -0,0 +1,39 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemasFollowValidateTags(t *testing.T) {
+	schemas := Spec().Components.Schemas
+
+	// The validate tags of models.CreateClass become schema constraints
+	createClass := schemas["CreateClass"]
+	if assert.NotNil(t, createClass) {
+		assert.ElementsMatch(t, []string{"name", "capacity"}, createClass.Required)
+		assert.Equal(t, 20, *createClass.Properties["name"].MaxLength)
+		assert.Equal(t, "^[a-zA-Z0-9]+$", createClass.Properties["name"].Pattern)
+		assert.Equal(t, "date-time", createClass.Properties["start_date"].Format)
+		assert.Equal(t, localDateTimePattern, createClass.Properties["local_start_date"].Pattern)
+	}
+
+	recurring := schemas["CreateRecurringClass"]
+	if assert.NotNil(t, recurring) {
+		assert.Equal(t, []string{"daily", "weekly"}, recurring.Properties["frequency"].Enum)
+		assert.Equal(t, float64(52), *recurring.Properties["count"].Maximum)
+	}
+}
+
+func TestOperationsReferenceModels(t *testing.T) {
+	operation := (*Spec().Paths["/classes/{id}"])["put"]
+
+	if assert.NotNil(t, operation) {
+		assert.Equal(t, "#/components/schemas/UpdateClass", operation.RequestBody.Content["application/json"].Schema.Ref)
+		assert.Equal(t, "#/components/schemas/Class", operation.Responses["200"].Content["application/json"].Schema.Ref)
+		assert.Contains(t, operation.Responses, "412")
+		assert.Contains(t, operation.Responses["429"].Content, "application/problem+json")
+		assert.Equal(t, "id", operation.Parameters[0].Name)
+	}
+}
\ No newline at end of file
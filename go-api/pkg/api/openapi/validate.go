@@ -0,0 +1,292 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	patternsMu sync.Mutex
+	patterns   = map[string]*regexp.Regexp{}
+)
+
+/**
+ * @brief FindOperation returns the documented operation of a Gin route.
+ *
+ * @param method string: The HTTP method.
+ * @param fullPath string: The Gin route, with or without BasePath.
+ * @return *OperationObject: nil when the route is not documented.
+ */
+func FindOperation(method string, fullPath string) *OperationObject {
+	item, ok := Spec().Paths[SpecPath(strings.TrimPrefix(fullPath, BasePath))]
+	if !ok {
+		return nil
+	}
+	return (*item)[strings.ToLower(method)]
+}
+
+/**
+ * @brief ValidateRequests rejects JSON request bodies that do not match the documented schema with 400.
+ *
+ * Unknown properties are tolerated, as they are by the handlers.
+ */
+func ValidateRequests() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		operation := FindOperation(c.Request.Method, c.FullPath())
+		if operation == nil || operation.RequestBody == nil {
+			c.Next()
+			return
+		}
+
+		mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+		if mediaType != "application/json" {
+			c.Next()
+			return
+		}
+
+		media, ok := operation.RequestBody.Content[mediaType]
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, gin.H{"error": "Unsupported Content-Type"})
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		var value interface{}
+		if err := json.Unmarshal(body, &value); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+			return
+		}
+
+		if violations := Validate(media.Schema, value, false); len(violations) > 0 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + strings.Join(violations, "; ")})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+/**
+ * @brief ValidateResponses reports responses whose status, content type or body is not documented.
+ *
+ * Response bodies are validated strictly: properties missing from the schema
+ * are violations. Meant for tests and staging, as it buffers every response.
+ *
+ * @param report func(string): Called once per violation.
+ */
+func ValidateResponses(report func(violation string)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		operation := FindOperation(c.Request.Method, c.FullPath())
+		if operation == nil {
+			c.Next()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		route := c.Request.Method + " " + c.FullPath()
+		status := c.Writer.Status()
+		response, ok := operation.Responses[strconv.Itoa(status)]
+		if !ok {
+			report(fmt.Sprintf("%s: undocumented status %d", route, status))
+			return
+		}
+
+		if response.Content == nil {
+			if recorder.body.Len() > 0 {
+				report(fmt.Sprintf("%s: status %d must not have a body", route, status))
+			}
+			return
+		}
+
+		mediaType, _, _ := mime.ParseMediaType(c.Writer.Header().Get("Content-Type"))
+		media, ok := response.Content[mediaType]
+		if !ok {
+			report(fmt.Sprintf("%s: undocumented content type %q for status %d", route, mediaType, status))
+			return
+		}
+
+		if media.Schema.Type == "string" {
+			return
+		}
+
+		var value interface{}
+		if err := json.Unmarshal(recorder.body.Bytes(), &value); err != nil {
+			report(fmt.Sprintf("%s: invalid JSON body for status %d", route, status))
+			return
+		}
+
+		for _, violation := range Validate(media.Schema, value, true) {
+			report(fmt.Sprintf("%s: status %d: %s", route, status, violation))
+		}
+	}
+}
+
+/**
+ * @brief Validate checks a decoded JSON value against a schema of the document.
+ *
+ * @param schema *Schema: The schema, possibly a $ref.
+ * @param value interface{}: The value decoded by encoding/json.
+ * @param strict bool: Whether properties absent from the schema are violations.
+ * @return []string: The violations, empty when the value matches.
+ */
+func Validate(schema *Schema, value interface{}, strict bool) []string {
+	var violations []string
+	validate(schema, value, "$", strict, &violations)
+	return violations
+}
+
+func validate(schema *Schema, value interface{}, at string, strict bool, violations *[]string) {
+	if schema == nil {
+		return
+	}
+	if schema.Ref != "" {
+		validate(Spec().Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")], value, at, strict, violations)
+		return
+	}
+
+	fail := func(format string, args ...interface{}) {
+		*violations = append(*violations, at+": "+fmt.Sprintf(format, args...))
+	}
+
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			fail("expected an object")
+			return
+		}
+		for _, name := range schema.Required {
+			if _, ok := object[name]; !ok {
+				fail("missing required property %q", name)
+			}
+		}
+		names := make([]string, 0, len(object))
+		for name := range object {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			property, ok := schema.Properties[name]
+			if !ok {
+				if strict && schema.Properties != nil {
+					fail("undocumented property %q", name)
+				}
+				continue
+			}
+			validate(property, object[name], at+"."+name, strict, violations)
+		}
+
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			fail("expected an array")
+			return
+		}
+		for i, item := range items {
+			validate(schema.Items, item, fmt.Sprintf("%s[%d]", at, i), strict, violations)
+		}
+
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			fail("expected a string")
+			return
+		}
+		length := utf8.RuneCountInString(text)
+		if schema.MinLength != nil && length < *schema.MinLength {
+			fail("shorter than %d characters", *schema.MinLength)
+		}
+		if schema.MaxLength != nil && length > *schema.MaxLength {
+			fail("longer than %d characters", *schema.MaxLength)
+		}
+		if schema.Pattern != "" && text != "" && !compile(schema.Pattern).MatchString(text) {
+			fail("does not match %s", schema.Pattern)
+		}
+		if schema.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, text); err != nil {
+				fail("not an RFC 3339 date-time")
+			}
+		}
+		if len(schema.Enum) > 0 && !contains(schema.Enum, text) {
+			fail("not one of %v", schema.Enum)
+		}
+
+	case "integer", "number":
+		number, ok := value.(float64)
+		if !ok || (schema.Type == "integer" && number != math.Trunc(number)) {
+			fail("expected an %s", schema.Type)
+			return
+		}
+		if schema.Minimum != nil && number < *schema.Minimum {
+			fail("less than %v", *schema.Minimum)
+		}
+		if schema.Maximum != nil && number > *schema.Maximum {
+			fail("greater than %v", *schema.Maximum)
+		}
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			fail("expected a boolean")
+		}
+	}
+}
+
+func compile(pattern string) *regexp.Regexp {
+	patternsMu.Lock()
+	defer patternsMu.Unlock()
+
+	re, ok := patterns[pattern]
+	if !ok {
+		re = regexp.MustCompile(pattern)
+		patterns[pattern] = re
+	}
+	return re
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// responseRecorder copies everything written to the response.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (recorder *responseRecorder) Write(data []byte) (int, error) {
+	recorder.body.Write(data)
+	return recorder.ResponseWriter.Write(data)
+}
+
+func (recorder *responseRecorder) WriteString(data string) (int, error) {
+	recorder.body.WriteString(data)
+	return recorder.ResponseWriter.WriteString(data)
+}
\ No newline at end of file
@@ -0,0 +1,58 @@
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateRequestsRejectsInvalidBody(t *testing.T) {
+	// Create a test Gin router
+	router := gin.Default()
+	router.POST("/api/classes", ValidateRequests(), func(c *gin.Context) {
+		c.IndentedJSON(http.StatusCreated, gin.H{})
+	})
+
+	// The name breaks the maxLength and pattern derived from the validate tags
+	body := `{"name": "Not alphanumeric and far too long", "start_date": "2023-10-06T16:00:00Z", "end_date": "2023-10-16T17:00:00Z", "capacity": 10}`
+	req, _ := http.NewRequest(http.MethodPost, "/api/classes", strings.NewReader(body))
+	req.Header.Add("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert that the HTTP status code is Bad Request (400)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "$.name")
+}
+
+func TestValidateResponsesReportsViolations(t *testing.T) {
+	var violations []string
+
+	// Create a test Gin router whose handler breaks the contract of GET /classes/:id
+	router := gin.Default()
+	router.Use(ValidateResponses(func(violation string) { violations = append(violations, violation) }))
+	router.GET("/classes/:id", func(c *gin.Context) {
+		if c.Param("id") == "1" {
+			c.IndentedJSON(http.StatusOK, gin.H{"id": 1, "name": "Yoga", "colour": "blue"})
+			return
+		}
+		c.IndentedJSON(http.StatusTeapot, gin.H{"error": "teapot"})
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/classes/1", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Missing required properties and undocumented ones are reported
+	assert.Contains(t, strings.Join(violations, "\n"), `missing required property "start_date"`)
+	assert.Contains(t, strings.Join(violations, "\n"), `undocumented property "colour"`)
+
+	violations = nil
+	req, _ = http.NewRequest(http.MethodGet, "/classes/2", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, []string{"GET /classes/:id: undocumented status 418"}, violations)
+}
\ No newline at end of file
@@ -0,0 +1,41 @@
+package plans
+
+import (
+	"net/http"
+	"go-api/pkg/models"
+	"go-api/pkg/mockDatabase"
+	"github.com/gin-gonic/gin"
+)
+
+/**
+ * @brief GetPlans returns the membership plans and class-credit packs on sale.
+ * 
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func GetPlans(c *gin.Context) {
+	c.IndentedJSON(http.StatusOK, database.Plans)
+}
+
+/**
+ * @brief PostPlans creates a new plan: a pack of credits or a membership of some days.
+ * 
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func PostPlans(c *gin.Context) {
+	var newPlan models.CreatePlan
+
+	if err := c.ShouldBindJSON(&newPlan); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid Plan"})
+		return
+	}
+
+	if err := models.PlanValidate.Struct(newPlan); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid Plan"})
+		return
+	}
+
+	var plan models.Plan = database.CreatePlan(newPlan)
+
+	database.Plans = append(database.Plans, plan)
+	c.IndentedJSON(http.StatusCreated, plan)
+}
\ No newline at end of file
@@ -1,27 +1,123 @@
 package api
 
 import (
+	"log"
+	"go-api/pkg/api/audit"
 	"go-api/pkg/api/bookings"
+	"go-api/pkg/api/calendar"
 	"go-api/pkg/api/classes"
+	"go-api/pkg/api/members"
+	"go-api/pkg/api/middleware"
+	"go-api/pkg/api/openapi"
+	"go-api/pkg/api/plans"
+	"go-api/pkg/api/studios"
+	"go-api/pkg/api/webhooks"
+	"go-api/pkg/attendance"
+	"go-api/pkg/events"
+	"go-api/pkg/notifications"
+	"go-api/pkg/payments"
+	"go-api/pkg/webhook"
 	"github.com/gin-gonic/gin"
 )
 
 func InitRouter() *gin.Engine {
+	return NewRouter(DefaultConfig())
+}
+
+func NewRouter(config Config) *gin.Engine {
 	router := gin.Default()
+	if err := router.SetTrustedProxies(config.TrustedProxies); err != nil {
+		panic("trusted_proxies: " + err.Error())
+	}
+	router.Use(middleware.RequestID(), middleware.SecurityHeaders(config.SecurityHeaders), middleware.CORS(config.CORS))
+
+	idempotent := middleware.Idempotency(config.IdempotencyStore, config.IdempotencyTTL)
+	admin := middleware.RequireRole("admin")
+	desk := middleware.RequireRole("admin", "staff")
+
+	bookings.Policy = config.Cancellation
+	attendance.DefaultTokens = attendance.NewTokens([]byte(config.CheckInSecret), config.CheckInTokenTTL)
+	payments.Default = config.Payments
+	payments.Currency = config.Currency
+
+	webhook.Default = webhook.NewDispatcher(webhook.WithRetries(config.WebhookAttempts, config.WebhookBackoff), webhook.WithHistory(config.WebhookHistory))
+	events.Subscribe("webhooks", webhook.Handle)
 
-	api := router.Group("/api")
+	notifications.Default = nil
+	if config.SMTP.Addr != "" {
+		notifications.Default = notifications.New(notifications.NewSMTPMailer(config.SMTP))
+	}
+	events.Subscribe("notifications", notifications.Handle)
+	events.Subscribe("reminders", notifications.NewReminders(config.Jobs).Handle)
+
+	router.GET("/openapi.json", openapi.GetSpec)
+	router.GET("/docs", openapi.GetDocs)
+
+	api := router.Group(openapi.BasePath, middleware.Authenticate(config.APIKeys), middleware.RateLimiter(config.Limiter, config.RateLimits))
+	if config.ValidateResponses {
+		api.Use(openapi.ValidateResponses(func(violation string) { log.Println("openapi:", violation) }))
+	}
+	if config.ValidateRequests {
+		api.Use(openapi.ValidateRequests())
+	}
 	{
 		api.GET("/classes", classes.GetClasses)
 		api.GET("/classes/:id", classes.GetClassesByID)
-		api.POST("/classes", classes.PostClasses)
+		api.GET("/classes/:id/roster", bookings.GetClassRoster)
+		api.POST("/classes/:id/check-in", bookings.CheckInClass)
+		api.POST("/classes/:id/roster/check-in", bookings.BulkCheckInClass)
+		api.POST("/classes", idempotent, classes.PostClasses)
+		api.POST("/classes/recurring", classes.PostRecurringClasses)
+		api.POST("/classes/import", idempotent, classes.ImportClasses)
 		api.PUT("/classes/:id", classes.UpdateClass)
 		api.DELETE("/classes/:id", classes.DeleteClass)
+		api.POST("/classes/:id/restore", admin, classes.RestoreClass)
 
 		api.GET("/bookings", bookings.GetBookings)
+		api.GET("/bookings/export", bookings.ExportBookings)
 		api.GET("/bookings/:id", bookings.GetBookingByID)
-		api.POST("/bookings", bookings.PostBookings)
+		api.POST("/bookings", idempotent, bookings.PostBookings)
 		api.PUT("/bookings/:id", bookings.UpdateBooking)
-		api.DELETE("/bookings/:id", bookings.DeleteBooking)
+		api.DELETE("/bookings/:id", bookings.CancelBooking)
+		api.POST("/bookings/:id/confirm", bookings.ConfirmBooking)
+		api.POST("/bookings/:id/cancel", bookings.CancelBooking)
+		api.POST("/bookings/:id/attend", bookings.AttendBooking)
+		api.POST("/bookings/:id/no-show", bookings.MarkNoShow)
+		api.POST("/bookings/:id/restore", admin, bookings.RestoreBooking)
+		api.GET("/bookings/:id/checkin-code", middleware.RequireRole("admin", "staff", "member"), bookings.GetCheckInCode)
+
+		api.POST("/kiosk/check-in", middleware.RequireRole("admin", "kiosk"), bookings.KioskCheckIn)
+		api.POST("/payments/webhook", bookings.PaymentWebhook)
+
+		api.GET("/studios", studios.GetStudios)
+		api.GET("/studios/:id", studios.GetStudioByID)
+		api.POST("/studios", studios.PostStudios)
+		api.GET("/studios/:id/schedule.ics", calendar.GetStudioSchedule)
+		api.GET("/studios/:id/email-templates", studios.GetEmailTemplates)
+		api.PUT("/studios/:id/email-templates/:kind", admin, studios.PutEmailTemplate)
+		api.DELETE("/studios/:id/email-templates/:kind", admin, studios.DeleteEmailTemplate)
+
+		api.GET("/members", desk, members.GetMembers)
+		api.GET("/members/:id", desk, members.GetMemberByID)
+		api.GET("/members/:id/attendance", members.GetMemberAttendance)
+		api.POST("/members", desk, members.PostMembers)
+		api.POST("/members/:id/feed", middleware.RequireRole("admin", "member"), members.PostMemberFeed)
+		api.GET("/members/:id/credits", middleware.RequireRole("admin", "staff", "member"), members.GetMemberCredits)
+		api.POST("/members/:id/plans", desk, members.PostMemberPlan)
+
+		api.GET("/plans", plans.GetPlans)
+		api.POST("/plans", admin, plans.PostPlans)
+
+		api.GET("/calendar/:token", calendar.GetMemberFeed)
+
+		api.GET("/webhooks", admin, webhooks.GetWebhooks)
+		api.POST("/webhooks", admin, webhooks.PostWebhooks)
+		api.DELETE("/webhooks/:id", admin, webhooks.DeleteWebhook)
+		api.GET("/webhooks/dead-letters", admin, webhooks.GetDeadLetters)
+		api.POST("/webhooks/dead-letters/:id/redeliver", admin, webhooks.RedeliverDeadLetter)
+
+		api.GET("/audit", admin, audit.GetAudit)
+		api.GET("/audit/verify", admin, audit.VerifyAudit)
 	}
 
 	return router
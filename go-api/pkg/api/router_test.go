@@ -0,0 +1,96 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"go-api/pkg/api/middleware"
+	"go-api/pkg/api/openapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoutesMatchOpenAPISpec(t *testing.T) {
+	router := InitRouter()
+
+	// Every route registered under /api must be documented
+	registered := map[string]bool{}
+	for _, route := range router.Routes() {
+		if !strings.HasPrefix(route.Path, openapi.BasePath+"/") {
+			continue
+		}
+		path := openapi.SpecPath(strings.TrimPrefix(route.Path, openapi.BasePath))
+		registered[route.Method+" "+path] = true
+
+		item, ok := openapi.Spec().Paths[path]
+		if assert.True(t, ok, "%s %s is not documented", route.Method, route.Path) {
+			assert.Contains(t, *item, strings.ToLower(route.Method), "%s %s is not documented", route.Method, route.Path)
+		}
+	}
+
+	// Every documented operation must be registered
+	for path, item := range openapi.Spec().Paths {
+		for method := range *item {
+			assert.True(t, registered[strings.ToUpper(method)+" "+path], "%s %s is documented but not routed", strings.ToUpper(method), path)
+		}
+	}
+}
+
+func TestGetOpenAPISpec(t *testing.T) {
+	router := InitRouter()
+
+	// Create a GET request to retrieve the OpenAPI document
+	req, _ := http.NewRequest(http.MethodGet, "/openapi.json", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert that the HTTP status code is OK (200)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var spec map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "3.1.0", spec["openapi"])
+
+	// The docs page is served from the binary
+	req, _ = http.NewRequest(http.MethodGet, "/docs", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "/openapi.json")
+}
+func TestRateLimitIgnoresSpoofedForwardedFor(t *testing.T) {
+	config := DefaultConfig()
+	config.RateLimits = map[string]middleware.RateLimitPolicy{
+		"default": {IP: middleware.RateLimit{Requests: 1, Period: time.Minute}},
+	}
+	router := NewRouter(config)
+
+	get := func(forwardedFor string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodGet, openapi.BasePath+"/studios", nil)
+		req.RemoteAddr = "203.0.113.7:41000"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Anonymous clients are limited by the address they connect from, whatever they claim
+	assert.Equal(t, http.StatusOK, get("198.51.100.1").Code)
+	assert.Equal(t, http.StatusTooManyRequests, get("198.51.100.2").Code)
+	assert.Equal(t, http.StatusTooManyRequests, get("198.51.100.3").Code)
+
+	// Behind a trusted proxy, the forwarded address is the client's
+	config.TrustedProxies = []string{"203.0.113.7"}
+	config.Limiter = middleware.NewMemoryLimiter()
+	router = NewRouter(config)
+
+	assert.Equal(t, http.StatusOK, get("198.51.100.1").Code)
+	assert.Equal(t, http.StatusOK, get("198.51.100.2").Code)
+	assert.Equal(t, http.StatusTooManyRequests, get("198.51.100.2").Code)
+}
\ No newline at end of file
@@ -0,0 +1,64 @@
+package studios
+
+import (
+	"net/http"
+	"strconv"
+	"go-api/pkg/models"
+	"go-api/pkg/mockDatabase"
+	"github.com/gin-gonic/gin"
+)
+
+/**
+ * @brief GetStudios returns a list of all studios.
+ * 
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func GetStudios(c *gin.Context) {
+	c.IndentedJSON(http.StatusOK, database.Studios)
+}
+
+/**
+ * @brief GetStudioByID returns a studio by its ID.
+ * 
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func GetStudioByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	existingStudio, index := database.FindItemByID(database.Studios, id)
+	if existingStudio == nil {
+		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Studio not found"})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, database.Studios[index])
+}
+
+/**
+ * @brief PostStudios creates a new studio with its IANA time zone.
+ * 
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func PostStudios(c *gin.Context) {
+	var newStudio models.CreateStudio
+
+	if err := c.ShouldBindJSON(&newStudio); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid Studio"})
+		return
+	}
+
+	if err := models.StudioValidate.Struct(newStudio); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid Studio"})
+		return
+	}
+
+	var studio models.Studio = database.CreateStudio(newStudio)
+
+	database.Studios = append(database.Studios, studio)
+	c.IndentedJSON(http.StatusCreated, studio)
+}
\ No newline at end of file
@@ -0,0 +1,168 @@
+package studios
+
+import (
+	"bytes"
+	"go-api/pkg/models"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"go-api/pkg/mockDatabase"
+	"testing"
+	"go-api/pkg/api/openapi"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// newRouter returns a test Gin router that fails the test on responses not matching the OpenAPI spec.
+func newRouter(t *testing.T) *gin.Engine {
+	router := gin.Default()
+	router.Use(openapi.ValidateResponses(func(violation string) { t.Error(violation) }))
+	return router
+}
+
+func TestGetStudios(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.GET("/studios", GetStudios)
+
+	// Create a GET request to retrieve all studios
+	req, _ := http.NewRequest(http.MethodGet, "/studios", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert that the HTTP status code is OK (200)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Parse the response body into a slice of studios
+	var response []models.Studio
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, len(database.Studios), len(response))
+	for i, studio := range database.Studios {
+		assert.Equal(t, studio.ID, response[i].ID)
+		assert.Equal(t, studio.TimeZone, response[i].TimeZone)
+	}
+}
+
+func TestGetStudioByID(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.GET("/studios/:id", GetStudioByID)
+
+	// Create a GET request to retrieve studio with ID 2
+	req, _ := http.NewRequest(http.MethodGet, "/studios/2", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert that the HTTP status code is OK (200)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.Studio
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "Madrid", response.Name)
+	assert.Equal(t, "Europe/Madrid", response.TimeZone)
+}
+
+func TestPostStudios(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.POST("/studios", PostStudios)
+
+	newStudioJSON, _ := json.Marshal(models.CreateStudio{Name: "Lisboa", TimeZone: "Europe/Lisbon"})
+
+	// Create a POST request to create a new studio
+	req, _ := http.NewRequest(http.MethodPost, "/studios", bytes.NewReader(newStudioJSON))
+	req.Header.Add("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert that the HTTP status code is Created (201)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var createdStudio models.Studio
+	err := json.Unmarshal(w.Body.Bytes(), &createdStudio)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NotZero(t, createdStudio.ID)
+	assert.Equal(t, "Europe/Lisbon", createdStudio.TimeZone)
+}
+
+func TestPostStudiosInvalidTimeZone(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.POST("/studios", PostStudios)
+
+	newStudioJSON, _ := json.Marshal(models.CreateStudio{Name: "Nowhere", TimeZone: "Mars/Olympus"})
+
+	// Create a POST request with a zone that is not in the IANA database
+	req, _ := http.NewRequest(http.MethodPost, "/studios", bytes.NewReader(newStudioJSON))
+	req.Header.Add("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert that the HTTP status code is Bad Request (400)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+func TestEmailTemplates(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.GET("/studios/:id/email-templates", GetEmailTemplates)
+	router.PUT("/studios/:id/email-templates/:kind", PutEmailTemplate)
+	router.DELETE("/studios/:id/email-templates/:kind", DeleteEmailTemplate)
+
+	// Override the confirmation template of studio 2
+	override, _ := json.Marshal(models.UpdateEmailTemplate{Subject: "¡Reserva confirmada! {{.Class.Name}}", Text: "Hola {{.Member.Name}}", HTML: "<p>Hola {{.Member.Name}}</p>"})
+	req, _ := http.NewRequest(http.MethodPut, "/studios/2/email-templates/confirmation", bytes.NewReader(override))
+	req.Header.Add("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req, _ = http.NewRequest(http.MethodGet, "/studios/2/email-templates", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var templates []models.EmailTemplate
+	if err := json.Unmarshal(w.Body.Bytes(), &templates); err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, templates, 5)
+	assert.Equal(t, "Hola {{.Member.Name}}", templates[0].Text)
+	assert.False(t, templates[0].Default)
+	assert.True(t, templates[1].Default)
+
+	// Templates referring to unknown fields are rejected before they are stored
+	broken, _ := json.Marshal(models.UpdateEmailTemplate{Subject: "{{.Class.Teacher}}", Text: "x", HTML: "x"})
+	req, _ = http.NewRequest(http.MethodPut, "/studios/2/email-templates/change", bytes.NewReader(broken))
+	req.Header.Add("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Deleting the override reverts to the default
+	req, _ = http.NewRequest(http.MethodDelete, "/studios/2/email-templates/confirmation", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, database.EmailTemplates)
+
+	req, _ = http.NewRequest(http.MethodDelete, "/studios/2/email-templates/confirmation", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
\ No newline at end of file
@@ -0,0 +1,114 @@
+package studios
+
+import (
+	"net/http"
+	"strconv"
+	"go-api/pkg/models"
+	"go-api/pkg/mockDatabase"
+	"go-api/pkg/notifications"
+	"github.com/gin-gonic/gin"
+)
+
+/**
+ * @brief GetEmailTemplates returns the notification templates of a studio, overridden or default.
+ * 
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func GetEmailTemplates(c *gin.Context) {
+	studio, ok := findStudio(c)
+	if !ok {
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, notifications.Templates(studio.ID))
+}
+
+/**
+ * @brief PutEmailTemplate overrides one notification template of a studio.
+ *
+ * The template is rendered with sample data first, so broken templates are rejected here
+ * rather than when a member should have been notified.
+ * 
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func PutEmailTemplate(c *gin.Context) {
+	studio, ok := findStudio(c)
+	if !ok {
+		return
+	}
+
+	kind := c.Param("kind")
+	if _, ok := notifications.Template(studio.ID, kind); !ok {
+		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+		return
+	}
+
+	var update models.UpdateEmailTemplate
+
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid Template"})
+		return
+	}
+
+	if err := models.EmailTemplateValidate.Struct(update); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid Template"})
+		return
+	}
+
+	template := models.EmailTemplate{StudioId: studio.ID, Kind: kind, Subject: update.Subject, Text: update.Text, HTML: update.HTML}
+	if err := notifications.Check(template, *studio); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid Template: " + err.Error()})
+		return
+	}
+
+	for index, existing := range database.EmailTemplates {
+		if existing.StudioId == studio.ID && existing.Kind == kind {
+			database.EmailTemplates[index] = template
+			c.IndentedJSON(http.StatusOK, template)
+			return
+		}
+	}
+
+	database.EmailTemplates = append(database.EmailTemplates, template)
+	c.IndentedJSON(http.StatusOK, template)
+}
+
+/**
+ * @brief DeleteEmailTemplate removes the override of a template, reverting the studio to the default.
+ * 
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func DeleteEmailTemplate(c *gin.Context) {
+	studio, ok := findStudio(c)
+	if !ok {
+		return
+	}
+
+	for index, existing := range database.EmailTemplates {
+		if existing.StudioId == studio.ID && existing.Kind == c.Param("kind") {
+			database.EmailTemplates = append(database.EmailTemplates[:index], database.EmailTemplates[index+1:]...)
+			fallback, _ := notifications.Template(studio.ID, existing.Kind)
+			c.IndentedJSON(http.StatusOK, fallback)
+			return
+		}
+	}
+
+	c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+}
+
+// findStudio looks up the studio of the :id parameter, answering 400 or 404 when there is none.
+func findStudio(c *gin.Context) (*models.Studio, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return nil, false
+	}
+
+	studio, _ := database.FindItemByID(database.Studios, id)
+	if studio == nil {
+		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Studio not found"})
+		return nil, false
+	}
+	return studio.(*models.Studio), true
+}
\ No newline at end of file
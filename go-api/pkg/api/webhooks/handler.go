@@ -0,0 +1,121 @@
+package webhooks
+
+import (
+	"net/http"
+	"strconv"
+	"go-api/pkg/models"
+	"go-api/pkg/mockDatabase"
+	"go-api/pkg/webhook"
+	"github.com/gin-gonic/gin"
+)
+
+/**
+ * @brief GetWebhooks returns a list of all webhooks, without their secrets.
+ * 
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func GetWebhooks(c *gin.Context) {
+	var webhooks []models.Webhook
+	database.Transaction(func(tx *database.Tx) error {
+		webhooks = make([]models.Webhook, len(database.Webhooks))
+		for i, item := range database.Webhooks {
+			item.Secret = ""
+			webhooks[i] = item
+		}
+		return nil
+	})
+	c.IndentedJSON(http.StatusOK, webhooks)
+}
+
+/**
+ * @brief PostWebhooks subscribes a URL to events. The signing secret is only returned here.
+ * 
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func PostWebhooks(c *gin.Context) {
+	var newWebhook models.CreateWebhook
+
+	if err := c.ShouldBindJSON(&newWebhook); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid Webhook"})
+		return
+	}
+
+	if err := models.WebhookValidate.Struct(newWebhook); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid Webhook"})
+		return
+	}
+
+	var item models.Webhook
+	database.Transaction(func(tx *database.Tx) error {
+		item = database.CreateWebhook(newWebhook, webhook.NewSecret())
+		database.Webhooks = append(database.Webhooks, item)
+		return nil
+	})
+	c.IndentedJSON(http.StatusCreated, item)
+}
+
+/**
+ * @brief DeleteWebhook removes a webhook by its ID. Deliveries already in flight still finish.
+ * 
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func DeleteWebhook(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	err = database.Transaction(func(tx *database.Tx) error {
+		existingWebhook, index := database.FindItemByID(database.Webhooks, id)
+		if existingWebhook == nil {
+			return database.ErrNotFound
+		}
+		database.Webhooks = append(database.Webhooks[:index:index], database.Webhooks[index+1:]...)
+		return nil
+	})
+	if err != nil {
+		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, gin.H{"message": "Webhook deleted"})
+}
+
+/**
+ * @brief GetDeadLetters returns the deliveries that failed every attempt.
+ * 
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func GetDeadLetters(c *gin.Context) {
+	c.IndentedJSON(http.StatusOK, webhook.Default.Deliveries(webhook.StatusDead))
+}
+
+/**
+ * @brief RedeliverDeadLetter posts a dead letter again, with a fresh retry budget.
+ *
+ * Deliveries still pending, or delivered since, are not dead letters and get 409.
+ * 
+ * @param c *gin.Context: The Gin HTTP context.
+ */
+func RedeliverDeadLetter(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	delivery, err := webhook.Default.Redeliver(id)
+	switch err {
+	case nil:
+		c.IndentedJSON(http.StatusAccepted, delivery)
+	case webhook.ErrDeliveryNotFound:
+		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Delivery not found"})
+	case webhook.ErrDeliveryPending:
+		c.IndentedJSON(http.StatusConflict, gin.H{"error": "Delivery is still pending"})
+	default:
+		c.IndentedJSON(http.StatusConflict, gin.H{"error": "Delivery is not a dead letter"})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,222 @@
+package webhooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+	"go-api/pkg/models"
+	"go-api/pkg/mockDatabase"
+	"go-api/pkg/api/openapi"
+	"go-api/pkg/events"
+	"go-api/pkg/webhook"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// newRouter returns a test Gin router that fails the test on responses not matching the OpenAPI spec.
+func newRouter(t *testing.T) *gin.Engine {
+	router := gin.Default()
+	router.Use(openapi.ValidateResponses(func(violation string) { t.Error(violation) }))
+	return router
+}
+
+// receiver is a webhook endpoint answering with status and recording what it receives.
+type receiver struct {
+	mu         sync.Mutex
+	status     int
+	bodies     [][]byte
+	signatures []string
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bodies = append(r.bodies, body)
+	r.signatures = append(r.signatures, req.Header.Get(webhook.SignatureHeader))
+	w.WriteHeader(r.status)
+}
+
+func (r *receiver) setStatus(status int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = status
+}
+
+func (r *receiver) received() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.bodies)
+}
+
+func TestPostWebhooks(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.POST("/webhooks", PostWebhooks)
+	router.GET("/webhooks", GetWebhooks)
+
+	newWebhookJSON, _ := json.Marshal(models.CreateWebhook{URL: "https://example.com/hooks", Events: []string{events.BookingCreated}})
+
+	req, _ := http.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader(newWebhookJSON))
+	req.Header.Add("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// The secret is returned once, on creation
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var created models.Webhook
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	assert.NotEmpty(t, created.Secret)
+
+	req, _ = http.NewRequest(http.MethodGet, "/webhooks", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), created.Secret)
+
+	// Unknown event types are rejected
+	invalidJSON, _ := json.Marshal(models.CreateWebhook{URL: "https://example.com/hooks", Events: []string{"booking.exploded"}})
+	req, _ = http.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader(invalidJSON))
+	req.Header.Add("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestWebhooksChangeConcurrently(t *testing.T) {
+	// Create a test Gin router
+	router := newRouter(t)
+	router.POST("/webhooks", PostWebhooks)
+	router.DELETE("/webhooks/:id", DeleteWebhook)
+
+	webhook.Default = webhook.NewDispatcher(webhook.WithRetries(1, time.Millisecond))
+	defer func() { webhook.Default = webhook.NewDispatcher() }()
+	before := len(database.Webhooks)
+
+	// Webhooks are added and removed while events are published
+	body, _ := json.Marshal(models.CreateWebhook{URL: "http://127.0.0.1:1/hooks", Events: []string{events.ClassCreated}})
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader(body))
+			req.Header.Add("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			var created models.Webhook
+			json.Unmarshal(w.Body.Bytes(), &created)
+
+			req, _ = http.NewRequest(http.MethodDelete, "/webhooks/"+strconv.Itoa(created.ID), nil)
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusOK, w.Code)
+		}()
+		go func(id int64) {
+			defer wg.Done()
+			webhook.Handle(models.DomainEvent{ID: id, Type: events.ClassCreated, Data: json.RawMessage(`{"id": 1}`)})
+		}(int64(i))
+	}
+	wg.Wait()
+
+	// Every webhook added was removed, and none other
+	assert.Len(t, database.Webhooks, before)
+
+	// Unknown webhooks are Not Found (404)
+	req, _ := http.NewRequest(http.MethodDelete, "/webhooks/999", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestWebhookDeliveryRetriesAndDeadLetters(t *testing.T) {
+	target := &receiver{status: http.StatusInternalServerError}
+	server := httptest.NewServer(target)
+	defer server.Close()
+
+	webhook.Default = webhook.NewDispatcher(webhook.WithRetries(3, time.Millisecond))
+	defer func() { webhook.Default = webhook.NewDispatcher() }()
+
+	subscription := database.CreateWebhook(models.CreateWebhook{URL: server.URL, Events: []string{events.ClassCreated}}, webhook.NewSecret())
+	saved := database.Webhooks
+	database.Webhooks = []models.Webhook{subscription}
+	defer func() { database.Webhooks = saved }()
+
+	// Events the webhook is not subscribed to are not posted
+	webhook.Handle(models.DomainEvent{ID: 1, Type: events.BookingCreated, Data: json.RawMessage(`{"id": 1}`)})
+	webhook.Handle(models.DomainEvent{ID: 2, Type: events.ClassCreated, Data: json.RawMessage(`{"id": 42, "name": "Yoga"}`)})
+
+	// Every attempt fails, so the delivery ends up as a dead letter
+	router := newRouter(t)
+	router.GET("/webhooks/dead-letters", GetDeadLetters)
+	router.POST("/webhooks/dead-letters/:id/redeliver", RedeliverDeadLetter)
+
+	var deadLetters []models.WebhookDelivery
+	assert.Eventually(t, func() bool {
+		req, _ := http.NewRequest(http.MethodGet, "/webhooks/dead-letters", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		deadLetters = nil
+		json.Unmarshal(w.Body.Bytes(), &deadLetters)
+		return len(deadLetters) == 1
+	}, time.Second, 5*time.Millisecond)
+	if len(deadLetters) != 1 {
+		t.FailNow()
+	}
+	assert.Equal(t, 3, target.received())
+	assert.Equal(t, 3, deadLetters[0].Attempts)
+	assert.Equal(t, http.StatusInternalServerError, deadLetters[0].ResponseStatus)
+	assert.Equal(t, events.ClassCreated, deadLetters[0].EventType)
+
+	// Every attempt is signed with the webhook secret
+	target.mu.Lock()
+	assert.True(t, webhook.Verify(subscription.Secret, target.signatures[0], target.bodies[0], time.Minute))
+	assert.False(t, webhook.Verify("whsec_other", target.signatures[0], target.bodies[0], time.Minute))
+	var event models.WebhookEvent
+	json.Unmarshal(target.bodies[0], &event)
+	target.mu.Unlock()
+	var class models.Class
+	json.Unmarshal(event.Data, &class)
+	assert.Equal(t, "evt_2", event.ID)
+	assert.Equal(t, events.ClassCreated, event.Type)
+	assert.Equal(t, "Yoga", class.Name)
+
+	// Once the receiver is fixed, the dead letter can be redelivered
+	target.setStatus(http.StatusNoContent)
+	req, _ := http.NewRequest(http.MethodPost, "/webhooks/dead-letters/"+strconv.Itoa(deadLetters[0].ID)+"/redeliver", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Eventually(t, func() bool {
+		return len(webhook.Default.Deliveries(webhook.StatusDelivered)) == 1
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, 4, target.received())
+
+	// A delivered delivery is no longer a dead letter, so it is a Conflict (409)
+	req, _ = http.NewRequest(http.MethodPost, "/webhooks/dead-letters/"+strconv.Itoa(deadLetters[0].ID)+"/redeliver", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "Delivery is not a dead letter")
+	assert.Equal(t, 4, target.received())
+
+	// Unknown deliveries are Not Found (404)
+	req, _ = http.NewRequest(http.MethodPost, "/webhooks/dead-letters/999/redeliver", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
\ No newline at end of file
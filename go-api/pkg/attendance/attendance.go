@@ -0,0 +1,240 @@
+package attendance
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"strings"
+	"time"
+	"go-api/pkg/events"
+	"go-api/pkg/models"
+	"go-api/pkg/mockDatabase"
+)
+
+// CheckInOpens is how long before a session starts its members can check in. Check-in closes when the session ends.
+var CheckInOpens = time.Hour
+
+// NoShowWindow is how long after a session ends MarkNoShows still marks its bookings; older sessions are left as they are.
+var NoShowWindow = 24 * time.Hour
+
+var (
+	ErrBookingNotFound = errors.New("booking not found")
+	ErrMemberNotFound  = errors.New("member not found")
+	ErrNotBooked       = errors.New("member has no booking open for check-in")
+	ErrNotOpen         = errors.New("check-in is not open")
+	ErrNotConfirmed    = errors.New("only confirmed bookings can be checked in")
+)
+
+// Audit records a check-in or no-show in the transaction making it, e.g. in the audit log.
+type Audit func(tx *database.Tx, before models.Booking, after models.Booking) error
+
+/**
+ * @brief CheckIn marks the booking of a class named by request as attended.
+ *
+ * A member is checked in through their confirmed booking of the class whose
+ * session is open for check-in. Checking in an attended booking again returns
+ * it unchanged.
+ *
+ * @param classId int: The class being checked in.
+ * @param request models.CheckIn: The booking, or the member.
+ * @param at time.Time: When the member arrived.
+ * @param audit Audit: Records the check-in; nil records nothing.
+ * @return models.Booking: The attended booking.
+ */
+func CheckIn(classId int, request models.CheckIn, at time.Time, audit Audit) (models.Booking, error) {
+	index, err := findBooking(classId, request, at)
+	if err != nil {
+		return models.Booking{}, err
+	}
+
+	booking := database.Bookings[index]
+	if booking.Status == models.BookingAttended {
+		return booking, nil
+	}
+	if booking.Status != models.BookingConfirmed {
+		return booking, ErrNotConfirmed
+	}
+	if !Open(booking, at) {
+		return booking, ErrNotOpen
+	}
+
+	booking.Transition(models.BookingAttended, at)
+	booking.Version++
+	err = database.Transaction(func(tx *database.Tx) error {
+		index, err := database.Locate(database.Bookings, booking.ID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Record(events.BookingAttended, booking.ID, booking); err != nil {
+			return err
+		}
+		if audit != nil {
+			if err := audit(tx, database.Bookings[index], booking); err != nil {
+				return err
+			}
+		}
+		database.Bookings[index] = booking
+		return nil
+	})
+	return booking, err
+}
+
+/**
+ * @brief Open reports whether a booking can be checked in at the given time.
+ *
+ * @return bool: true from CheckInOpens before the session starts until it ends.
+ */
+func Open(booking models.Booking, at time.Time) bool {
+	return !at.Before(booking.Date.Add(-CheckInOpens)) && at.Before(sessionEnd(booking))
+}
+
+func findBooking(classId int, request models.CheckIn, at time.Time) (int, error) {
+	if request.BookingId != 0 {
+		_, index := database.FindItemByID(database.Bookings, request.BookingId)
+		if index < 0 || database.Bookings[index].ClassId != classId {
+			return -1, ErrBookingNotFound
+		}
+		return index, nil
+	}
+
+	member, _ := database.FindItemByID(database.Members, request.MemberId)
+	if member == nil {
+		return -1, ErrMemberNotFound
+	}
+
+	for i, booking := range database.Bookings {
+		if booking.ClassId != classId || booking.DeletedAt != nil || !strings.EqualFold(booking.Name, member.(*models.Member).Name) {
+			continue
+		}
+		if (booking.Status == models.BookingConfirmed || booking.Status == models.BookingAttended) && Open(booking, at) {
+			return i, nil
+		}
+	}
+	return -1, ErrNotBooked
+}
+
+/**
+ * @brief MarkNoShows marks the confirmed bookings whose session is over as no-shows.
+ *
+ * Only sessions that ended within NoShowWindow before at are considered, so
+ * that bookings of past sessions, e.g. imported or seeded ones, are not
+ * marked in bulk the first time the job runs.
+ *
+ * @param at time.Time: The current time.
+ * @param audit Audit: Records each booking marked; nil records nothing.
+ * @return int: The number of bookings marked.
+ */
+func MarkNoShows(at time.Time, audit Audit) (int, error) {
+	marked := 0
+	err := database.Transaction(func(tx *database.Tx) error {
+		for i, booking := range database.Bookings {
+			if booking.Status != models.BookingConfirmed || booking.DeletedAt != nil {
+				continue
+			}
+			if end := sessionEnd(booking); at.Before(end) || at.Sub(end) > NoShowWindow {
+				continue
+			}
+
+			booking.Transition(models.BookingNoShow, at)
+			booking.Version++
+			if err := tx.Record(events.BookingNoShow, booking.ID, booking); err != nil {
+				return err
+			}
+			if audit != nil {
+				if err := audit(tx, database.Bookings[i], booking); err != nil {
+					return err
+				}
+			}
+			database.Bookings[i] = booking
+			marked++
+		}
+		return nil
+	})
+	return marked, err
+}
+
+/**
+ * @brief Run calls MarkNoShows every interval until ctx is done.
+ *
+ * @param audit Audit: Records each booking marked, passed to MarkNoShows.
+ */
+func Run(ctx context.Context, interval time.Duration, audit Audit) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if _, err := MarkNoShows(time.Now(), audit); err != nil {
+			log.Println("attendance: marking no-shows:", err)
+		}
+	}
+}
+
+/**
+ * @brief History returns the bookings of a member, newest first, with their attendance counts.
+ *
+ * @param member models.Member: The member; bookings belong to the member whose name they carry.
+ */
+func History(member models.Member) models.Attendance {
+	attendance := models.Attendance{MemberId: member.ID, History: []models.AttendanceRecord{}}
+
+	for _, booking := range database.Bookings {
+		if booking.DeletedAt != nil || !strings.EqualFold(booking.Name, member.Name) {
+			continue
+		}
+
+		record := models.AttendanceRecord{BookingId: booking.ID, ClassId: booking.ClassId, Date: booking.Date, Status: booking.Status}
+		if len(booking.Transitions) > 0 {
+			record.At = booking.Transitions[len(booking.Transitions)-1].At
+		}
+		class, _ := database.FindItemByID(database.Classes, booking.ClassId)
+		if class != nil {
+			record.ClassName = class.(*models.Class).Name
+		}
+		attendance.History = append(attendance.History, record)
+
+		switch {
+		case booking.Status == models.BookingAttended:
+			attendance.Attended++
+		case booking.Status == models.BookingNoShow:
+			attendance.NoShows++
+		case booking.Cancellation != nil && booking.Cancellation.Outcome == "late":
+			attendance.LateCancellations++
+		}
+	}
+
+	sort.SliceStable(attendance.History, func(i, j int) bool {
+		return attendance.History[i].Date.After(attendance.History[j].Date)
+	})
+	return attendance
+}
+
+/**
+ * @brief NoShows counts the sessions a member booked and missed since a given time, for rules such as booking limits.
+ *
+ * @param name string: The member name carried by the bookings.
+ * @param since time.Time: Only sessions starting at or after this time count.
+ */
+func NoShows(name string, since time.Time) int {
+	count := 0
+	for _, booking := range database.Bookings {
+		if booking.Status == models.BookingNoShow && booking.DeletedAt == nil && strings.EqualFold(booking.Name, name) && !booking.Date.Before(since) {
+			count++
+		}
+	}
+	return count
+}
+
+// sessionEnd is when the session of a booking ends.
+func sessionEnd(booking models.Booking) time.Time {
+	class, _ := database.FindItemByID(database.Classes, booking.ClassId)
+	if class == nil {
+		return booking.Date.Add(time.Hour)
+	}
+	return booking.Date.Add(class.(*models.Class).SessionLength())
+}
\ No newline at end of file
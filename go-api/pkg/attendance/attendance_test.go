@@ -0,0 +1,116 @@
+package attendance
+
+import (
+	"testing"
+	"time"
+	"go-api/pkg/events"
+	"go-api/pkg/models"
+	"go-api/pkg/mockDatabase"
+	"github.com/stretchr/testify/assert"
+)
+
+// addSession adds a class of one 90-minute session and a confirmed booking of the given member to it.
+func addSession(name string, start time.Time) (models.Class, models.Booking) {
+	class := database.CreateClass(models.CreateClass{Name: "Session", StudioId: 1, StartDate: start, EndDate: start.Add(90 * time.Minute), Capacity: 10})
+	database.Classes = append(database.Classes, class)
+
+	booking := database.CreateBooking(models.CreateBooking{Name: name, ClassId: class.ID, Date: start})
+	database.Bookings = append(database.Bookings, booking)
+	return class, booking
+}
+
+func TestCheckIn(t *testing.T) {
+	start := time.Date(2024, 3, 1, 18, 0, 0, 0, time.UTC)
+	class, booking := addSession("Martin", start)
+
+	// Check-in opens an hour before the session
+	_, err := CheckIn(class.ID, models.CheckIn{MemberId: 2}, start.Add(-2*time.Hour), nil)
+	assert.ErrorIs(t, err, ErrNotBooked)
+	_, err = CheckIn(class.ID, models.CheckIn{BookingId: booking.ID}, start.Add(-2*time.Hour), nil)
+	assert.ErrorIs(t, err, ErrNotOpen)
+
+	// Martin (member 2) is checked in through his booking
+	attended, err := CheckIn(class.ID, models.CheckIn{MemberId: 2}, start.Add(-30*time.Minute), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, booking.ID, attended.ID)
+	assert.Equal(t, models.BookingAttended, attended.Status)
+	assert.Equal(t, 2, attended.Version)
+	assert.Equal(t, events.BookingAttended, database.Outbox[len(database.Outbox)-1].Type)
+
+	// Checking in again changes nothing
+	again, err := CheckIn(class.ID, models.CheckIn{BookingId: booking.ID}, start, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, again.Version)
+
+	// Bookings of other classes and unknown members are not found
+	_, err = CheckIn(1, models.CheckIn{BookingId: booking.ID}, start, nil)
+	assert.ErrorIs(t, err, ErrBookingNotFound)
+	_, err = CheckIn(class.ID, models.CheckIn{MemberId: 999}, start, nil)
+	assert.ErrorIs(t, err, ErrMemberNotFound)
+}
+
+func TestMarkNoShows(t *testing.T) {
+	start := time.Date(2024, 3, 2, 18, 0, 0, 0, time.UTC)
+	_, missed := addSession("Joaquin", start)
+	_, later := addSession("Joaquin", start.Add(24*time.Hour))
+
+	var audited []models.Booking
+	audit := func(tx *database.Tx, before models.Booking, after models.Booking) error {
+		audited = append(audited, after)
+		return nil
+	}
+
+	// Nothing is marked while the session runs
+	_, err := MarkNoShows(start.Add(time.Hour), audit)
+	assert.NoError(t, err)
+	stored, _ := database.FindItemByID(database.Bookings, missed.ID)
+	assert.Equal(t, models.BookingConfirmed, stored.(*models.Booking).Status)
+
+	marked, err := MarkNoShows(start.Add(2 * time.Hour), audit)
+	assert.NoError(t, err)
+	assert.NotZero(t, marked)
+	assert.Len(t, audited, marked)
+
+	stored, _ = database.FindItemByID(database.Bookings, missed.ID)
+	assert.Equal(t, models.BookingNoShow, stored.(*models.Booking).Status)
+	assert.Equal(t, start.Add(2*time.Hour), stored.(*models.Booking).Transitions[0].At)
+	stored, _ = database.FindItemByID(database.Bookings, later.ID)
+	assert.Equal(t, models.BookingConfirmed, stored.(*models.Booking).Status)
+
+	// Sessions that ended before the window, such as the seeded ones, are left alone
+	stored, _ = database.FindItemByID(database.Bookings, 1)
+	assert.Equal(t, models.BookingConfirmed, stored.(*models.Booking).Status)
+	marked, err = MarkNoShows(start.Add(24*time.Hour+NoShowWindow+2*time.Hour), audit)
+	assert.NoError(t, err)
+	assert.Zero(t, marked)
+	stored, _ = database.FindItemByID(database.Bookings, later.ID)
+	assert.Equal(t, models.BookingConfirmed, stored.(*models.Booking).Status)
+
+	// The no-show counts from the session date
+	assert.Equal(t, 1, NoShows("joaquin", start))
+	assert.Equal(t, 0, NoShows("joaquin", start.Add(time.Minute)))
+}
+
+func TestHistory(t *testing.T) {
+	start := time.Date(2024, 3, 4, 18, 0, 0, 0, time.UTC)
+	class, attended := addSession("Lucia", start)
+	_, missed := addSession("Lucia", start.Add(24*time.Hour))
+	addSession("Other", start)
+
+	_, err := CheckIn(class.ID, models.CheckIn{BookingId: attended.ID}, start, nil)
+	assert.NoError(t, err)
+	_, err = MarkNoShows(start.Add(48 * time.Hour), nil)
+	assert.NoError(t, err)
+
+	history := History(models.Member{ID: 7, Name: "lucia"})
+	assert.Equal(t, 7, history.MemberId)
+	assert.Equal(t, 1, history.Attended)
+	assert.Equal(t, 1, history.NoShows)
+	if assert.Len(t, history.History, 2) {
+		// Newest first
+		assert.Equal(t, missed.ID, history.History[0].BookingId)
+		assert.Equal(t, models.BookingNoShow, history.History[0].Status)
+		assert.Equal(t, "Session", history.History[1].ClassName)
+		assert.Equal(t, start, history.History[1].At)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,43 @@
+package attendance
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/skip2/go-qrcode"
+)
+
+/**
+ * @brief QRCodePNG renders content as a QR code PNG image.
+ *
+ * @param content string: The encoded text, e.g. a check-in token.
+ * @param size int: The width and height of the image, in pixels.
+ */
+func QRCodePNG(content string, size int) ([]byte, error) {
+	return qrcode.Encode(content, qrcode.Medium, size)
+}
+
+/**
+ * @brief QRCodeSVG renders content as a QR code SVG image, one unit per module.
+ *
+ * @param content string: The encoded text, e.g. a check-in token.
+ */
+func QRCodeSVG(content string) ([]byte, error) {
+	code, err := qrcode.New(content, qrcode.Medium)
+	if err != nil {
+		return nil, err
+	}
+	bitmap := code.Bitmap()
+
+	var svg bytes.Buffer
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, len(bitmap), len(bitmap))
+	fmt.Fprintf(&svg, `<rect width="%d" height="%d" fill="#fff"/><path fill="#000" d="`, len(bitmap), len(bitmap))
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&svg, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+	svg.WriteString(`"/></svg>`)
+	return svg.Bytes(), nil
+}
\ No newline at end of file
@@ -0,0 +1,162 @@
+package attendance
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"go-api/pkg/models"
+	"go-api/pkg/mockDatabase"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid check-in token")
+	ErrExpiredToken = errors.New("check-in token has expired")
+	ErrTokenUsed    = errors.New("check-in token has already been used")
+)
+
+// Tokens issues and redeems the signed check-in tokens shown as QR codes.
+//
+// A token is "<payload>.<signature>", both base64url: the payload holds the
+// booking ID, the expiry and a random nonce, and the signature is its
+// HMAC-SHA256. Redeemed nonces are remembered until the token expires, so a
+// token checks in once.
+type Tokens struct {
+	secret []byte
+	ttl    time.Duration
+
+	mu   sync.Mutex
+	used map[string]time.Time
+}
+
+// DefaultTokens is used by the check-in code and kiosk endpoints.
+var DefaultTokens = NewTokens(nil, 2*time.Minute)
+
+/**
+ * @brief NewTokens returns a token issuer.
+ *
+ * @param secret []byte: The signing key; a random one is generated when empty, so tokens do not survive restarts.
+ * @param ttl time.Duration: How long a token stays valid.
+ */
+func NewTokens(secret []byte, ttl time.Duration) *Tokens {
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			panic(err)
+		}
+	}
+	return &Tokens{secret: secret, ttl: ttl, used: map[string]time.Time{}}
+}
+
+/**
+ * @brief Issue returns a new token for a booking.
+ *
+ * @param bookingId int: The booking to check in.
+ * @param now time.Time: The current time.
+ * @return string: The token.
+ * @return time.Time: When it expires.
+ */
+func (tokens *Tokens) Issue(bookingId int, now time.Time) (string, time.Time, error) {
+	nonce := make([]byte, 8)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", time.Time{}, err
+	}
+
+	expires := now.Add(tokens.ttl).Truncate(time.Second)
+	payload := strconv.Itoa(bookingId) + "." + strconv.FormatInt(expires.Unix(), 10) + "." + hex.EncodeToString(nonce)
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	return encoded + "." + tokens.sign(encoded), expires.UTC(), nil
+}
+
+/**
+ * @brief Redeem checks the signature and expiry of a token and spends it on use.
+ *
+ * The token is only marked as used when use succeeds, so a check-in refused
+ * for another reason can be retried with the same token.
+ *
+ * @param token string: The token read from the QR code.
+ * @param now time.Time: The current time.
+ * @param use func(bookingId int) error: Called with the booking ID the token was issued for.
+ */
+func (tokens *Tokens) Redeem(token string, now time.Time, use func(bookingId int) error) error {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(tokens.sign(encoded))) {
+		return ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return ErrInvalidToken
+	}
+	fields := strings.Split(string(payload), ".")
+	if len(fields) != 3 {
+		return ErrInvalidToken
+	}
+	bookingId, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return ErrInvalidToken
+	}
+	unix, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return ErrInvalidToken
+	}
+
+	expires := time.Unix(unix, 0)
+	if !now.Before(expires) {
+		return ErrExpiredToken
+	}
+
+	tokens.mu.Lock()
+	defer tokens.mu.Unlock()
+	for nonce, until := range tokens.used {
+		if !now.Before(until) {
+			delete(tokens.used, nonce)
+		}
+	}
+	if _, replayed := tokens.used[fields[2]]; replayed {
+		return ErrTokenUsed
+	}
+	if err := use(bookingId); err != nil {
+		return err
+	}
+	tokens.used[fields[2]] = expires
+	return nil
+}
+
+func (tokens *Tokens) sign(payload string) string {
+	mac := hmac.New(sha256.New, tokens.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+/**
+ * @brief CheckIn redeems a token read by the kiosk and checks its booking in.
+ *
+ * The booking must be open for check-in (see Open), so a token only works
+ * while its class is about to start or running.
+ *
+ * @param token string: The token read from the QR code.
+ * @param at time.Time: When it was scanned.
+ * @param audit Audit: Records the check-in; nil records nothing.
+ * @return models.Booking: The attended booking.
+ */
+func (tokens *Tokens) CheckIn(token string, at time.Time, audit Audit) (models.Booking, error) {
+	var booking models.Booking
+	err := tokens.Redeem(token, at, func(bookingId int) error {
+		found, _ := database.FindItemByID(database.Bookings, bookingId)
+		if found == nil {
+			return ErrBookingNotFound
+		}
+
+		var err error
+		booking, err = CheckIn(found.(*models.Booking).ClassId, models.CheckIn{BookingId: bookingId}, at, audit)
+		return err
+	})
+	return booking, err
+}
\ No newline at end of file
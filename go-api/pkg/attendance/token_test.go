@@ -0,0 +1,70 @@
+package attendance
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+	"go-api/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokens(t *testing.T) {
+	issued := time.Date(2024, 3, 1, 18, 0, 0, 0, time.UTC)
+	tokens := NewTokens([]byte("secret"), 2*time.Minute)
+
+	token, expires, err := tokens.Issue(7, issued)
+	assert.NoError(t, err)
+	assert.Equal(t, issued.Add(2*time.Minute), expires)
+
+	// A refused check-in does not spend the token
+	refused := errors.New("refused")
+	err = tokens.Redeem(token, issued, func(int) error { return refused })
+	assert.ErrorIs(t, err, refused)
+
+	var bookingId int
+	err = tokens.Redeem(token, issued.Add(time.Minute), func(id int) error { bookingId = id; return nil })
+	assert.NoError(t, err)
+	assert.Equal(t, 7, bookingId)
+
+	// Replayed, expired, tampered and foreign tokens are rejected
+	use := func(int) error { return nil }
+	assert.ErrorIs(t, tokens.Redeem(token, issued.Add(time.Minute), use), ErrTokenUsed)
+
+	token, _, _ = tokens.Issue(7, issued)
+	assert.ErrorIs(t, tokens.Redeem(token, issued.Add(2*time.Minute), use), ErrExpiredToken)
+	assert.ErrorIs(t, tokens.Redeem(token+"x", issued, use), ErrInvalidToken)
+	assert.ErrorIs(t, tokens.Redeem("garbage", issued, use), ErrInvalidToken)
+	assert.ErrorIs(t, NewTokens([]byte("other"), time.Minute).Redeem(token, issued, use), ErrInvalidToken)
+}
+
+func TestTokensCheckIn(t *testing.T) {
+	start := time.Date(2024, 3, 5, 18, 0, 0, 0, time.UTC)
+	_, booking := addSession("Diego", start)
+	tokens := NewTokens(nil, 2*time.Minute)
+
+	// The class must be starting soon
+	token, _, _ := tokens.Issue(booking.ID, start.Add(-2*time.Hour))
+	_, err := tokens.CheckIn(token, start.Add(-2*time.Hour), nil)
+	assert.ErrorIs(t, err, ErrNotOpen)
+
+	token, _, _ = tokens.Issue(booking.ID, start.Add(-5*time.Minute))
+	attended, err := tokens.CheckIn(token, start.Add(-4*time.Minute), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, models.BookingAttended, attended.Status)
+
+	token, _, _ = tokens.Issue(999999, start)
+	_, err = tokens.CheckIn(token, start, nil)
+	assert.ErrorIs(t, err, ErrBookingNotFound)
+}
+
+func TestQRCode(t *testing.T) {
+	png, err := QRCodePNG("token", 128)
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(png, []byte("\x89PNG")))
+
+	svg, err := QRCodeSVG("token")
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(svg, []byte("<svg")))
+	assert.Contains(t, string(svg), "h1v1h-1z")
+}
\ No newline at end of file
@@ -0,0 +1,28 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"go-api/pkg/models"
+)
+
+/**
+ * @brief ListAudit returns the audit log, oldest first. Filters are passed with Query.
+ */
+func (client *Client) ListAudit(ctx context.Context, options ...RequestOption) ([]models.AuditEntry, error) {
+	var entries []models.AuditEntry
+	_, err := client.do(ctx, http.MethodGet, "/audit", nil, &entries, options)
+	return entries, err
+}
+
+/**
+ * @brief VerifyAudit checks the hash chain of the audit log.
+ */
+func (client *Client) VerifyAudit(ctx context.Context, options ...RequestOption) (*models.AuditVerification, error) {
+	var verification models.AuditVerification
+	if _, err := client.do(ctx, http.MethodGet, "/audit/verify", nil, &verification, options); err != nil {
+		return nil, err
+	}
+	return &verification, nil
+}
\ No newline at end of file
@@ -0,0 +1,138 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+
+	"go-api/pkg/models"
+)
+
+/**
+ * @brief ListBookings returns all bookings.
+ */
+func (client *Client) ListBookings(ctx context.Context, options ...RequestOption) ([]models.Booking, error) {
+	var bookings []models.Booking
+	_, err := client.do(ctx, http.MethodGet, "/bookings", nil, &bookings, options)
+	return bookings, err
+}
+
+/**
+ * @brief GetBooking returns a booking by its ID, or a *NotFoundError.
+ */
+func (client *Client) GetBooking(ctx context.Context, id int, options ...RequestOption) (*models.Booking, error) {
+	var booking models.Booking
+	if _, err := client.do(ctx, http.MethodGet, "/bookings/"+strconv.Itoa(id), nil, &booking, options); err != nil {
+		return nil, err
+	}
+	return &booking, nil
+}
+
+/**
+ * @brief CreateBooking creates a booking. It is only retried when an IdempotencyKey is given.
+ */
+func (client *Client) CreateBooking(ctx context.Context, newBooking models.CreateBooking, options ...RequestOption) (*models.Booking, error) {
+	var booking models.Booking
+	if _, err := client.do(ctx, http.MethodPost, "/bookings", newBooking, &booking, options); err != nil {
+		return nil, err
+	}
+	return &booking, nil
+}
+
+/**
+ * @brief UpdateBooking replaces a booking. Use IfMatch to avoid overwriting concurrent changes.
+ */
+func (client *Client) UpdateBooking(ctx context.Context, id int, updatedBooking models.UpdateBooking, options ...RequestOption) (*models.Booking, error) {
+	var booking models.Booking
+	if _, err := client.do(ctx, http.MethodPut, "/bookings/"+strconv.Itoa(id), updatedBooking, &booking, options); err != nil {
+		return nil, err
+	}
+	return &booking, nil
+}
+
+/**
+ * @brief ConfirmBooking confirms a pending or waitlisted booking.
+ */
+func (client *Client) ConfirmBooking(ctx context.Context, id int, options ...RequestOption) (*models.Booking, error) {
+	return client.transitionBooking(ctx, id, "confirm", options)
+}
+
+/**
+ * @brief CancelBooking cancels a booking by its ID and returns it with the cancellation outcome.
+ *
+ * Bookings whose session has started, or already cancelled, fail with a *ConflictError.
+ */
+func (client *Client) CancelBooking(ctx context.Context, id int, options ...RequestOption) (*models.Booking, error) {
+	return client.transitionBooking(ctx, id, "cancel", options)
+}
+
+/**
+ * @brief AttendBooking marks a confirmed booking as attended.
+ */
+func (client *Client) AttendBooking(ctx context.Context, id int, options ...RequestOption) (*models.Booking, error) {
+	return client.transitionBooking(ctx, id, "attend", options)
+}
+
+/**
+ * @brief MarkNoShow marks a confirmed booking as a no-show.
+ */
+func (client *Client) MarkNoShow(ctx context.Context, id int, options ...RequestOption) (*models.Booking, error) {
+	return client.transitionBooking(ctx, id, "no-show", options)
+}
+
+/**
+ * @brief RestoreBooking brings back a booking soft-deleted with its class (admin).
+ */
+func (client *Client) RestoreBooking(ctx context.Context, id int, options ...RequestOption) (*models.Booking, error) {
+	return client.transitionBooking(ctx, id, "restore", options)
+}
+
+// transitionBooking posts to one of the status endpoints of a booking. Transitions not allowed fail with a *ConflictError.
+func (client *Client) transitionBooking(ctx context.Context, id int, action string, options []RequestOption) (*models.Booking, error) {
+	var booking models.Booking
+	if _, err := client.do(ctx, http.MethodPost, "/bookings/"+strconv.Itoa(id)+"/"+action, nil, &booking, options); err != nil {
+		return nil, err
+	}
+	return &booking, nil
+}
+
+/**
+ * @brief GetCheckInCode issues a short-lived check-in token for a confirmed booking.
+ */
+func (client *Client) GetCheckInCode(ctx context.Context, id int, options ...RequestOption) (*models.CheckInCode, error) {
+	var code models.CheckInCode
+	if _, err := client.do(ctx, http.MethodGet, "/bookings/"+strconv.Itoa(id)+"/checkin-code", nil, &code, options); err != nil {
+		return nil, err
+	}
+	return &code, nil
+}
+
+/**
+ * @brief GetCheckInQRCode writes a new check-in token of a booking to w as a "png" or "svg" QR code.
+ */
+func (client *Client) GetCheckInQRCode(ctx context.Context, id int, format string, w io.Writer, options ...RequestOption) error {
+	options = append([]RequestOption{Query("format", format)}, options...)
+	_, err := client.do(ctx, http.MethodGet, "/bookings/"+strconv.Itoa(id)+"/checkin-code", nil, w, options)
+	return err
+}
+
+/**
+ * @brief KioskCheckIn checks in the booking of a scanned check-in token. Used tokens fail with a *ConflictError.
+ */
+func (client *Client) KioskCheckIn(ctx context.Context, token string, options ...RequestOption) (*models.Booking, error) {
+	var booking models.Booking
+	if _, err := client.do(ctx, http.MethodPost, "/kiosk/check-in", models.KioskCheckIn{Token: token}, &booking, options); err != nil {
+		return nil, err
+	}
+	return &booking, nil
+}
+
+/**
+ * @brief ExportBookings writes the bookings as "csv" or "xlsx" to w. Filters are passed with Query.
+ */
+func (client *Client) ExportBookings(ctx context.Context, format string, w io.Writer, options ...RequestOption) error {
+	options = append([]RequestOption{Query("format", format)}, options...)
+	_, err := client.do(ctx, http.MethodGet, "/bookings/export", nil, w, options)
+	return err
+}
\ No newline at end of file
@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"go-api/pkg/models"
+)
+
+/**
+ * @brief ListClasses returns all classes.
+ */
+func (client *Client) ListClasses(ctx context.Context, options ...RequestOption) ([]models.Class, error) {
+	var classes []models.Class
+	_, err := client.do(ctx, http.MethodGet, "/classes", nil, &classes, options)
+	return classes, err
+}
+
+/**
+ * @brief GetClass returns a class by its ID, or a *NotFoundError.
+ */
+func (client *Client) GetClass(ctx context.Context, id int, options ...RequestOption) (*models.Class, error) {
+	var class models.Class
+	if _, err := client.do(ctx, http.MethodGet, "/classes/"+strconv.Itoa(id), nil, &class, options); err != nil {
+		return nil, err
+	}
+	return &class, nil
+}
+
+/**
+ * @brief CreateClass creates a class. It is only retried when an IdempotencyKey is given.
+ */
+func (client *Client) CreateClass(ctx context.Context, newClass models.CreateClass, options ...RequestOption) (*models.Class, error) {
+	var class models.Class
+	if _, err := client.do(ctx, http.MethodPost, "/classes", newClass, &class, options); err != nil {
+		return nil, err
+	}
+	return &class, nil
+}
+
+/**
+ * @brief CreateRecurringClasses creates one class per occurrence of a recurring schedule.
+ */
+func (client *Client) CreateRecurringClasses(ctx context.Context, recurring models.CreateRecurringClass, options ...RequestOption) ([]models.Class, error) {
+	var classes []models.Class
+	_, err := client.do(ctx, http.MethodPost, "/classes/recurring", recurring, &classes, options)
+	return classes, err
+}
+
+/**
+ * @brief ImportClasses uploads a JSON array or CSV file of classes.
+ *
+ * contentType is application/json or text/csv. Use Query("mode", "best_effort")
+ * and Query("dry_run", "true") to change the import mode. When an atomic import
+ * is rejected the report is returned together with the error.
+ */
+func (client *Client) ImportClasses(ctx context.Context, contentType string, data io.Reader, options ...RequestOption) (*models.ClassImportReport, error) {
+	body, err := io.ReadAll(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var report models.ClassImportReport
+	_, err = client.do(ctx, http.MethodPost, "/classes/import", rawBody{contentType, body}, &report, options)
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnprocessableEntity {
+		if json.Unmarshal(apiErr.Body, &report) == nil {
+			return &report, err
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+/**
+ * @brief UpdateClass replaces a class. Use IfMatch to avoid overwriting concurrent changes.
+ */
+func (client *Client) UpdateClass(ctx context.Context, id int, updatedClass models.UpdateClass, options ...RequestOption) (*models.Class, error) {
+	var class models.Class
+	if _, err := client.do(ctx, http.MethodPut, "/classes/"+strconv.Itoa(id), updatedClass, &class, options); err != nil {
+		return nil, err
+	}
+	return &class, nil
+}
+
+/**
+ * @brief DeleteClass soft-deletes a class and its bookings by its ID.
+ */
+func (client *Client) DeleteClass(ctx context.Context, id int, options ...RequestOption) error {
+	_, err := client.do(ctx, http.MethodDelete, "/classes/"+strconv.Itoa(id), nil, nil, options)
+	return err
+}
+
+/**
+ * @brief RestoreClass brings back a soft-deleted class and the bookings deleted with it (admin).
+ */
+func (client *Client) RestoreClass(ctx context.Context, id int, options ...RequestOption) (*models.Class, error) {
+	var class models.Class
+	if _, err := client.do(ctx, http.MethodPost, "/classes/"+strconv.Itoa(id)+"/restore", nil, &class, options); err != nil {
+		return nil, err
+	}
+	return &class, nil
+}
+
+/**
+ * @brief GetClassRoster writes the bookings of a class as "csv" or "xlsx" to w.
+ */
+func (client *Client) GetClassRoster(ctx context.Context, id int, format string, w io.Writer, options ...RequestOption) error {
+	options = append([]RequestOption{Query("format", format)}, options...)
+	_, err := client.do(ctx, http.MethodGet, "/classes/"+strconv.Itoa(id)+"/roster", nil, w, options)
+	return err
+}
+
+/**
+ * @brief CheckIn marks a member or booking of a class as attended. Check-ins outside the session window fail with a *ConflictError.
+ */
+func (client *Client) CheckIn(ctx context.Context, classId int, checkIn models.CheckIn, options ...RequestOption) (*models.Booking, error) {
+	var booking models.Booking
+	if _, err := client.do(ctx, http.MethodPost, "/classes/"+strconv.Itoa(classId)+"/check-in", checkIn, &booking, options); err != nil {
+		return nil, err
+	}
+	return &booking, nil
+}
+
+/**
+ * @brief BulkCheckIn checks in several members of a class roster and reports the outcome of each one.
+ */
+func (client *Client) BulkCheckIn(ctx context.Context, classId int, checkIns models.BulkCheckIn, options ...RequestOption) (*models.CheckInReport, error) {
+	var report models.CheckInReport
+	if _, err := client.do(ctx, http.MethodPost, "/classes/"+strconv.Itoa(classId)+"/roster/check-in", checkIns, &report, options); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
\ No newline at end of file
@@ -0,0 +1,276 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client calls the go-api HTTP API. The zero value is not usable, use New.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	apiKey     string
+	maxRetries int
+	backoff    time.Duration
+}
+
+type Option func(*Client)
+
+/**
+ * @brief WithHTTPClient replaces http.DefaultClient, e.g. to set timeouts or transports.
+ */
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(client *Client) { client.httpClient = httpClient }
+}
+
+/**
+ * @brief WithAPIKey authenticates every request with an API key.
+ */
+func WithAPIKey(key string) Option {
+	return func(client *Client) { client.apiKey = key }
+}
+
+/**
+ * @brief WithRetries sets how many times idempotent calls are retried and the initial backoff, doubled on each attempt.
+ */
+func WithRetries(maxRetries int, backoff time.Duration) Option {
+	return func(client *Client) {
+		client.maxRetries = maxRetries
+		client.backoff = backoff
+	}
+}
+
+/**
+ * @brief New returns a client for the API served at baseURL, e.g. "http://localhost:8080".
+ */
+func New(baseURL string, options ...Option) *Client {
+	client := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
+		maxRetries: 3,
+		backoff:    200 * time.Millisecond,
+	}
+	for _, option := range options {
+		option(client)
+	}
+	return client
+}
+
+type requestOptions struct {
+	header   http.Header
+	query    url.Values
+	response *Response
+}
+
+// RequestOption adds headers or query parameters to a single call.
+type RequestOption func(*requestOptions)
+
+/**
+ * @brief IfMatch makes an update or delete fail with PreconditionFailedError unless the resource still has etag.
+ */
+func IfMatch(etag string) RequestOption {
+	return func(options *requestOptions) { options.header.Set("If-Match", etag) }
+}
+
+/**
+ * @brief IdempotencyKey makes a create call safe to retry; the server replays the first response.
+ */
+func IdempotencyKey(key string) RequestOption {
+	return func(options *requestOptions) { options.header.Set("Idempotency-Key", key) }
+}
+
+/**
+ * @brief TimeZone renders dates in "studio" or an IANA zone.
+ */
+func TimeZone(tz string) RequestOption {
+	return func(options *requestOptions) { options.query.Set("tz", tz) }
+}
+
+/**
+ * @brief Query sets an arbitrary query parameter, e.g. a list filter.
+ */
+func Query(name string, value string) RequestOption {
+	return func(options *requestOptions) { options.query.Set(name, value) }
+}
+
+// Response holds the metadata of a call made with CaptureResponse.
+type Response struct {
+	StatusCode int
+	ETag       string
+	Header     http.Header
+}
+
+/**
+ * @brief CaptureResponse stores the status, ETag and headers of the call into response.
+ */
+func CaptureResponse(response *Response) RequestOption {
+	return func(options *requestOptions) { options.response = response }
+}
+
+/**
+ * @brief do sends a request, retrying idempotent ones, and decodes the JSON response into out.
+ *
+ * @param ctx context.Context: Cancels the call, including backoff waits.
+ * @param method string: The HTTP method.
+ * @param path string: The path below /api.
+ * @param in interface{}: The request body, nil for none.
+ * @param out interface{}: Where to decode the response body, nil to discard it.
+ * @return *Response: The response metadata.
+ */
+func (client *Client) do(ctx context.Context, method string, path string, in interface{}, out interface{}, options []RequestOption) (*Response, error) {
+	opts := &requestOptions{header: http.Header{}, query: url.Values{}}
+	for _, option := range options {
+		option(opts)
+	}
+
+	var body []byte
+	if raw, ok := in.(rawBody); ok {
+		body = raw.data
+		opts.header.Set("Content-Type", raw.contentType)
+	} else if in != nil {
+		encoded, err := json.Marshal(in)
+		if err != nil {
+			return nil, err
+		}
+		body = encoded
+	}
+
+	target := client.baseURL + "/api" + path
+	if len(opts.query) > 0 {
+		target += "?" + opts.query.Encode()
+	}
+
+	idempotent := method != http.MethodPost || opts.header.Get("Idempotency-Key") != ""
+	attempts := 1
+	if idempotent {
+		attempts += client.maxRetries
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			if err := sleep(ctx, client.wait(attempt, lastErr)); err != nil {
+				return nil, err
+			}
+		}
+
+		response, retry, err := client.send(ctx, method, target, body, opts.header, out)
+		if response != nil && opts.response != nil {
+			*opts.response = *response
+		}
+		if err == nil || !retry {
+			return response, err
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// retryAfterError carries the Retry-After delay of a retryable response.
+type retryAfterError struct {
+	error
+	after time.Duration
+}
+
+func (err *retryAfterError) Unwrap() error { return err.error }
+
+// rawBody is a request body sent as is instead of being encoded as JSON.
+type rawBody struct {
+	contentType string
+	data        []byte
+}
+
+func (client *Client) send(ctx context.Context, method string, target string, body []byte, header http.Header, out interface{}) (*Response, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, false, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if body != nil && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	if client.apiKey != "" {
+		req.Header.Set("X-API-Key", client.apiKey)
+	}
+
+	res, err := client.httpClient.Do(req)
+	if err != nil {
+		return nil, ctx.Err() == nil, err
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, true, err
+	}
+
+	response := &Response{StatusCode: res.StatusCode, ETag: res.Header.Get("ETag"), Header: res.Header}
+
+	if res.StatusCode >= 300 && res.StatusCode != http.StatusNotModified {
+		apiErr := newError(res.StatusCode, errorMessage(data), data)
+		switch res.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			seconds, _ := strconv.Atoi(res.Header.Get("Retry-After"))
+			return response, true, &retryAfterError{apiErr, time.Duration(seconds) * time.Second}
+		}
+		return response, false, apiErr
+	}
+
+	if w, ok := out.(io.Writer); ok {
+		_, err := w.Write(data)
+		return response, false, err
+	}
+	if out != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, out); err != nil {
+			return response, false, err
+		}
+	}
+	return response, false, nil
+}
+
+func (client *Client) wait(attempt int, lastErr error) time.Duration {
+	wait := client.backoff << (attempt - 1)
+
+	var retryAfter *retryAfterError
+	if errors.As(lastErr, &retryAfter) && retryAfter.after > wait {
+		wait = retryAfter.after
+	}
+	return wait
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func errorMessage(data []byte) string {
+	var body struct {
+		Error  string `json:"error"`
+		Detail string `json:"detail"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return strings.TrimSpace(string(data))
+	}
+	if body.Error != "" {
+		return body.Error
+	}
+	return body.Detail
+}
\ No newline at end of file
@@ -0,0 +1,218 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api/pkg/api"
+	"go-api/pkg/api/middleware"
+	"go-api/pkg/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestClient returns a client for the real router served by httptest.
+func newTestClient(t *testing.T) *Client {
+	server := httptest.NewServer(api.InitRouter())
+	t.Cleanup(server.Close)
+	return New(server.URL, WithHTTPClient(server.Client()), WithRetries(2, time.Millisecond))
+}
+
+func TestClientClasses(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	created, err := client.CreateClass(ctx, models.CreateClass{
+		Name:      "ClientYoga",
+		StartDate: time.Date(2023, 11, 6, 16, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2023, 11, 6, 17, 0, 0, 0, time.UTC),
+		Capacity:  10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "ClientYoga", created.Name)
+
+	var response Response
+	class, err := client.GetClass(ctx, created.ID, CaptureResponse(&response))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, created.ID, class.ID)
+	assert.NotEmpty(t, response.ETag)
+
+	classes, err := client.ListClasses(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Contains(t, classes, *class)
+
+	updated, err := client.UpdateClass(ctx, class.ID, models.UpdateClass{
+		Name:      "ClientPilates",
+		StartDate: class.StartDate,
+		EndDate:   class.EndDate,
+		Capacity:  12,
+	}, IfMatch(response.ETag))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 2, updated.Version)
+
+	// The ETag read before the update is stale
+	err = client.DeleteClass(ctx, class.ID, IfMatch(response.ETag))
+	var preconditionFailed *PreconditionFailedError
+	assert.True(t, errors.As(err, &preconditionFailed))
+
+	assert.NoError(t, client.DeleteClass(ctx, class.ID))
+}
+
+func TestClientTypedErrors(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	_, err := client.GetBooking(ctx, 999)
+	var notFound *NotFoundError
+	if assert.True(t, errors.As(err, &notFound)) {
+		assert.Equal(t, "Booking not found", notFound.Message)
+	}
+
+	_, err = client.CreateBooking(ctx, models.CreateBooking{Name: "Not valid!", ClassId: 1})
+	var badRequest *BadRequestError
+	assert.True(t, errors.As(err, &badRequest))
+
+	// Every typed error is also an *APIError
+	var apiErr *APIError
+	if assert.True(t, errors.As(err, &apiErr)) {
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	}
+}
+
+func TestClientBookingsWithIdempotencyKey(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	newBooking := models.CreateBooking{Name: "ClientDiego", ClassId: 2, Date: time.Date(2023, 10, 8, 20, 0, 0, 0, time.UTC)}
+
+	first, err := client.CreateBooking(ctx, newBooking, IdempotencyKey("client-test-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := client.CreateBooking(ctx, newBooking, IdempotencyKey("client-test-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, first.ID, second.ID)
+
+	// The same key with another body is a conflict of intent
+	newBooking.Name = "ClientMartin"
+	_, err = client.CreateBooking(ctx, newBooking, IdempotencyKey("client-test-1"))
+	var apiErr *APIError
+	if assert.True(t, errors.As(err, &apiErr)) {
+		assert.Equal(t, http.StatusUnprocessableEntity, apiErr.StatusCode)
+	}
+}
+
+func TestClientExportBookings(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	var export bytes.Buffer
+	if err := client.ExportBookings(ctx, "csv", &export, Query("class_id", "1")); err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, strings.HasPrefix(export.String(), "booking_id,name,class_id,class_name"))
+	assert.Contains(t, export.String(), ",1,Yoga,")
+
+	var roster bytes.Buffer
+	err := client.GetClassRoster(ctx, 999, "csv", &roster)
+	var notFound *NotFoundError
+	assert.True(t, errors.As(err, &notFound))
+}
+
+func TestClientMembersAndSchedule(t *testing.T) {
+	// Members are for the front desk
+	config := api.DefaultConfig()
+	config.APIKeys = map[string]middleware.Principal{"desk-key": {Name: "front desk", Role: "staff"}}
+	server := httptest.NewServer(api.NewRouter(config))
+	t.Cleanup(server.Close)
+	client := New(server.URL, WithHTTPClient(server.Client()), WithAPIKey("desk-key"))
+	ctx := context.Background()
+
+	member, err := client.CreateMember(ctx, models.CreateMember{Name: "ClientMember"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.CreateMember(ctx, models.CreateMember{Name: "ClientMember"})
+	var conflict *ConflictError
+	assert.True(t, errors.As(err, &conflict))
+
+	fetched, err := client.GetMember(ctx, member.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, *member, *fetched)
+
+	var schedule bytes.Buffer
+	if err := client.GetStudioSchedule(ctx, 1, &schedule); err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, strings.HasPrefix(schedule.String(), "BEGIN:VCALENDAR"))
+}
+
+func TestClientRetriesIdempotentCalls(t *testing.T) {
+	calls := 0
+	router := gin.Default()
+	router.GET("/api/studios", func(c *gin.Context) {
+		calls++
+		if calls < 3 {
+			c.IndentedJSON(http.StatusServiceUnavailable, gin.H{"error": "Unavailable"})
+			return
+		}
+		c.IndentedJSON(http.StatusOK, []models.Studio{{ID: 1, Name: "Centro", TimeZone: "America/Montevideo"}})
+	})
+	router.POST("/api/studios", func(c *gin.Context) {
+		calls++
+		c.IndentedJSON(http.StatusServiceUnavailable, gin.H{"error": "Unavailable"})
+	})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	client := New(server.URL, WithRetries(3, time.Millisecond))
+
+	studios, err := client.ListStudios(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 3, calls)
+	assert.Len(t, studios, 1)
+
+	// POST without an Idempotency-Key is never retried
+	calls = 0
+	_, err = client.CreateStudio(context.Background(), models.CreateStudio{Name: "Lisboa", TimeZone: "Europe/Lisbon"})
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestClientHonoursContext(t *testing.T) {
+	router := gin.Default()
+	router.GET("/api/classes", func(c *gin.Context) {
+		c.IndentedJSON(http.StatusServiceUnavailable, gin.H{"error": "Unavailable"})
+	})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	client := New(server.URL, WithRetries(5, time.Hour))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.ListClasses(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
\ No newline at end of file
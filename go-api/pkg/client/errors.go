@@ -0,0 +1,51 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// APIError is returned for every non-2xx response without a more specific type.
+type APIError struct {
+	StatusCode int
+	Message    string
+	// Body is the raw response body.
+	Body []byte
+}
+
+func (err *APIError) Error() string {
+	return fmt.Sprintf("go-api: %d %s: %s", err.StatusCode, http.StatusText(err.StatusCode), err.Message)
+}
+
+// BadRequestError is returned for 400 responses, e.g. validation failures.
+type BadRequestError struct{ APIError }
+
+// NotFoundError is returned for 404 responses.
+type NotFoundError struct{ APIError }
+
+// ConflictError is returned for 409 responses.
+type ConflictError struct{ APIError }
+
+// PreconditionFailedError is returned for 412 responses, when If-Match no longer matches.
+type PreconditionFailedError struct{ APIError }
+
+func (err *BadRequestError) Unwrap() error         { return &err.APIError }
+func (err *NotFoundError) Unwrap() error           { return &err.APIError }
+func (err *ConflictError) Unwrap() error           { return &err.APIError }
+func (err *PreconditionFailedError) Unwrap() error { return &err.APIError }
+
+func newError(status int, message string, body []byte) error {
+	apiErr := APIError{StatusCode: status, Message: message, Body: body}
+
+	switch status {
+	case http.StatusBadRequest:
+		return &BadRequestError{apiErr}
+	case http.StatusNotFound:
+		return &NotFoundError{apiErr}
+	case http.StatusConflict:
+		return &ConflictError{apiErr}
+	case http.StatusPreconditionFailed:
+		return &PreconditionFailedError{apiErr}
+	}
+	return &apiErr
+}
\ No newline at end of file
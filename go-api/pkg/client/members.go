@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"go-api/pkg/models"
+)
+
+/**
+ * @brief ListMembers returns all members.
+ */
+func (client *Client) ListMembers(ctx context.Context, options ...RequestOption) ([]models.Member, error) {
+	var members []models.Member
+	_, err := client.do(ctx, http.MethodGet, "/members", nil, &members, options)
+	return members, err
+}
+
+/**
+ * @brief GetMember returns a member by its ID, or a *NotFoundError.
+ */
+func (client *Client) GetMember(ctx context.Context, id int, options ...RequestOption) (*models.Member, error) {
+	var member models.Member
+	if _, err := client.do(ctx, http.MethodGet, "/members/"+strconv.Itoa(id), nil, &member, options); err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+/**
+ * @brief GetMemberAttendance returns the bookings of a member with their attendance and no-show counts.
+ */
+func (client *Client) GetMemberAttendance(ctx context.Context, id int, options ...RequestOption) (*models.Attendance, error) {
+	var attendance models.Attendance
+	if _, err := client.do(ctx, http.MethodGet, "/members/"+strconv.Itoa(id)+"/attendance", nil, &attendance, options); err != nil {
+		return nil, err
+	}
+	return &attendance, nil
+}
+
+/**
+ * @brief CreateMember creates a member, or returns a *ConflictError when the name is taken.
+ */
+func (client *Client) CreateMember(ctx context.Context, newMember models.CreateMember, options ...RequestOption) (*models.Member, error) {
+	var member models.Member
+	if _, err := client.do(ctx, http.MethodPost, "/members", newMember, &member, options); err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+/**
+ * @brief CreateMemberFeed issues a new private calendar feed URL for a member, revoking the previous one.
+ */
+func (client *Client) CreateMemberFeed(ctx context.Context, id int, options ...RequestOption) (*models.MemberFeed, error) {
+	var feed models.MemberFeed
+	if _, err := client.do(ctx, http.MethodPost, "/members/"+strconv.Itoa(id)+"/feed", nil, &feed, options); err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+/**
+ * @brief GetMemberCredits returns the class credit balance, active membership and credit ledger of a member.
+ */
+func (client *Client) GetMemberCredits(ctx context.Context, id int, options ...RequestOption) (*models.Credits, error) {
+	var credits models.Credits
+	if _, err := client.do(ctx, http.MethodGet, "/members/"+strconv.Itoa(id)+"/credits", nil, &credits, options); err != nil {
+		return nil, err
+	}
+	return &credits, nil
+}
+
+/**
+ * @brief PurchasePlan gives a member a class-credit pack or a membership and returns their new credits.
+ */
+func (client *Client) PurchasePlan(ctx context.Context, id int, planId int, options ...RequestOption) (*models.Credits, error) {
+	var credits models.Credits
+	if _, err := client.do(ctx, http.MethodPost, "/members/"+strconv.Itoa(id)+"/plans", models.PurchasePlan{PlanId: planId}, &credits, options); err != nil {
+		return nil, err
+	}
+	return &credits, nil
+}
\ No newline at end of file
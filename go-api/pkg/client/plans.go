@@ -0,0 +1,28 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"go-api/pkg/models"
+)
+
+/**
+ * @brief ListPlans returns the membership plans and class-credit packs.
+ */
+func (client *Client) ListPlans(ctx context.Context, options ...RequestOption) ([]models.Plan, error) {
+	var plans []models.Plan
+	_, err := client.do(ctx, http.MethodGet, "/plans", nil, &plans, options)
+	return plans, err
+}
+
+/**
+ * @brief CreatePlan creates a class-credit pack or a membership plan.
+ */
+func (client *Client) CreatePlan(ctx context.Context, newPlan models.CreatePlan, options ...RequestOption) (*models.Plan, error) {
+	var plan models.Plan
+	if _, err := client.do(ctx, http.MethodPost, "/plans", newPlan, &plan, options); err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
\ No newline at end of file
@@ -0,0 +1,80 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"go-api/pkg/models"
+)
+
+/**
+ * @brief ListStudios returns all studios.
+ */
+func (client *Client) ListStudios(ctx context.Context, options ...RequestOption) ([]models.Studio, error) {
+	var studios []models.Studio
+	_, err := client.do(ctx, http.MethodGet, "/studios", nil, &studios, options)
+	return studios, err
+}
+
+/**
+ * @brief GetStudio returns a studio by its ID, or a *NotFoundError.
+ */
+func (client *Client) GetStudio(ctx context.Context, id int, options ...RequestOption) (*models.Studio, error) {
+	var studio models.Studio
+	if _, err := client.do(ctx, http.MethodGet, "/studios/"+strconv.Itoa(id), nil, &studio, options); err != nil {
+		return nil, err
+	}
+	return &studio, nil
+}
+
+/**
+ * @brief CreateStudio creates a studio with its IANA time zone.
+ */
+func (client *Client) CreateStudio(ctx context.Context, newStudio models.CreateStudio, options ...RequestOption) (*models.Studio, error) {
+	var studio models.Studio
+	if _, err := client.do(ctx, http.MethodPost, "/studios", newStudio, &studio, options); err != nil {
+		return nil, err
+	}
+	return &studio, nil
+}
+
+/**
+ * @brief GetStudioSchedule writes the iCalendar feed of a studio's classes to w.
+ */
+func (client *Client) GetStudioSchedule(ctx context.Context, id int, w io.Writer, options ...RequestOption) error {
+	_, err := client.do(ctx, http.MethodGet, "/studios/"+strconv.Itoa(id)+"/schedule.ics", nil, w, options)
+	return err
+}
+/**
+ * @brief ListEmailTemplates returns the notification templates of a studio, overridden or default.
+ */
+func (client *Client) ListEmailTemplates(ctx context.Context, id int, options ...RequestOption) ([]models.EmailTemplate, error) {
+	var templates []models.EmailTemplate
+	_, err := client.do(ctx, http.MethodGet, "/studios/"+strconv.Itoa(id)+"/email-templates", nil, &templates, options)
+	return templates, err
+}
+
+/**
+ * @brief SetEmailTemplate overrides a notification template of a studio. Requires an admin key.
+ */
+func (client *Client) SetEmailTemplate(ctx context.Context, id int, kind string, update models.UpdateEmailTemplate, options ...RequestOption) (*models.EmailTemplate, error) {
+	var template models.EmailTemplate
+	if _, err := client.do(ctx, http.MethodPut, "/studios/"+strconv.Itoa(id)+"/email-templates/"+url.PathEscape(kind), update, &template, options); err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+/**
+ * @brief ResetEmailTemplate reverts a notification template of a studio to the default. Requires an admin key.
+ */
+func (client *Client) ResetEmailTemplate(ctx context.Context, id int, kind string, options ...RequestOption) (*models.EmailTemplate, error) {
+	var template models.EmailTemplate
+	if _, err := client.do(ctx, http.MethodDelete, "/studios/"+strconv.Itoa(id)+"/email-templates/"+url.PathEscape(kind), nil, &template, options); err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
\ No newline at end of file
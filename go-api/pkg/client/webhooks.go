@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"go-api/pkg/models"
+)
+
+/**
+ * @brief ListWebhooks returns all webhooks, without their secrets. Requires an admin key.
+ */
+func (client *Client) ListWebhooks(ctx context.Context, options ...RequestOption) ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+	_, err := client.do(ctx, http.MethodGet, "/webhooks", nil, &webhooks, options)
+	return webhooks, err
+}
+
+/**
+ * @brief CreateWebhook subscribes a URL to events. The returned webhook holds the signing secret.
+ */
+func (client *Client) CreateWebhook(ctx context.Context, newWebhook models.CreateWebhook, options ...RequestOption) (*models.Webhook, error) {
+	var webhook models.Webhook
+	if _, err := client.do(ctx, http.MethodPost, "/webhooks", newWebhook, &webhook, options); err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+/**
+ * @brief DeleteWebhook deletes a webhook by its ID.
+ */
+func (client *Client) DeleteWebhook(ctx context.Context, id int, options ...RequestOption) error {
+	_, err := client.do(ctx, http.MethodDelete, "/webhooks/"+strconv.Itoa(id), nil, nil, options)
+	return err
+}
+
+/**
+ * @brief ListDeadLetters returns the webhook deliveries that failed every attempt.
+ */
+func (client *Client) ListDeadLetters(ctx context.Context, options ...RequestOption) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	_, err := client.do(ctx, http.MethodGet, "/webhooks/dead-letters", nil, &deliveries, options)
+	return deliveries, err
+}
+
+/**
+ * @brief RedeliverDeadLetter posts a dead letter again, or returns a *ConflictError while it is pending.
+ */
+func (client *Client) RedeliverDeadLetter(ctx context.Context, id int, options ...RequestOption) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	if _, err := client.do(ctx, http.MethodPost, "/webhooks/dead-letters/"+strconv.Itoa(id)+"/redeliver", nil, &delivery, options); err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
\ No newline at end of file
@@ -0,0 +1,148 @@
+package credits
+
+import (
+	"errors"
+	"time"
+	"go-api/pkg/models"
+	"go-api/pkg/mockDatabase"
+)
+
+// Reasons of credit ledger entries.
+const (
+	ReasonPurchase = "purchase"
+	ReasonBooking  = "booking"
+	ReasonRefund   = "refund"
+)
+
+var ErrNoCredits = errors.New("member has no class credits left")
+
+/**
+ * @brief Entitlement decides what covers a new booking made by a member.
+ *
+ * An active membership covers it; otherwise it costs one class credit.
+ * Bookings without a member (see models.Booking.MemberId), such as drop-ins,
+ * need nothing. Call it and Spend in the database.Transaction creating the booking.
+ *
+ * @param memberId int: The member making the booking, 0 for none.
+ * @param at time.Time: When the booking is made.
+ * @return string: models.EntitlementMembership, models.EntitlementCredit or "" for non-members.
+ * @return error: ErrNoCredits when the member has neither.
+ */
+func Entitlement(memberId int, at time.Time) (string, error) {
+	if memberId == 0 {
+		return "", nil
+	}
+	if ActiveMembership(memberId, at) != nil {
+		return models.EntitlementMembership, nil
+	}
+	if Balance(memberId) > 0 {
+		return models.EntitlementCredit, nil
+	}
+	return "", ErrNoCredits
+}
+
+/**
+ * @brief Spend takes the class credit of a booking whose Entitlement is credit from its member.
+ */
+func Spend(booking models.Booking, at time.Time) {
+	if booking.Entitlement == models.EntitlementCredit && booking.MemberId != 0 {
+		add(booking.MemberId, -1, ReasonBooking, 0, booking.ID, at)
+	}
+}
+
+/**
+ * @brief Refund gives back the class credit spent on a booking to the member it was taken from.
+ *
+ * Call it in the database.Transaction cancelling the booking, unless the
+ * cancellation policy forfeits the credit. A credit is refunded once.
+ */
+func Refund(booking models.Booking, at time.Time) {
+	spent := 0
+	memberId := 0
+	for _, entry := range database.CreditLedger {
+		if entry.BookingId != booking.ID {
+			continue
+		}
+		spent -= entry.Amount
+		memberId = entry.MemberId
+	}
+	if spent > 0 {
+		add(memberId, spent, ReasonRefund, 0, booking.ID, at)
+	}
+}
+
+/**
+ * @brief Purchase gives a plan to a member.
+ *
+ * A pack adds its credits to the ledger. A membership starts at the given
+ * time, or when the member's last membership ends if it is still running.
+ * Call it in a database.Transaction.
+ *
+ * @param memberId int: The buyer.
+ * @param plan models.Plan: The plan bought.
+ * @param at time.Time: When it was bought.
+ */
+func Purchase(memberId int, plan models.Plan, at time.Time) {
+	if plan.Kind == models.PlanPack {
+		add(memberId, plan.Credits, ReasonPurchase, plan.ID, 0, at)
+		return
+	}
+
+	start := at
+	for _, membership := range database.Memberships {
+		if membership.MemberId == memberId && membership.EndsAt.After(start) {
+			start = membership.EndsAt
+		}
+	}
+	database.Memberships = append(database.Memberships, database.CreateMembership(memberId, plan, start))
+}
+
+/**
+ * @brief Balance returns the class credits a member has left.
+ */
+func Balance(memberId int) int {
+	for i := len(database.CreditLedger) - 1; i >= 0; i-- {
+		if database.CreditLedger[i].MemberId == memberId {
+			return database.CreditLedger[i].Balance
+		}
+	}
+	return 0
+}
+
+/**
+ * @brief ActiveMembership returns the membership of a member running at the given time, if any.
+ */
+func ActiveMembership(memberId int, at time.Time) *models.Membership {
+	for _, membership := range database.Memberships {
+		if membership.MemberId == memberId && !at.Before(membership.StartsAt) && at.Before(membership.EndsAt) {
+			return &membership
+		}
+	}
+	return nil
+}
+
+/**
+ * @brief Ledger returns the credit balance of a member, their active membership and every credit movement, oldest first.
+ */
+func Ledger(memberId int, at time.Time) models.Credits {
+	credits := models.Credits{MemberId: memberId, Balance: Balance(memberId), Membership: ActiveMembership(memberId, at), Ledger: []models.CreditEntry{}}
+	for _, entry := range database.CreditLedger {
+		if entry.MemberId == memberId {
+			credits.Ledger = append(credits.Ledger, entry)
+		}
+	}
+	return credits
+}
+
+func add(memberId int, amount int, reason string, planId int, bookingId int, at time.Time) {
+	entry := database.CreateCreditEntry(models.CreditEntry{
+		MemberId:  memberId,
+		Amount:    amount,
+		Balance:   Balance(memberId) + amount,
+		Reason:    reason,
+		PlanId:    planId,
+		BookingId: bookingId,
+		At:        at,
+	})
+	database.CreditLedger = append(database.CreditLedger, entry)
+}
\ No newline at end of file
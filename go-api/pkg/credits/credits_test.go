@@ -0,0 +1,79 @@
+package credits
+
+import (
+	"testing"
+	"time"
+	"go-api/pkg/models"
+	"go-api/pkg/mockDatabase"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntitlement(t *testing.T) {
+	at := time.Date(2024, 3, 1, 18, 0, 0, 0, time.UTC)
+
+	member := database.CreateMember(models.CreateMember{Name: "Newcomer"})
+	database.Members = append(database.Members, member)
+
+	// Non-members need nothing, members need credits or a membership
+	entitlement, err := Entitlement(0, at)
+	assert.NoError(t, err)
+	assert.Equal(t, "", entitlement)
+	_, err = Entitlement(member.ID, at)
+	assert.ErrorIs(t, err, ErrNoCredits)
+
+	// The seeded members start with a pack
+	entitlement, err = Entitlement(1, at)
+	assert.NoError(t, err)
+	assert.Equal(t, models.EntitlementCredit, entitlement)
+
+	Purchase(member.ID, models.Plan{ID: 1, Kind: models.PlanPack, Credits: 2}, at)
+	entitlement, err = Entitlement(member.ID, at)
+	assert.NoError(t, err)
+	assert.Equal(t, models.EntitlementCredit, entitlement)
+
+	Purchase(member.ID, models.Plan{ID: 2, Name: "Monthly", Kind: models.PlanMembership, Days: 30}, at)
+	entitlement, _ = Entitlement(member.ID, at.AddDate(0, 0, 29))
+	assert.Equal(t, models.EntitlementMembership, entitlement)
+	entitlement, _ = Entitlement(member.ID, at.AddDate(0, 0, 30))
+	assert.Equal(t, models.EntitlementCredit, entitlement)
+}
+
+func TestPurchaseExtendsMembership(t *testing.T) {
+	at := time.Date(2024, 3, 1, 18, 0, 0, 0, time.UTC)
+	plan := models.Plan{ID: 2, Name: "Monthly", Kind: models.PlanMembership, Days: 30}
+
+	Purchase(2, plan, at)
+	Purchase(2, plan, at.AddDate(0, 0, 10))
+
+	assert.NotNil(t, ActiveMembership(2, at.AddDate(0, 0, 59)))
+	assert.Nil(t, ActiveMembership(2, at.AddDate(0, 0, 60)))
+}
+
+func TestSpendAndRefund(t *testing.T) {
+	at := time.Date(2024, 3, 1, 18, 0, 0, 0, time.UTC)
+	member := database.CreateMember(models.CreateMember{Name: "Spender"})
+	database.Members = append(database.Members, member)
+	Purchase(member.ID, models.Plan{ID: 1, Kind: models.PlanPack, Credits: 10}, at)
+
+	booking := models.Booking{ID: 500, Name: "Spender", MemberId: member.ID, Entitlement: models.EntitlementCredit}
+	Spend(booking, at)
+	assert.Equal(t, 9, Balance(member.ID))
+
+	// A credit is refunded once
+	Refund(booking, at)
+	Refund(booking, at)
+	assert.Equal(t, 10, Balance(member.ID))
+
+	ledger := Ledger(member.ID, at)
+	if assert.Len(t, ledger.Ledger, 3) {
+		assert.Equal(t, ReasonBooking, ledger.Ledger[1].Reason)
+		assert.Equal(t, -1, ledger.Ledger[1].Amount)
+		assert.Equal(t, 500, ledger.Ledger[2].BookingId)
+		assert.Equal(t, 10, ledger.Ledger[2].Balance)
+	}
+
+	// Membership bookings spend nothing
+	Spend(models.Booking{ID: 501, Name: "Spender", MemberId: member.ID, Entitlement: models.EntitlementMembership}, at)
+	assert.Equal(t, 10, Balance(member.ID))
+	assert.Len(t, Ledger(member.ID, at).Ledger, 3)
+}
\ No newline at end of file
@@ -0,0 +1,180 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+	"go-api/pkg/models"
+	"go-api/pkg/mockDatabase"
+)
+
+// Event types.
+const (
+	BookingCreated = "booking.created"
+	BookingUpdated = "booking.updated"
+	// BookingDeleted is recorded when a booking is soft-deleted with its class, and after BookingCancelled by DELETE /bookings/:id for older subscribers.
+	BookingDeleted = "booking.deleted"
+	// BookingCancelled is recorded when a booking is cancelled under the cancellation policy.
+	BookingCancelled = "booking.cancelled"
+	ClassCreated   = "class.created"
+	ClassUpdated   = "class.updated"
+	ClassDeleted   = "class.deleted"
+	// ClassRestored is recorded when a soft-deleted class is brought back with its bookings.
+	ClassRestored = "class.restored"
+	// BookingRestored is recorded for each booking brought back with its class or by POST /bookings/:id/restore.
+	BookingRestored = "booking.restored"
+	// BookingPromoted is recorded when a waitlisted booking gets a seat.
+	BookingPromoted = "booking.promoted"
+	// Status changes of a booking, see models.BookingTransitions.
+	BookingConfirmed = "booking.confirmed"
+	BookingAttended  = "booking.attended"
+	BookingNoShow    = "booking.no_show"
+)
+
+// Handler processes one event. Returning an error, or panicking, retries the event on the next dispatch.
+type Handler func(event models.DomainEvent) error
+
+// Bus delivers the events of the database Outbox to in-process subscribers.
+//
+// Delivery is at-least-once: a handler sees an event again until it returns nil,
+// so handlers must tolerate duplicates. Events of the same aggregate (e.g.
+// booking 7) reach a handler in the order they were committed; a failing event
+// holds back the later events of its aggregate, but not those of other aggregates.
+type Bus struct {
+	mu            sync.Mutex
+	subscriptions []*subscription
+	trimmed       int64
+}
+
+type subscription struct {
+	name    string
+	handler Handler
+	// offset is the ID of the last event processed along with every event before it.
+	offset int64
+	// done holds the IDs above offset already processed.
+	done map[int64]bool
+}
+
+/**
+ * @brief NewBus returns a bus without subscribers.
+ */
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Default is the bus used by Subscribe, run by the server.
+var Default = NewBus()
+
+/**
+ * @brief Subscribe registers a handler on the Default bus.
+ */
+func Subscribe(name string, handler Handler) {
+	Default.Subscribe(name, handler)
+}
+
+/**
+ * @brief Subscribe registers a handler, replacing the handler with the same name.
+ *
+ * A new subscriber starts with the events still in the Outbox.
+ *
+ * @param name string: Identifies the subscriber in logs.
+ * @param handler Handler: Called for each event.
+ */
+func (bus *Bus) Subscribe(name string, handler Handler) {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	for _, existing := range bus.subscriptions {
+		if existing.name == name {
+			existing.handler = handler
+			return
+		}
+	}
+	bus.subscriptions = append(bus.subscriptions, &subscription{name: name, handler: handler, offset: bus.trimmed, done: map[int64]bool{}})
+}
+
+/**
+ * @brief Dispatch hands the pending Outbox events to every subscriber once.
+ *
+ * Events processed by every subscriber are removed from the Outbox.
+ *
+ * @return int: The number of events processed successfully, over all subscribers.
+ */
+func (bus *Bus) Dispatch() int {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	if len(bus.subscriptions) == 0 {
+		return 0
+	}
+
+	processed := 0
+	low := int64(-1)
+	for _, sub := range bus.subscriptions {
+		pending := database.PendingEvents(sub.offset)
+		blocked := map[string]bool{}
+		for _, event := range pending {
+			if sub.done[event.ID] {
+				continue
+			}
+			aggregate := event.AggregateType + "/" + strconv.Itoa(event.AggregateID)
+			if blocked[aggregate] {
+				continue
+			}
+			if err := call(sub.handler, event); err != nil {
+				log.Printf("events: %s failed on event %d (%s): %v", sub.name, event.ID, event.Type, err)
+				blocked[aggregate] = true
+				continue
+			}
+			sub.done[event.ID] = true
+			processed++
+		}
+
+		for _, event := range pending {
+			if !sub.done[event.ID] {
+				break
+			}
+			delete(sub.done, event.ID)
+			sub.offset = event.ID
+		}
+		if low < 0 || sub.offset < low {
+			low = sub.offset
+		}
+	}
+
+	if low > bus.trimmed {
+		database.TrimOutbox(low)
+		bus.trimmed = low
+	}
+	return processed
+}
+
+/**
+ * @brief Run dispatches events as transactions commit, and every interval to retry failures, until ctx is done.
+ */
+func (bus *Bus) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-database.Committed():
+		case <-ticker.C:
+		}
+		bus.Dispatch()
+	}
+}
+
+func call(handler Handler, event models.DomainEvent) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+	return handler(event)
+}
\ No newline at end of file
@@ -0,0 +1,90 @@
+package events
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"go-api/pkg/models"
+	"go-api/pkg/mockDatabase"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionWritesOutbox(t *testing.T) {
+	before := len(database.Outbox)
+
+	// A failed transaction records nothing
+	err := database.Transaction(func(tx *database.Tx) error {
+		tx.Record(BookingCreated, 1, models.Booking{ID: 1})
+		return errors.New("rolled back")
+	})
+	assert.Error(t, err)
+	assert.Len(t, database.Outbox, before)
+
+	err = database.Transaction(func(tx *database.Tx) error {
+		tx.Record(ClassCreated, 7, models.Class{ID: 7})
+		return tx.Record(ClassUpdated, 7, models.Class{ID: 7, Version: 2})
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, database.Outbox, before+2) {
+		created, updated := database.Outbox[before], database.Outbox[before+1]
+		assert.Equal(t, "class", created.AggregateType)
+		assert.Equal(t, 7, created.AggregateID)
+		assert.Equal(t, created.ID+1, updated.ID)
+	}
+}
+
+func TestBusDeliversAtLeastOnceInAggregateOrder(t *testing.T) {
+	database.Outbox = []models.DomainEvent{}
+	bus := NewBus()
+
+	var seen []string
+	failBooking1 := true
+	bus.Subscribe("test", func(event models.DomainEvent) error {
+		if failBooking1 && event.AggregateID == 1 {
+			failBooking1 = false
+			return errors.New("temporary failure")
+		}
+		seen = append(seen, event.Type+"/"+strconv.Itoa(event.AggregateID))
+		return nil
+	})
+
+	database.Transaction(func(tx *database.Tx) error {
+		tx.Record(BookingCreated, 1, models.Booking{ID: 1})
+		tx.Record(BookingCreated, 2, models.Booking{ID: 2})
+		return tx.Record(BookingUpdated, 1, models.Booking{ID: 1, Version: 2})
+	})
+
+	// The failure of booking 1 holds back its update, but not booking 2
+	assert.Equal(t, 1, bus.Dispatch())
+	assert.Equal(t, []string{"booking.created/2"}, seen)
+
+	// The failed event is retried, then the held back one follows in order
+	assert.Equal(t, 2, bus.Dispatch())
+	assert.Equal(t, []string{"booking.created/2", "booking.created/1", "booking.updated/1"}, seen)
+
+	// Processed events leave the outbox
+	assert.Equal(t, 0, bus.Dispatch())
+	assert.Empty(t, database.Outbox)
+}
+
+func TestBusRecoversFromPanics(t *testing.T) {
+	database.Outbox = []models.DomainEvent{}
+	bus := NewBus()
+
+	calls := 0
+	bus.Subscribe("panicky", func(event models.DomainEvent) error {
+		calls++
+		if calls == 1 {
+			panic("boom")
+		}
+		return nil
+	})
+
+	database.Transaction(func(tx *database.Tx) error {
+		return tx.Record(ClassDeleted, 3, models.Class{ID: 3})
+	})
+
+	assert.Equal(t, 0, bus.Dispatch())
+	assert.Equal(t, 1, bus.Dispatch())
+	assert.Equal(t, 2, calls)
+}
\ No newline at end of file
@@ -0,0 +1,115 @@
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MediaType is the Content-Type of iCalendar documents.
+const MediaType = "text/calendar"
+
+// Event statuses.
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+	StatusTentative = "TENTATIVE"
+)
+
+const dateTimeLayout = "20060102T150405Z"
+
+// Calendar is a VCALENDAR object holding events.
+type Calendar struct {
+	// ProductID identifies the feed producer, e.g. "-//go-api//schedule//EN".
+	ProductID string
+	Name      string
+	Events    []Event
+}
+
+// Event is a VEVENT. UID must stay the same across updates, which are told apart by Sequence.
+type Event struct {
+	UID         string
+	Sequence    int
+	Stamp       time.Time
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Location    string
+	Description string
+	// Status is StatusConfirmed, StatusTentative or StatusCancelled, omitted when empty.
+	Status string
+}
+
+/**
+ * @brief Write encodes the calendar as RFC 5545 text.
+ *
+ * Dates are written in UTC, text values are escaped and long lines folded at 75 octets.
+ *
+ * @param w io.Writer: Where to write.
+ * @return error: The first write error.
+ */
+func (calendar Calendar) Write(w io.Writer) error {
+	out := bufio.NewWriter(w)
+	line := func(name string, value string) {
+		writeFolded(out, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", calendar.ProductID)
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	if calendar.Name != "" {
+		line("X-WR-CALNAME", Escape(calendar.Name))
+	}
+
+	for _, event := range calendar.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", event.UID)
+		line("SEQUENCE", strconv.Itoa(event.Sequence))
+		line("DTSTAMP", event.Stamp.UTC().Format(dateTimeLayout))
+		line("DTSTART", event.Start.UTC().Format(dateTimeLayout))
+		line("DTEND", event.End.UTC().Format(dateTimeLayout))
+		line("SUMMARY", Escape(event.Summary))
+		if event.Location != "" {
+			line("LOCATION", Escape(event.Location))
+		}
+		if event.Description != "" {
+			line("DESCRIPTION", Escape(event.Description))
+		}
+		if event.Status != "" {
+			line("STATUS", event.Status)
+		}
+		line("END", "VEVENT")
+	}
+
+	line("END", "VCALENDAR")
+	return out.Flush()
+}
+
+/**
+ * @brief Escape escapes a TEXT property value.
+ */
+func Escape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(value)
+}
+
+// writeFolded writes a content line, folding it so that no line exceeds 75
+// octets and no UTF-8 sequence is split.
+func writeFolded(w *bufio.Writer, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts towards the limit.
+		limit = 74
+	}
+	w.WriteString(line + "\r\n")
+}
\ No newline at end of file
@@ -0,0 +1,246 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"go-api/pkg/timezone"
+)
+
+// Property is a content line, e.g. DTSTART;TZID=Europe/Madrid:20231016T180000.
+type Property struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// Component is a BEGIN/END block such as VCALENDAR or VEVENT.
+type Component struct {
+	Name       string
+	Properties []Property
+	Components []*Component
+}
+
+/**
+ * @brief Parse reads an RFC 5545 document.
+ *
+ * Folded lines are joined; property and parameter names are upper-cased.
+ *
+ * @param r io.Reader: The document.
+ * @return *Component: The top-level VCALENDAR.
+ */
+func Parse(r io.Reader) (*Component, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var stack []*Component
+	var root *Component
+
+	for number, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		property, err := parseLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", number+1, err)
+		}
+
+		switch property.Name {
+		case "BEGIN":
+			component := &Component{Name: strings.ToUpper(property.Value)}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Components = append(parent.Components, component)
+			} else if root == nil {
+				root = component
+			}
+			stack = append(stack, component)
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].Name != strings.ToUpper(property.Value) {
+				return nil, fmt.Errorf("line %d: unexpected END:%s", number+1, property.Value)
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("line %d: property outside of a component", number+1)
+			}
+			component := stack[len(stack)-1]
+			component.Properties = append(component.Properties, property)
+		}
+	}
+
+	if root == nil || root.Name != "VCALENDAR" {
+		return nil, fmt.Errorf("not an iCalendar document")
+	}
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("missing END:%s", stack[len(stack)-1].Name)
+	}
+	return root, nil
+}
+
+/**
+ * @brief Get returns the first property with the given name, or nil.
+ */
+func (component *Component) Get(name string) *Property {
+	for i := range component.Properties {
+		if component.Properties[i].Name == name {
+			return &component.Properties[i]
+		}
+	}
+	return nil
+}
+
+/**
+ * @brief All returns every property with the given name.
+ */
+func (component *Component) All(name string) []Property {
+	var properties []Property
+	for _, property := range component.Properties {
+		if property.Name == name {
+			properties = append(properties, property)
+		}
+	}
+	return properties
+}
+
+/**
+ * @brief Unescape reverses Escape on a TEXT value.
+ */
+func Unescape(value string) string {
+	return strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n").Replace(value)
+}
+
+/**
+ * @brief Times parses the DATE-TIME values of a property such as DTSTART or EXDATE.
+ *
+ * Values ending in Z are UTC; values with a TZID parameter are wall-clock times
+ * in that IANA zone; other values are floating and interpreted in floating.
+ * DATE values (all-day events) are not supported.
+ *
+ * @param property Property: The property, possibly holding comma-separated values.
+ * @param floating *time.Location: The zone of floating times.
+ * @return []time.Time: The instants, in UTC.
+ */
+func Times(property Property, floating *time.Location) ([]time.Time, error) {
+	if property.Params["VALUE"] == "DATE" {
+		return nil, fmt.Errorf("%s: all-day dates are not supported", property.Name)
+	}
+
+	loc, err := property.Location(floating)
+	if err != nil {
+		return nil, err
+	}
+
+	var instants []time.Time
+	for _, value := range strings.Split(property.Value, ",") {
+		instant, err := parseDateTime(value, loc)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", property.Name, err)
+		}
+		instants = append(instants, instant)
+	}
+	return instants, nil
+}
+
+/**
+ * @brief Location returns the zone the DATE-TIME values of the property are expressed in.
+ *
+ * @param floating *time.Location: The zone of floating times.
+ * @return *time.Location: UTC for values ending in Z, the TZID zone, or floating.
+ */
+func (property Property) Location(floating *time.Location) (*time.Location, error) {
+	if strings.HasSuffix(property.Value, "Z") {
+		return time.UTC, nil
+	}
+	if tzid := property.Params["TZID"]; tzid != "" {
+		loc, err := time.LoadLocation(tzid)
+		if err != nil {
+			return nil, fmt.Errorf("%s: unknown TZID %q", property.Name, tzid)
+		}
+		return loc, nil
+	}
+	return floating, nil
+}
+
+func parseDateTime(value string, loc *time.Location) (time.Time, error) {
+	if strings.HasSuffix(value, "Z") {
+		return time.Parse(dateTimeLayout, value)
+	}
+	wall, err := time.Parse("20060102T150405", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return timezone.FromWallClock(wall, loc)
+}
+
+var durationPattern = regexp.MustCompile(`^P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+/**
+ * @brief Duration parses a positive DURATION value such as PT1H30M.
+ */
+func Duration(value string) (time.Duration, error) {
+	match := durationPattern.FindStringSubmatch(strings.TrimPrefix(value, "+"))
+	if match == nil || value == "P" || value == "PT" {
+		return 0, fmt.Errorf("invalid DURATION %q", value)
+	}
+
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var duration time.Duration
+	for i, unit := range units {
+		if match[i+1] != "" {
+			n, _ := strconv.Atoi(match[i+1])
+			duration += time.Duration(n) * unit
+		}
+	}
+	return duration, nil
+}
+
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+func parseLine(line string) (Property, error) {
+	colon := -1
+	quoted := false
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		}
+		if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return Property{}, fmt.Errorf("missing ':' in %q", line)
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	property := Property{Name: strings.ToUpper(parts[0]), Params: map[string]string{}, Value: line[colon+1:]}
+	for _, param := range parts[1:] {
+		name, value, ok := strings.Cut(param, "=")
+		if !ok {
+			return Property{}, fmt.Errorf("invalid parameter %q", param)
+		}
+		property.Params[strings.ToUpper(name)] = strings.Trim(value, `"`)
+	}
+	return property, nil
+}
\ No newline at end of file
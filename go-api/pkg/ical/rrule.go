@@ -0,0 +1,150 @@
+package ical
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"go-api/pkg/timezone"
+)
+
+// Rule is the supported subset of an RRULE: DAILY and WEEKLY frequencies with
+// INTERVAL, COUNT, UNTIL and, for WEEKLY, BYDAY and WKST.
+type Rule struct {
+	Frequency string
+	Interval  int
+	Count     int
+	Until     time.Time
+	ByDay     []time.Weekday
+	WeekStart time.Weekday
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+/**
+ * @brief ParseRule parses an RRULE value, rejecting the parts it does not support.
+ *
+ * @param value string: e.g. "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10".
+ * @return Rule: The parsed rule.
+ */
+func ParseRule(value string) (Rule, error) {
+	rule := Rule{Interval: 1, WeekStart: time.Monday}
+
+	for _, part := range strings.Split(value, ";") {
+		name, argument, ok := strings.Cut(part, "=")
+		if !ok {
+			return rule, fmt.Errorf("invalid RRULE part %q", part)
+		}
+
+		var err error
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			rule.Frequency = strings.ToUpper(argument)
+			if rule.Frequency != "DAILY" && rule.Frequency != "WEEKLY" {
+				return rule, fmt.Errorf("unsupported RRULE frequency %s", rule.Frequency)
+			}
+		case "INTERVAL":
+			if rule.Interval, err = strconv.Atoi(argument); err != nil || rule.Interval < 1 {
+				return rule, fmt.Errorf("invalid RRULE INTERVAL %q", argument)
+			}
+		case "COUNT":
+			if rule.Count, err = strconv.Atoi(argument); err != nil || rule.Count < 1 {
+				return rule, fmt.Errorf("invalid RRULE COUNT %q", argument)
+			}
+		case "UNTIL":
+			if rule.Until, err = time.Parse(dateTimeLayout, argument); err != nil {
+				if rule.Until, err = time.Parse("20060102", argument); err != nil {
+					return rule, fmt.Errorf("invalid RRULE UNTIL %q", argument)
+				}
+				// A date includes the whole day.
+				rule.Until = rule.Until.Add(24*time.Hour - time.Second)
+			}
+		case "BYDAY":
+			for _, day := range strings.Split(argument, ",") {
+				weekday, ok := weekdays[strings.ToUpper(day)]
+				if !ok {
+					return rule, fmt.Errorf("unsupported RRULE BYDAY %q", day)
+				}
+				rule.ByDay = append(rule.ByDay, weekday)
+			}
+		case "WKST":
+			weekday, ok := weekdays[strings.ToUpper(argument)]
+			if !ok {
+				return rule, fmt.Errorf("invalid RRULE WKST %q", argument)
+			}
+			rule.WeekStart = weekday
+		default:
+			return rule, fmt.Errorf("unsupported RRULE part %s", strings.ToUpper(name))
+		}
+	}
+
+	if rule.Frequency == "" {
+		return rule, fmt.Errorf("RRULE without FREQ")
+	}
+	if len(rule.ByDay) > 0 && rule.Frequency != "WEEKLY" {
+		return rule, fmt.Errorf("unsupported RRULE BYDAY with FREQ=%s", rule.Frequency)
+	}
+	return rule, nil
+}
+
+/**
+ * @brief Expand lists the start instants of the occurrences of the rule.
+ *
+ * Occurrences keep the wall-clock time of the first one in loc, so they stay
+ * at the same local time across DST changes; those falling in a DST gap are
+ * skipped. At most limit occurrences are returned.
+ *
+ * @param first time.Time: The start of the first occurrence (DTSTART).
+ * @param loc *time.Location: The zone DTSTART was given in.
+ * @param limit int: The maximum number of occurrences.
+ * @return []time.Time: The occurrence starts, in UTC.
+ * @return bool: true when the rule had more occurrences than limit.
+ */
+func (rule Rule) Expand(first time.Time, loc *time.Location, limit int) ([]time.Time, bool) {
+	wall := first.In(loc)
+	days := []int{0}
+
+	step := rule.Interval
+	if rule.Frequency == "WEEKLY" {
+		step *= 7
+		if len(rule.ByDay) > 0 {
+			// Offsets of BYDAY from the start of the week holding DTSTART.
+			weekStart := (int(wall.Weekday()) - int(rule.WeekStart) + 7) % 7
+			days = nil
+			for offset := 0; offset < 7; offset++ {
+				weekday := time.Weekday((int(rule.WeekStart) + offset) % 7)
+				for _, day := range rule.ByDay {
+					if day == weekday {
+						days = append(days, offset-weekStart)
+					}
+				}
+			}
+		}
+	}
+
+	var instants []time.Time
+	for period := 0; ; period++ {
+		for _, day := range days {
+			if period*step+day < 0 {
+				continue
+			}
+			instant, err := timezone.FromWallClock(wall.AddDate(0, 0, period*step+day), loc)
+			if err != nil {
+				continue
+			}
+			if !rule.Until.IsZero() && instant.After(rule.Until) {
+				return instants, false
+			}
+			if len(instants) == limit {
+				return instants, true
+			}
+			instants = append(instants, instant)
+			if rule.Count > 0 && len(instants) == rule.Count {
+				return instants, false
+			}
+		}
+	}
+}
\ No newline at end of file
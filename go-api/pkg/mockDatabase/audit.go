@@ -0,0 +1,31 @@
+package database
+
+import (
+	"go-api/pkg/models"
+)
+
+// auditLog is append-only: entries are only added by Transaction and never changed or removed.
+var auditLog = []models.AuditEntry{}
+var auditIDCounter int64 = 0
+
+// appendAudit numbers an entry and chains it to the last one. The caller holds the lock.
+func appendAudit(entry models.AuditEntry) {
+	auditIDCounter++
+	entry.ID = auditIDCounter
+	entry.PrevHash = ""
+	if len(auditLog) > 0 {
+		entry.PrevHash = auditLog[len(auditLog)-1].Hash
+	}
+	entry.Hash = entry.Digest()
+	auditLog = append(auditLog, entry)
+}
+
+/**
+ * @brief AuditLog returns a copy of the audit log, oldest first.
+ */
+func AuditLog() []models.AuditEntry {
+	lock.Lock()
+	defer lock.Unlock()
+
+	return append([]models.AuditEntry(nil), auditLog...)
+}
\ No newline at end of file
@@ -1,26 +1,111 @@
 package database
 
 import (
+	"errors"
 	"time"
 	"go-api/pkg/models"
 )
 
 var bookingIDCounter = 3
 var classIDCounter = 3
+var studioIDCounter = 2
+var memberIDCounter = 3
+var webhookIDCounter = 0
+var planIDCounter = 2
+var membershipIDCounter = 0
+var creditEntryIDCounter = 3
+
+const DefaultStudioID = 1
+
+// ErrNotFound is returned by Locate for records removed since they were looked up, e.g. by the purge job.
+var ErrNotFound = errors.New("record not found")
+
+var Studios = []models.Studio{
+	{ID: 1, Name: "Centro", TimeZone: "America/Montevideo"},
+	{ID: 2, Name: "Madrid", TimeZone: "Europe/Madrid"},
+}
 
 var Bookings = []models.Booking{
-    {ID: 1, Name: "Diego", ClassId: 1, Date: time.Date(2023, 10, 6, 16, 0, 0, 0, time.UTC)},
-    {ID: 2, Name: "Martin", ClassId: 2, Date: time.Date(2023, 10, 7, 20, 0, 0, 0, time.UTC)},
-    {ID: 3, Name: "Joaquin", ClassId: 3, Date: time.Date(2023, 10, 11, 11, 0, 0, 0, time.UTC)},
+    {ID: 1, Name: "Diego", MemberId: 1, ClassId: 1, Date: time.Date(2023, 10, 6, 16, 0, 0, 0, time.UTC), Version: 1, Status: models.BookingConfirmed},
+    {ID: 2, Name: "Martin", MemberId: 2, ClassId: 2, Date: time.Date(2023, 10, 7, 20, 0, 0, 0, time.UTC), Version: 1, Status: models.BookingConfirmed},
+    {ID: 3, Name: "Joaquin", MemberId: 3, ClassId: 3, Date: time.Date(2023, 10, 11, 11, 0, 0, 0, time.UTC), Version: 1, Status: models.BookingConfirmed},
 }
 
+var Members = []models.Member{
+	{ID: 1, Name: "Diego", Email: "diego@example.com"},
+	{ID: 2, Name: "Martin", Email: "martin@example.com"},
+	{ID: 3, Name: "Joaquin", Email: "joaquin@example.com"},
+}
+
+var Plans = []models.Plan{
+	{ID: 1, Name: "10-class pack", Kind: models.PlanPack, Credits: 10},
+	{ID: 2, Name: "Unlimited monthly", Kind: models.PlanMembership, Days: 30},
+}
+
+var Memberships = []models.Membership{}
+
+// CreditLedger holds every movement of class credits, oldest first. The
+// members booking before plans existed start with a 10-class pack.
+var CreditLedger = []models.CreditEntry{
+	{ID: 1, MemberId: 1, Amount: 10, Balance: 10, Reason: "purchase", PlanId: 1, At: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)},
+	{ID: 2, MemberId: 2, Amount: 10, Balance: 10, Reason: "purchase", PlanId: 1, At: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)},
+	{ID: 3, MemberId: 3, Amount: 10, Balance: 10, Reason: "purchase", PlanId: 1, At: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)},
+}
+
+var Webhooks = []models.Webhook{}
+
+// EmailTemplates holds the notification templates studios use instead of the defaults.
+var EmailTemplates = []models.EmailTemplate{}
+
 var Classes = []models.Class{
-	{ID: 1, Name: "Yoga", StartDate: time.Date(2023, 10, 6, 16, 0, 0, 0, time.UTC), EndDate: time.Date(2023, 10, 16, 17, 0, 0, 0, time.UTC), Capacity: 10},
-	{ID: 2, Name: "Pilates", StartDate: time.Date(2023, 10, 7, 20, 0, 0, 0, time.UTC), EndDate: time.Date(2023, 10, 10, 21, 0, 0, 0, time.UTC), Capacity: 8},
-	{ID: 3, Name: "Boxing", StartDate: time.Date(2023, 10, 11, 11, 0, 0, 0, time.UTC), EndDate: time.Date(2023, 10, 12, 12, 0, 0, 0, time.UTC), Capacity: 12},
+	{ID: 1, Name: "Yoga", StudioId: 1, StartDate: time.Date(2023, 10, 6, 16, 0, 0, 0, time.UTC), EndDate: time.Date(2023, 10, 16, 17, 0, 0, 0, time.UTC), Capacity: 10, Version: 1},
+	{ID: 2, Name: "Pilates", StudioId: 1, StartDate: time.Date(2023, 10, 7, 20, 0, 0, 0, time.UTC), EndDate: time.Date(2023, 10, 10, 21, 0, 0, 0, time.UTC), Capacity: 8, Version: 1},
+	{ID: 3, Name: "Boxing", StudioId: 1, StartDate: time.Date(2023, 10, 11, 11, 0, 0, 0, time.UTC), EndDate: time.Date(2023, 10, 12, 12, 0, 0, 0, time.UTC), Capacity: 12, Version: 1},
 }
 
+/**
+ * @brief FindItemByID returns a copy of the item of a list with the given ID, and its index.
+ *
+ * Soft-deleted classes and bookings are not found; use FindAnyItemByID to see them.
+ *
+ * @return interface{}: A pointer to the copy, nil when there is none.
+ * @return int: The index of the item, -1 when there is none.
+ */
 func FindItemByID(list interface{}, id int) (interface{}, int) {
+	item, index := FindAnyItemByID(list, id)
+	switch found := item.(type) {
+	case *models.Booking:
+		if found.DeletedAt != nil {
+			return nil, -1
+		}
+	case *models.Class:
+		if found.DeletedAt != nil {
+			return nil, -1
+		}
+	}
+	return item, index
+}
+
+/**
+ * @brief Locate returns the index of the item of a list with the given ID, soft-deleted or not.
+ *
+ * Call it inside the Transaction changing the item in place: Purge removes
+ * records, so an index found before the lock was taken may point to another item.
+ *
+ * @return int: The index of the item.
+ * @return error: ErrNotFound when there is none.
+ */
+func Locate(list interface{}, id int) (int, error) {
+	if _, index := FindAnyItemByID(list, id); index >= 0 {
+		return index, nil
+	}
+	return -1, ErrNotFound
+}
+
+/**
+ * @brief FindAnyItemByID is FindItemByID including soft-deleted classes and bookings.
+ */
+func FindAnyItemByID(list interface{}, id int) (interface{}, int) {
 	switch items := list.(type) {
 	case []models.Booking:
 		for index, item := range items {
@@ -34,6 +119,30 @@ func FindItemByID(list interface{}, id int) (interface{}, int) {
 				return &item, index
 			}
 		}
+	case []models.Studio:
+		for index, item := range items {
+			if item.ID == id {
+				return &item, index
+			}
+		}
+	case []models.Member:
+		for index, item := range items {
+			if item.ID == id {
+				return &item, index
+			}
+		}
+	case []models.Webhook:
+		for index, item := range items {
+			if item.ID == id {
+				return &item, index
+			}
+		}
+	case []models.Plan:
+		for index, item := range items {
+			if item.ID == id {
+				return &item, index
+			}
+		}
 	}
 	return nil, -1
 }
@@ -45,16 +154,19 @@ func CreateBooking(newBooking models.CreateBooking) models.Booking {
         Name:      newBooking.Name,
         ClassId:   newBooking.ClassId,
         Date:      newBooking.Date,
+        Version:   1,
+        Status:    models.BookingConfirmed,
     }
     return booking
 }
 
-func UpdateBooking(newBooking models.UpdateBooking, index int) models.Booking {
+func UpdateBooking(newBooking models.UpdateBooking, index int, version int) models.Booking {
     booking := models.Booking{
 		ID:        		index,
 		Name:      		newBooking.Name,
         ClassId:   		newBooking.ClassId,
         Date:      		newBooking.Date,
+        Version:   		version,
     }
     return booking
 }
@@ -64,20 +176,121 @@ func CreateClass(newClass models.CreateClass) models.Class {
     class := models.Class{
 		ID:        		classIDCounter,
 		Name:      		newClass.Name,
+		StudioId:  		newClass.StudioId,
         StartDate:      newClass.StartDate,
         EndDate:   		newClass.EndDate,
         Capacity:      	newClass.Capacity,
+        Price:      	newClass.Price,
+        Version:   		1,
     }
     return class
 }
 
-func UpdateClass(newClass models.UpdateClass, index int) models.Class {
+func UpdateClass(newClass models.UpdateClass, index int, version int) models.Class {
     class := models.Class{
 		ID:        		index,
 		Name:      		newClass.Name,
+		StudioId:  		newClass.StudioId,
         StartDate:      newClass.StartDate,
         EndDate:   		newClass.EndDate,
         Capacity:      	newClass.Capacity,
+        Price:      	newClass.Price,
+        Version:   		version,
     }
     return class
+}
+
+func CreateStudio(newStudio models.CreateStudio) models.Studio {
+	studioIDCounter++
+	studio := models.Studio{
+		ID:        studioIDCounter,
+		Name:      newStudio.Name,
+		TimeZone:  newStudio.TimeZone,
+	}
+	return studio
+}
+
+func CreateMember(newMember models.CreateMember) models.Member {
+	memberIDCounter++
+	member := models.Member{
+		ID:        memberIDCounter,
+		Name:      newMember.Name,
+		Email:     newMember.Email,
+	}
+	return member
+}
+
+func CreateWebhook(newWebhook models.CreateWebhook, secret string) models.Webhook {
+	webhookIDCounter++
+	webhook := models.Webhook{
+		ID:     webhookIDCounter,
+		URL:    newWebhook.URL,
+		Events: newWebhook.Events,
+		Secret: secret,
+	}
+	return webhook
+}
+
+func CreatePlan(newPlan models.CreatePlan) models.Plan {
+	planIDCounter++
+	plan := models.Plan{
+		ID:      planIDCounter,
+		Name:    newPlan.Name,
+		Kind:    newPlan.Kind,
+		Credits: newPlan.Credits,
+		Days:    newPlan.Days,
+	}
+	return plan
+}
+
+func CreateMembership(memberId int, plan models.Plan, startsAt time.Time) models.Membership {
+	membershipIDCounter++
+	membership := models.Membership{
+		ID:       membershipIDCounter,
+		MemberId: memberId,
+		PlanId:   plan.ID,
+		Name:     plan.Name,
+		StartsAt: startsAt,
+		EndsAt:   startsAt.AddDate(0, 0, plan.Days),
+	}
+	return membership
+}
+
+func CreateCreditEntry(entry models.CreditEntry) models.CreditEntry {
+	creditEntryIDCounter++
+	entry.ID = creditEntryIDCounter
+	return entry
+}
+
+func StudioTimeZone(studioId int) string {
+	studio, _ := FindItemByID(Studios, studioId)
+	if studio == nil {
+		return "UTC"
+	}
+	return studio.(*models.Studio).TimeZone
+}
+
+func ClassTimeZone(classId int) string {
+	class, _ := FindItemByID(Classes, classId)
+	if class == nil {
+		return "UTC"
+	}
+	return StudioTimeZone(class.(*models.Class).StudioId)
+}
+
+/**
+ * @brief SeatsTaken counts the bookings holding a seat in a session of a class.
+ *
+ * @param classId int: The class.
+ * @param date time.Time: The start of the session.
+ * @return int: The bookings whose status holds a seat, see Booking.HoldsSeat; soft-deleted ones hold none.
+ */
+func SeatsTaken(classId int, date time.Time) int {
+	taken := 0
+	for _, booking := range Bookings {
+		if booking.ClassId == classId && booking.Date.Equal(date) && booking.HoldsSeat() && booking.DeletedAt == nil {
+			taken++
+		}
+	}
+	return taken
 }
\ No newline at end of file
@@ -0,0 +1,169 @@
+package database
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+	"go-api/pkg/models"
+)
+
+// Outbox holds the domain events of committed transactions until every subscriber of the event bus has processed them.
+var Outbox = []models.DomainEvent{}
+var eventIDCounter int64 = 0
+
+var lock sync.Mutex
+var committed = make(chan struct{}, 1)
+
+// Tx collects the events and audit entries recorded by a transaction.
+type Tx struct {
+	events []models.DomainEvent
+	audit  []models.AuditEntry
+}
+
+/**
+ * @brief Record adds a domain event to the transaction.
+ *
+ * Call Record before changing the data, so that a failure leaves nothing half done.
+ *
+ * @param eventType string: e.g. "booking.created"; the part before the dot is the aggregate type.
+ * @param aggregateID int: The ID of the booking or class.
+ * @param data interface{}: The aggregate after the change.
+ */
+func (tx *Tx) Record(eventType string, aggregateID int, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	aggregateType, _, _ := strings.Cut(eventType, ".")
+	tx.events = append(tx.events, models.DomainEvent{
+		Type:          eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		OccurredAt:    time.Now().UTC(),
+		Data:          payload,
+	})
+	return nil
+}
+
+/**
+ * @brief Audit adds an entry to the audit log when the transaction commits.
+ *
+ * @param entry models.AuditEntry: The change; its ID and hashes are set on commit.
+ */
+func (tx *Tx) Audit(entry models.AuditEntry) {
+	tx.audit = append(tx.audit, entry)
+}
+
+// snapshot holds copies of the tables a transaction may change, put back when it fails.
+type snapshot struct {
+	bookings    []models.Booking
+	classes     []models.Class
+	members     []models.Member
+	memberships []models.Membership
+	ledger      []models.CreditEntry
+	webhooks    []models.Webhook
+}
+
+// takeSnapshot copies the tables. The caller holds the lock.
+func takeSnapshot() snapshot {
+	return snapshot{
+		bookings:    append([]models.Booking(nil), Bookings...),
+		classes:     append([]models.Class(nil), Classes...),
+		members:     append([]models.Member(nil), Members...),
+		memberships: append([]models.Membership(nil), Memberships...),
+		ledger:      append([]models.CreditEntry(nil), CreditLedger...),
+		webhooks:    append([]models.Webhook(nil), Webhooks...),
+	}
+}
+
+// restore puts the tables back as they were when the snapshot was taken. The caller holds the lock.
+func (s snapshot) restore() {
+	Bookings = s.bookings
+	Classes = s.classes
+	Members = s.members
+	Memberships = s.memberships
+	CreditLedger = s.ledger
+	Webhooks = s.webhooks
+}
+
+/**
+ * @brief Transaction runs fn holding the database lock and, if it succeeds, appends its events to the Outbox.
+ *
+ * The audit entries of the transaction are appended to the audit log at the
+ * same time. When fn fails, the bookings, classes, members, credits and
+ * webhooks it changed are rolled back, so that no change is kept without its
+ * events.
+ *
+ * @param fn func(tx *Tx) error: Changes the data and records the matching events.
+ * @return error: The error of fn; nothing is changed nor added to the Outbox then.
+ */
+func Transaction(fn func(tx *Tx) error) error {
+	lock.Lock()
+	defer lock.Unlock()
+
+	tx := &Tx{}
+	before := takeSnapshot()
+	if err := fn(tx); err != nil {
+		before.restore()
+		return err
+	}
+	for _, entry := range tx.audit {
+		appendAudit(entry)
+	}
+	if len(tx.events) == 0 {
+		return nil
+	}
+
+	for _, event := range tx.events {
+		eventIDCounter++
+		event.ID = eventIDCounter
+		Outbox = append(Outbox, event)
+	}
+
+	select {
+	case committed <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+/**
+ * @brief Committed returns a channel signalled after transactions that recorded events.
+ */
+func Committed() <-chan struct{} {
+	return committed
+}
+
+/**
+ * @brief PendingEvents returns the Outbox events with an ID above after, oldest first.
+ */
+func PendingEvents(after int64) []models.DomainEvent {
+	lock.Lock()
+	defer lock.Unlock()
+
+	var events []models.DomainEvent
+	for _, event := range Outbox {
+		if event.ID > after {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+/**
+ * @brief TrimOutbox drops the events up to and including id, once every subscriber has processed them.
+ */
+func TrimOutbox(id int64) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	kept := Outbox[:0]
+	for _, event := range Outbox {
+		if event.ID > id {
+			kept = append(kept, event)
+		}
+	}
+	Outbox = kept
+}
\ No newline at end of file
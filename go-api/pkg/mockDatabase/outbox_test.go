@@ -0,0 +1,45 @@
+package database
+
+import (
+	"errors"
+	"testing"
+	"go-api/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionRollsBackOnError(t *testing.T) {
+	failed := errors.New("failed")
+	bookings := len(Bookings)
+	outbox := len(Outbox)
+	audited := len(AuditLog())
+	name := Bookings[0].Name
+
+	err := Transaction(func(tx *Tx) error {
+		if err := tx.Record("booking.updated", Bookings[0].ID, Bookings[0]); err != nil {
+			return err
+		}
+		tx.Audit(models.AuditEntry{Actor: "test", Action: "booking.updated", Resource: "booking", ResourceId: Bookings[0].ID})
+		Bookings[0].Name = "Changed"
+		Bookings = append(Bookings, models.Booking{ID: 9000, Name: "Added"})
+		CreditLedger = append(CreditLedger, models.CreditEntry{ID: 9000, MemberId: 1, Amount: -1})
+		return failed
+	})
+	assert.ErrorIs(t, err, failed)
+
+	// Nothing written before the error is kept, and nothing is recorded
+	assert.Equal(t, name, Bookings[0].Name)
+	assert.Len(t, Bookings, bookings)
+	for _, entry := range CreditLedger {
+		assert.NotEqual(t, 9000, entry.ID)
+	}
+	assert.Len(t, Outbox, outbox)
+	assert.Len(t, AuditLog(), audited)
+
+	// A successful transaction keeps its changes
+	assert.NoError(t, Transaction(func(tx *Tx) error {
+		Bookings[0].Name = "Changed"
+		return nil
+	}))
+	assert.Equal(t, "Changed", Bookings[0].Name)
+	Bookings[0].Name = name
+}
\ No newline at end of file
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+)
+
+// CheckIn names the booking to mark as attended, directly or through its member.
+type CheckIn struct {
+	BookingId int `json:"booking_id,omitempty" validate:"required_without=MemberId"`
+	MemberId  int `json:"member_id,omitempty" validate:"required_without=BookingId"`
+}
+
+// BulkCheckIn checks in several members of a class roster at once.
+type BulkCheckIn struct {
+	CheckIns []CheckIn `json:"check_ins" validate:"required,min=1,dive"`
+}
+
+// CheckInResult is the outcome of one check-in of a BulkCheckIn.
+type CheckInResult struct {
+	BookingId int      `json:"booking_id,omitempty"`
+	MemberId  int      `json:"member_id,omitempty"`
+	Status    string   `json:"status" validate:"required,oneof=checked_in failed"`
+	Error     string   `json:"error,omitempty"`
+	Booking   *Booking `json:"booking,omitempty"`
+}
+
+type CheckInReport struct {
+	CheckedIn int             `json:"checked_in"`
+	Failed    int             `json:"failed"`
+	Results   []CheckInResult `json:"results" validate:"required"`
+}
+
+// AttendanceRecord is one past or upcoming booking of a member.
+type AttendanceRecord struct {
+	BookingId int       `json:"booking_id" validate:"required"`
+	ClassId   int       `json:"class_id" validate:"required"`
+	ClassName string    `json:"class_name,omitempty"`
+	Date      time.Time `json:"date" validate:"required"`
+	Status    string    `json:"status" validate:"required"`
+	// At is when the booking reached its status.
+	At time.Time `json:"at,omitempty"`
+}
+
+// Attendance is the attendance history of a member, newest first, with its counts.
+type Attendance struct {
+	MemberId          int                `json:"member_id" validate:"required"`
+	Attended          int                `json:"attended"`
+	NoShows           int                `json:"no_shows"`
+	LateCancellations int                `json:"late_cancellations"`
+	History           []AttendanceRecord `json:"history" validate:"required"`
+}
+
+// CheckInCode is a short-lived signed token a member shows, as a QR code, to check in at the front-desk kiosk.
+type CheckInCode struct {
+	BookingId int       `json:"booking_id" validate:"required"`
+	Token     string    `json:"token" validate:"required"`
+	ExpiresAt time.Time `json:"expires_at" validate:"required"`
+}
+
+// KioskCheckIn is the token read by the kiosk from a CheckInCode.
+type KioskCheckIn struct {
+	Token string `json:"token" validate:"required"`
+}
\ No newline at end of file
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// AuditEntry records who changed a class or booking, and how. Entries are chained by hash: each one
+// covers the hash of the previous entry, so editing or removing an entry breaks every later hash.
+type AuditEntry struct {
+	// ID orders the entries; it grows with every committed entry.
+	ID int64 `json:"id" validate:"required"`
+	At time.Time `json:"at" validate:"required"`
+	// Actor is the name of the API key's principal, "anonymous" without a key and "system" for background jobs.
+	Actor string `json:"actor" validate:"required"`
+	Role  string `json:"role,omitempty"`
+	// RequestId is the X-Request-ID of the request that made the change.
+	RequestId string `json:"request_id,omitempty"`
+	// Action is the domain event recorded with the change, e.g. "class.updated".
+	Action     string `json:"action" validate:"required"`
+	Resource   string `json:"resource" validate:"required,oneof=class booking"`
+	ResourceId int    `json:"resource_id" validate:"required"`
+	// Changes maps each changed field to its value before and after the change.
+	Changes  map[string]AuditChange `json:"changes"`
+	PrevHash string                 `json:"prev_hash"`
+	Hash     string                 `json:"hash" validate:"required"`
+}
+
+// AuditChange is the value of a field before and after a change; Before is empty for created resources.
+type AuditChange struct {
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+// AuditVerification is the outcome of checking the hash chain of the audit log.
+type AuditVerification struct {
+	Valid   bool `json:"valid"`
+	Entries int  `json:"entries"`
+	// BrokenAt is the ID of the first entry whose hash does not match, when the chain is broken.
+	BrokenAt int64 `json:"broken_at,omitempty"`
+}
+
+/**
+ * @brief Digest returns the hex SHA-256 of the entry, with every field but Hash.
+ *
+ * @return string: The value Hash must hold.
+ */
+func (entry AuditEntry) Digest() string {
+	entry.Hash = ""
+	payload, _ := json.Marshal(entry)
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
\ No newline at end of file
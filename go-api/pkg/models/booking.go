@@ -7,11 +7,73 @@ import (
 
 var BookingValidate *validator.Validate = validator.New()
 
+// Booking statuses.
+const (
+	BookingPending    = "pending"
+	BookingConfirmed  = "confirmed"
+	BookingWaitlisted = "waitlisted"
+	BookingCancelled  = "cancelled"
+	BookingAttended   = "attended"
+	BookingNoShow     = "no_show"
+)
+
+// BookingTransitions lists the statuses each status can change to. Cancelled, attended and no_show are final.
+var BookingTransitions = map[string][]string{
+	BookingPending:    {BookingConfirmed, BookingWaitlisted, BookingCancelled},
+	BookingWaitlisted: {BookingConfirmed, BookingCancelled},
+	BookingConfirmed:  {BookingCancelled, BookingAttended, BookingNoShow},
+}
+
 type Booking struct {
 	ID       	int `json:"id" validate:"required"`
 	Name      	string `json:"name" validate:"required,alphanum,max=20"`
 	ClassId 	int `json:"class_id" validate:"required"`
 	Date      	time.Time `json:"date" validate:"required"`
+	Version   	int `json:"version"`
+	Status    	string `json:"status" validate:"required,oneof=pending confirmed waitlisted cancelled attended no_show"`
+	// MemberId is the member who made the booking, whose membership or class credits cover it; 0 for drop-ins.
+	MemberId int `json:"member_id,omitempty"`
+	// Transitions records when the booking entered each status, starting with its initial one.
+	Transitions []BookingTransition `json:"transitions,omitempty"`
+	// Cancellation is set once the booking is cancelled.
+	Cancellation *Cancellation `json:"cancellation,omitempty"`
+	// Entitlement is what covered the booking of a member: "membership" or "credit", refunded on cancellation.
+	Entitlement string `json:"entitlement,omitempty" validate:"omitempty,oneof=membership credit"`
+	// Payment is set for drop-in bookings of priced classes, confirmed once it succeeds.
+	Payment *Payment `json:"payment,omitempty"`
+	// DeletedAt is set while the booking is soft-deleted together with its class.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// Payment is the payment of a drop-in booking through the payment provider.
+type Payment struct {
+	IntentId string `json:"intent_id" validate:"required"`
+	// ClientSecret lets the client complete the payment with the provider.
+	ClientSecret string `json:"client_secret,omitempty"`
+	// Amount is the class price paid, in cents of Currency.
+	Amount   int    `json:"amount" validate:"required"`
+	Currency string `json:"currency" validate:"required"`
+	Status   string `json:"status" validate:"required,oneof=requires_payment succeeded failed cancelled refunding refunded"`
+	// Refunded is the amount given back when the booking was cancelled, in cents.
+	Refunded int `json:"refunded,omitempty"`
+}
+
+// BookingTransition is one status change of a booking. From is empty for the initial status.
+type BookingTransition struct {
+	From string    `json:"from,omitempty"`
+	To   string    `json:"to" validate:"required"`
+	At   time.Time `json:"at" validate:"required"`
+}
+
+// Cancellation records which outcome of the cancellation policy applied to a booking.
+type Cancellation struct {
+	CancelledAt time.Time `json:"cancelled_at" validate:"required"`
+	// Outcome is "free" within the free-cancellation window, "late" after it.
+	Outcome string `json:"outcome" validate:"required,oneof=free late"`
+	// Penalty is what a late cancellation costs: nothing, a class credit or a fee.
+	Penalty string `json:"penalty" validate:"required,oneof=none credit fee"`
+	// Fee is charged when Penalty is "fee", in cents.
+	Fee int `json:"fee,omitempty"`
 }
 
 type CreateBooking struct {
@@ -24,4 +86,58 @@ type UpdateBooking struct {
 	Name      	string `json:"name" validate:"required,alphanum,max=20"`
 	ClassId 	int `json:"class_id" validate:"required"`
 	Date      	time.Time `json:"date" validate:"required"`
+}
+
+/**
+ * @brief In returns a copy of the booking with its date expressed in loc.
+ *
+ * @param loc *time.Location: The zone to render the date in.
+ * @return Booking: The converted booking.
+ */
+func (booking Booking) In(loc *time.Location) Booking {
+	booking.Date = booking.Date.In(loc)
+	return booking
+}
+
+/**
+ * @brief CanTransition reports whether the booking can change to the given status.
+ *
+ * @param to string: The new status.
+ * @return bool: Whether BookingTransitions allows the change.
+ */
+func (booking Booking) CanTransition(to string) bool {
+	for _, allowed := range BookingTransitions[booking.Status] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+/**
+ * @brief Transition changes the status of the booking and records when it happened.
+ *
+ * The transition is not checked; use CanTransition first.
+ *
+ * @param to string: The new status.
+ * @param at time.Time: When the status changed.
+ */
+func (booking *Booking) Transition(to string, at time.Time) {
+	// Copies of a booking share the history; never append into it in place.
+	history := booking.Transitions[:len(booking.Transitions):len(booking.Transitions)]
+	booking.Transitions = append(history, BookingTransition{From: booking.Status, To: to, At: at.UTC()})
+	booking.Status = to
+}
+
+/**
+ * @brief HoldsSeat reports whether the booking counts against the class capacity.
+ *
+ * @return bool: true for pending, confirmed and attended bookings.
+ */
+func (booking Booking) HoldsSeat() bool {
+	switch booking.Status {
+	case BookingPending, BookingConfirmed, BookingAttended:
+		return true
+	}
+	return false
 }
\ No newline at end of file
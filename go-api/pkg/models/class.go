@@ -10,21 +10,96 @@ var ClassValidate *validator.Validate = validator.New()
 type Class struct {
 	ID         int `json:"id" validate:"required"`
 	Name       string `json:"name" validate:"required,alphanum,max=20"`
+	StudioId   int `json:"studio_id"`
 	StartDate  time.Time `json:"start_date" validate:"required"`
 	EndDate    time.Time `json:"end_date" validate:"required"`
 	Capacity   int    `json:"capacity" validate:"required"`
+	// Price is what a drop-in booking costs, in cents; classes without a price are free.
+	Price      int    `json:"price,omitempty" validate:"omitempty,min=0"`
+	Version    int    `json:"version"`
+	SourceUID  string `json:"source_uid,omitempty"`
+	// SourceRecurrenceId is the original start of the imported occurrence of a recurring event (its RECURRENCE-ID).
+	SourceRecurrenceId *time.Time `json:"source_recurrence_id,omitempty"`
+	// DeletedAt is set while the class is soft-deleted; it is purged for good after the retention period.
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"`
 }
 
 type CreateClass struct {
 	Name       string `json:"name" validate:"required,alphanum,max=20"`
-	StartDate  time.Time `json:"start_date" validate:"required"`
-	EndDate    time.Time `json:"end_date" validate:"required"`
+	StudioId   int `json:"studio_id,omitempty"`
+	StartDate  time.Time `json:"start_date" validate:"required_without=LocalStartDate"`
+	EndDate    time.Time `json:"end_date" validate:"required_without=LocalEndDate"`
+	LocalStartDate string `json:"local_start_date,omitempty" validate:"omitempty,datetime=2006-01-02T15:04:05"`
+	LocalEndDate   string `json:"local_end_date,omitempty" validate:"omitempty,datetime=2006-01-02T15:04:05"`
 	Capacity   int    `json:"capacity" validate:"required"`
+	Price      int    `json:"price,omitempty" validate:"omitempty,min=0"`
 }
 
 type UpdateClass struct {
 	Name       string `json:"name" validate:"required,alphanum,max=20"`
-	StartDate  time.Time `json:"start_date" validate:"required"`
-	EndDate    time.Time `json:"end_date" validate:"required"`
+	StudioId   int `json:"studio_id,omitempty"`
+	StartDate  time.Time `json:"start_date" validate:"required_without=LocalStartDate"`
+	EndDate    time.Time `json:"end_date" validate:"required_without=LocalEndDate"`
+	LocalStartDate string `json:"local_start_date,omitempty" validate:"omitempty,datetime=2006-01-02T15:04:05"`
+	LocalEndDate   string `json:"local_end_date,omitempty" validate:"omitempty,datetime=2006-01-02T15:04:05"`
 	Capacity   int    `json:"capacity" validate:"required"`
+	Price      int    `json:"price,omitempty" validate:"omitempty,min=0"`
+}
+
+type CreateRecurringClass struct {
+	Name            string `json:"name" validate:"required,alphanum,max=20"`
+	StudioId        int `json:"studio_id,omitempty"`
+	LocalStartDate  string `json:"local_start_date" validate:"required,datetime=2006-01-02T15:04:05"`
+	DurationMinutes int `json:"duration_minutes" validate:"required,min=1"`
+	Capacity        int `json:"capacity" validate:"required"`
+	Price           int `json:"price,omitempty" validate:"omitempty,min=0"`
+	Frequency       string `json:"frequency" validate:"required,oneof=daily weekly"`
+	Interval        int `json:"interval,omitempty" validate:"omitempty,min=1"`
+	Count           int `json:"count" validate:"required,min=1,max=52"`
+}
+
+type ClassImportRow struct {
+	Row      int      `json:"row" validate:"required"`
+	Status   string   `json:"status" validate:"required,oneof=created updated unchanged valid invalid"`
+	UID      string   `json:"uid,omitempty"`
+	Error    string   `json:"error,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+	Class    *Class   `json:"class,omitempty"`
+}
+
+type ClassImportReport struct {
+	Mode    string `json:"mode" validate:"required,oneof=atomic best_effort"`
+	DryRun  bool   `json:"dry_run"`
+	Total   int    `json:"total"`
+	Created int    `json:"created"`
+	Updated int    `json:"updated"`
+	Unchanged int  `json:"unchanged"`
+	Invalid int    `json:"invalid"`
+	Error   string `json:"error,omitempty"`
+	Rows    []ClassImportRow `json:"rows" validate:"required"`
+}
+
+/**
+ * @brief In returns a copy of the class with its dates expressed in loc.
+ *
+ * @param loc *time.Location: The zone to render the dates in.
+ * @return Class: The converted class.
+ */
+func (class Class) In(loc *time.Location) Class {
+	class.StartDate = class.StartDate.In(loc)
+	class.EndDate = class.EndDate.In(loc)
+	return class
+}
+
+/**
+ * @brief SessionLength returns the length of one session of a class spanning several days.
+ *
+ * @return time.Duration: The time of day between start and end, one hour when they match.
+ */
+func (class Class) SessionLength() time.Duration {
+	length := class.EndDate.Sub(class.StartDate) % (24 * time.Hour)
+	if length <= 0 {
+		return time.Hour
+	}
+	return length
 }
\ No newline at end of file
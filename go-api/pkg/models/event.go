@@ -0,0 +1,17 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// DomainEvent is a change to a booking or class, written to the outbox together with the change itself.
+type DomainEvent struct {
+	// ID orders the events; it grows with every committed event.
+	ID            int64           `json:"id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   int             `json:"aggregate_id"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Data          json.RawMessage `json:"data"`
+}
\ No newline at end of file
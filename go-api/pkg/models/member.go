@@ -0,0 +1,26 @@
+package models
+
+import (
+	"github.com/go-playground/validator/v10"
+)
+
+var MemberValidate *validator.Validate = validator.New()
+
+// Member is a person who books classes. Bookings belong to the member whose Name they carry.
+type Member struct {
+	ID        int    `json:"id" validate:"required"`
+	Name      string `json:"name" validate:"required,alphanum,max=20"`
+	Email     string `json:"email,omitempty" validate:"omitempty,email"`
+	FeedToken string `json:"-"`
+}
+
+type CreateMember struct {
+	Name  string `json:"name" validate:"required,alphanum,max=20"`
+	Email string `json:"email,omitempty" validate:"omitempty,email"`
+}
+
+// MemberFeed is the private calendar feed of a member.
+type MemberFeed struct {
+	Token string `json:"token" validate:"required"`
+	URL   string `json:"url" validate:"required"`
+}
\ No newline at end of file
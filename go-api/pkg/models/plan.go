@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+	"github.com/go-playground/validator/v10"
+)
+
+var PlanValidate *validator.Validate = validator.New()
+
+// Plan kinds.
+const (
+	PlanPack       = "pack"
+	PlanMembership = "membership"
+)
+
+// Booking entitlements: what a member's booking was covered by.
+const (
+	EntitlementMembership = "membership"
+	EntitlementCredit     = "credit"
+)
+
+// Plan is what members buy to book classes: a pack of class credits or an unlimited membership.
+type Plan struct {
+	ID   int    `json:"id" validate:"required"`
+	Name string `json:"name" validate:"required,max=40"`
+	Kind string `json:"kind" validate:"required,oneof=pack membership"`
+	// Credits is the number of classes in a pack.
+	Credits int `json:"credits,omitempty" validate:"required_if=Kind pack,omitempty,min=1"`
+	// Days is how long a membership lasts.
+	Days int `json:"days,omitempty" validate:"required_if=Kind membership,omitempty,min=1"`
+}
+
+type CreatePlan struct {
+	Name    string `json:"name" validate:"required,max=40"`
+	Kind    string `json:"kind" validate:"required,oneof=pack membership"`
+	Credits int    `json:"credits,omitempty" validate:"required_if=Kind pack,omitempty,min=1"`
+	Days    int    `json:"days,omitempty" validate:"required_if=Kind membership,omitempty,min=1"`
+}
+
+// PurchasePlan gives a member a plan.
+type PurchasePlan struct {
+	PlanId int `json:"plan_id" validate:"required"`
+}
+
+// Membership lets a member book any class between StartsAt and EndsAt.
+type Membership struct {
+	ID       int       `json:"id" validate:"required"`
+	MemberId int       `json:"member_id" validate:"required"`
+	PlanId   int       `json:"plan_id" validate:"required"`
+	Name     string    `json:"name" validate:"required"`
+	StartsAt time.Time `json:"starts_at" validate:"required"`
+	EndsAt   time.Time `json:"ends_at" validate:"required"`
+}
+
+// CreditEntry is one movement of the class credits of a member.
+type CreditEntry struct {
+	ID       int `json:"id" validate:"required"`
+	MemberId int `json:"member_id" validate:"required"`
+	// Amount is positive for credits added, negative for credits spent.
+	Amount int `json:"amount" validate:"required"`
+	// Balance is the member's credits after the movement.
+	Balance   int       `json:"balance"`
+	Reason    string    `json:"reason" validate:"required,oneof=purchase booking refund"`
+	PlanId    int       `json:"plan_id,omitempty"`
+	BookingId int       `json:"booking_id,omitempty"`
+	At        time.Time `json:"at" validate:"required"`
+}
+
+// Credits is the class credit balance of a member, their active membership and the ledger of credit movements, oldest first.
+type Credits struct {
+	MemberId   int           `json:"member_id" validate:"required"`
+	Balance    int           `json:"balance"`
+	Membership *Membership   `json:"membership,omitempty"`
+	Ledger     []CreditEntry `json:"ledger" validate:"required"`
+}
\ No newline at end of file
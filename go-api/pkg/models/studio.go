@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+	"github.com/go-playground/validator/v10"
+)
+
+var StudioValidate *validator.Validate = validator.New()
+
+type Studio struct {
+	ID       int    `json:"id" validate:"required"`
+	Name     string `json:"name" validate:"required,max=40"`
+	TimeZone string `json:"time_zone" validate:"required,timezone"`
+}
+
+type CreateStudio struct {
+	Name     string `json:"name" validate:"required,max=40"`
+	TimeZone string `json:"time_zone" validate:"required,timezone"`
+}
+
+/**
+ * @brief Location returns the IANA time zone of the studio.
+ *
+ * @return *time.Location: The studio location, UTC if the zone is unknown.
+ */
+func (studio Studio) Location() *time.Location {
+	loc, err := time.LoadLocation(studio.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
\ No newline at end of file
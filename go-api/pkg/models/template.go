@@ -0,0 +1,25 @@
+package models
+
+import (
+	"github.com/go-playground/validator/v10"
+)
+
+var EmailTemplateValidate *validator.Validate = validator.New()
+
+// EmailTemplate is the notification email of one kind sent for a studio's classes.
+// Subject and Text are text/template sources, HTML an html/template source.
+type EmailTemplate struct {
+	StudioId int    `json:"studio_id" validate:"required"`
+	Kind     string `json:"kind" validate:"required,oneof=confirmation change cancellation promotion reminder"`
+	Subject  string `json:"subject" validate:"required"`
+	Text     string `json:"text" validate:"required"`
+	HTML     string `json:"html" validate:"required"`
+	// Default is true when the studio has not overridden the template.
+	Default bool `json:"default"`
+}
+
+type UpdateEmailTemplate struct {
+	Subject string `json:"subject" validate:"required,max=200"`
+	Text    string `json:"text" validate:"required"`
+	HTML    string `json:"html" validate:"required"`
+}
\ No newline at end of file
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+	"github.com/go-playground/validator/v10"
+)
+
+var WebhookValidate *validator.Validate = validator.New()
+
+// Webhook is a subscription of an external URL to domain events. Secret is only returned when the webhook is created.
+type Webhook struct {
+	ID     int      `json:"id" validate:"required"`
+	URL    string   `json:"url" validate:"required,url"`
+	Events []string `json:"events" validate:"required,min=1,dive,oneof=booking.created booking.updated booking.confirmed booking.promoted booking.cancelled booking.attended booking.no_show booking.deleted booking.restored class.created class.updated class.deleted class.restored"`
+	Secret string   `json:"secret,omitempty"`
+}
+
+type CreateWebhook struct {
+	URL    string   `json:"url" validate:"required,url"`
+	Events []string `json:"events" validate:"required,min=1,dive,oneof=booking.created booking.updated booking.confirmed booking.promoted booking.cancelled booking.attended booking.no_show booking.deleted booking.restored class.created class.updated class.deleted class.restored"`
+}
+
+// WebhookEvent is the signed JSON body posted to webhook URLs.
+type WebhookEvent struct {
+	ID        string          `json:"id" validate:"required"`
+	Type      string          `json:"type" validate:"required"`
+	CreatedAt time.Time       `json:"created_at" validate:"required"`
+	Data      json.RawMessage `json:"data" validate:"required"`
+}
+
+// WebhookDelivery tracks the attempts to post one event to one webhook.
+type WebhookDelivery struct {
+	ID             int       `json:"id" validate:"required"`
+	WebhookId      int       `json:"webhook_id" validate:"required"`
+	EventId        string    `json:"event_id" validate:"required"`
+	EventType      string    `json:"event_type" validate:"required"`
+	Status         string    `json:"status" validate:"required,oneof=pending delivered dead"`
+	Attempts       int       `json:"attempts"`
+	ResponseStatus int       `json:"response_status,omitempty"`
+	LastError      string    `json:"last_error,omitempty"`
+	CreatedAt      time.Time `json:"created_at" validate:"required"`
+}
\ No newline at end of file
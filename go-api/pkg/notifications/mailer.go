@@ -0,0 +1,112 @@
+package notifications
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"time"
+)
+
+// Message is one email, sent as multipart/alternative with a text and an HTML part.
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer sends messages. SMTPMailer is the production implementation.
+type Mailer interface {
+	Send(message Message) error
+}
+
+type SMTPConfig struct {
+	// Addr is the host:port of the SMTP server; notifications are disabled when empty.
+	Addr string `json:"addr"`
+	// From is the sender, e.g. "Studio <no-reply@example.com>".
+	From     string `json:"from"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type SMTPMailer struct {
+	config SMTPConfig
+}
+
+/**
+ * @brief NewSMTPMailer returns a mailer sending through the configured server, authenticating when a username is set.
+ */
+func NewSMTPMailer(config SMTPConfig) *SMTPMailer {
+	return &SMTPMailer{config: config}
+}
+
+/**
+ * @brief Send delivers a message through the SMTP server.
+ */
+func (mailer *SMTPMailer) Send(message Message) error {
+	from, err := mail.ParseAddress(mailer.config.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender: %w", err)
+	}
+
+	var auth smtp.Auth
+	if mailer.config.Username != "" {
+		host, _, _ := net.SplitHostPort(mailer.config.Addr)
+		auth = smtp.PlainAuth("", mailer.config.Username, mailer.config.Password, host)
+	}
+
+	data, err := message.Bytes(from.String())
+	if err != nil {
+		return err
+	}
+	return smtp.SendMail(mailer.config.Addr, auth, from.Address, []string{message.To}, data)
+}
+
+/**
+ * @brief Bytes renders the message as an RFC 5322 email with quoted-printable text and HTML parts.
+ *
+ * @param from string: The From header.
+ */
+func (message Message) Bytes(from string) ([]byte, error) {
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", message.Text},
+		{"text/html; charset=utf-8", message.HTML},
+	} {
+		writer, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		encoder := quotedprintable.NewWriter(writer)
+		if _, err := encoder.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	var email bytes.Buffer
+	fmt.Fprintf(&email, "From: %s\r\n", from)
+	fmt.Fprintf(&email, "To: %s\r\n", message.To)
+	fmt.Fprintf(&email, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&email, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&email, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&email, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", parts.Boundary())
+	email.Write(body.Bytes())
+	return email.Bytes(), nil
+}
\ No newline at end of file
@@ -0,0 +1,192 @@
+package notifications
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"go-api/pkg/events"
+	"go-api/pkg/models"
+	"go-api/pkg/mockDatabase"
+)
+
+// Notifier emails members about their bookings, as a subscriber of the event bus.
+type Notifier struct {
+	mailer Mailer
+
+	mu sync.Mutex
+	// sent holds, by event ID, the bookings already emailed about the last SentHistory events that
+	// emailed anyone, so that redelivered events do not email twice.
+	sent map[int64]map[int]bool
+	// events lists the keys of sent, oldest first.
+	events []int64
+}
+
+// SentHistory is how many events a Notifier remembers the emails of. The bus redelivers an event
+// only until it is handled, so older events are not seen again.
+var SentHistory = 10000
+
+/**
+ * @brief New returns a notifier sending through mailer.
+ */
+func New(mailer Mailer) *Notifier {
+	return &Notifier{mailer: mailer, sent: map[int64]map[int]bool{}}
+}
+
+// bookingKinds maps booking events to the notification sent to the member.
+var bookingKinds = map[string]string{
+	events.BookingCreated:   Confirmation,
+	events.BookingConfirmed: Confirmation,
+	events.BookingUpdated:   Change,
+	events.BookingCancelled: Cancellation,
+	events.BookingPromoted:  Promotion,
+}
+
+// Default is the notifier used by Handle, nil when no SMTP server is configured.
+var Default *Notifier
+
+/**
+ * @brief Handle is the event bus subscriber notifying through the Default notifier.
+ */
+func Handle(event models.DomainEvent) error {
+	if Default == nil {
+		return nil
+	}
+	return Default.Handle(event)
+}
+
+/**
+ * @brief Handle emails the members concerned by an event.
+ *
+ * Booking events notify the booking's member; class updates and deletions
+ * notify every member with an active booking in the class. Members without an email are skipped.
+ *
+ * @return error: The first failed send; the event is then retried, skipping the members already notified.
+ */
+func (notifier *Notifier) Handle(event models.DomainEvent) error {
+	var kind string
+	var bookings []models.Booking
+	var class *models.Class
+
+	switch event.Type {
+	case events.BookingCreated, events.BookingConfirmed, events.BookingUpdated, events.BookingCancelled, events.BookingPromoted:
+		kind = bookingKinds[event.Type]
+
+		var booking models.Booking
+		if err := json.Unmarshal(event.Data, &booking); err != nil {
+			return err
+		}
+		if kind == Confirmation && booking.Status != models.BookingConfirmed {
+			// Pending and waitlisted bookings are confirmed later.
+			return nil
+		}
+		bookings = append(bookings, booking)
+
+	case events.ClassUpdated, events.ClassDeleted, events.ClassRestored:
+		kind = Change
+		if event.Type == events.ClassDeleted {
+			kind = Cancellation
+		}
+
+		class = &models.Class{}
+		if err := json.Unmarshal(event.Data, class); err != nil {
+			return err
+		}
+		for _, booking := range database.Bookings {
+			// Bookings the restore left deleted are not told the class is back.
+			if booking.DeletedAt != nil && event.Type == events.ClassRestored {
+				continue
+			}
+			if booking.ClassId == class.ID && booking.Status != models.BookingCancelled {
+				bookings = append(bookings, booking)
+			}
+		}
+
+	default:
+		return nil
+	}
+
+	var firstErr error
+	for _, booking := range bookings {
+		if err := notifier.notify(event.ID, kind, booking, class); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (notifier *Notifier) notify(eventId int64, kind string, booking models.Booking, class *models.Class) error {
+	notifier.mu.Lock()
+	done := notifier.sent[eventId][booking.ID]
+	notifier.mu.Unlock()
+	if done {
+		return nil
+	}
+
+	if err := notifier.send(kind, booking, class); err != nil {
+		return err
+	}
+
+	notifier.mu.Lock()
+	if notifier.sent[eventId] == nil {
+		notifier.sent[eventId] = map[int]bool{}
+		notifier.events = append(notifier.events, eventId)
+		for len(notifier.events) > SentHistory {
+			delete(notifier.sent, notifier.events[0])
+			notifier.events = notifier.events[1:]
+		}
+	}
+	notifier.sent[eventId][booking.ID] = true
+	notifier.mu.Unlock()
+	return nil
+}
+
+/**
+ * @brief send renders the template of kind for a booking and emails it to the booking's member.
+ *
+ * @param class *models.Class: The class of the booking, looked up when nil.
+ */
+func (notifier *Notifier) send(kind string, booking models.Booking, class *models.Class) error {
+	member := memberOf(booking)
+	if member == nil || member.Email == "" {
+		return nil
+	}
+
+	if class == nil {
+		found, _ := database.FindItemByID(database.Classes, booking.ClassId)
+		if found == nil {
+			return nil
+		}
+		class = found.(*models.Class)
+	}
+
+	studio := models.Studio{ID: class.StudioId, TimeZone: database.StudioTimeZone(class.StudioId)}
+	if found, _ := database.FindItemByID(database.Studios, class.StudioId); found != nil {
+		studio = *found.(*models.Studio)
+	}
+
+	source, _ := Template(studio.ID, kind)
+	message, err := Render(source, Data{
+		Member:  *member,
+		Booking: booking,
+		Class:   *class,
+		Studio:  studio,
+		Start:   booking.Date.In(studio.Location()),
+		End:     booking.Date.Add(class.SessionLength()).In(studio.Location()),
+	})
+	if err != nil {
+		return err
+	}
+	message.To = member.Email
+
+	return notifier.mailer.Send(message)
+}
+
+// memberOf finds the member a booking belongs to, by name.
+func memberOf(booking models.Booking) *models.Member {
+	for _, member := range database.Members {
+		if strings.EqualFold(member.Name, booking.Name) {
+			return &member
+		}
+	}
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,191 @@
+package notifications
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"testing"
+	"time"
+	"go-api/pkg/events"
+	"go-api/pkg/models"
+	"go-api/pkg/mockDatabase"
+	"go-api/pkg/notifications/smtptest"
+	"github.com/stretchr/testify/assert"
+)
+
+// received is a parsed email of the fake SMTP server.
+type received struct {
+	to      []string
+	subject string
+	text    string
+	html    string
+}
+
+func parse(t *testing.T, message smtptest.Message) received {
+	email, err := mail.ReadMessage(bytes.NewReader(message.Data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subject, _ := new(mime.WordDecoder).DecodeHeader(email.Header.Get("Subject"))
+	result := received{to: message.To, subject: subject}
+
+	_, params, err := mime.ParseMediaType(email.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := multipart.NewReader(email.Body, params["boundary"])
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		// NextPart decodes quoted-printable bodies.
+		body, _ := io.ReadAll(part)
+		mediaType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		if mediaType == "text/html" {
+			result.html = string(body)
+		} else {
+			result.text = string(body)
+		}
+	}
+	return result
+}
+
+func newNotifier(server *smtptest.Server) *Notifier {
+	return New(NewSMTPMailer(SMTPConfig{Addr: server.Addr, From: "Studio <no-reply@example.com>"}))
+}
+
+func bookingEvent(id int64, eventType string, booking models.Booking) models.DomainEvent {
+	data, _ := json.Marshal(booking)
+	return models.DomainEvent{ID: id, Type: eventType, AggregateType: "booking", AggregateID: booking.ID, Data: data}
+}
+
+func TestConfirmationEmail(t *testing.T) {
+	server := smtptest.NewServer()
+	defer server.Close()
+	notifier := newNotifier(server)
+
+	// Booking 1 belongs to Diego, in Yoga at Centro (America/Montevideo)
+	event := bookingEvent(1, events.BookingCreated, database.Bookings[0])
+	assert.NoError(t, notifier.Handle(event))
+
+	messages := server.Messages()
+	if !assert.Len(t, messages, 1) {
+		return
+	}
+	email := parse(t, messages[0])
+	assert.Equal(t, []string{"diego@example.com"}, email.to)
+	assert.Equal(t, "Booking confirmed: Yoga on Fri 6 Oct 13:00", email.subject)
+	assert.Contains(t, email.text, "You are booked for Yoga at Centro")
+	assert.Contains(t, email.html, "<strong>Yoga</strong>")
+
+	// A redelivered event does not email twice
+	assert.NoError(t, notifier.Handle(event))
+	assert.Len(t, server.Messages(), 1)
+}
+
+func TestEmailShowsTheBookedSession(t *testing.T) {
+	server := smtptest.NewServer()
+	defer server.Close()
+	notifier := newNotifier(server)
+
+	// Yoga runs daily from Oct 6 to Oct 16; Diego books the session of Oct 12
+	booking := database.Bookings[0]
+	booking.Date = time.Date(2023, 10, 12, 16, 0, 0, 0, time.UTC)
+	assert.NoError(t, notifier.Handle(bookingEvent(3, events.BookingCreated, booking)))
+
+	messages := server.Messages()
+	if assert.Len(t, messages, 1) {
+		email := parse(t, messages[0])
+		assert.Equal(t, "Booking confirmed: Yoga on Thu 12 Oct 13:00", email.subject)
+	}
+}
+
+func TestClassChangeNotifiesBookedMembers(t *testing.T) {
+	server := smtptest.NewServer()
+	defer server.Close()
+	notifier := newNotifier(server)
+
+	class := database.Classes[1]
+	data, _ := json.Marshal(class)
+	assert.NoError(t, notifier.Handle(models.DomainEvent{ID: 2, Type: events.ClassUpdated, AggregateType: "class", AggregateID: class.ID, Data: data}))
+
+	// Only Martin is booked in Pilates
+	messages := server.Messages()
+	if assert.Len(t, messages, 1) {
+		email := parse(t, messages[0])
+		assert.Equal(t, []string{"martin@example.com"}, email.to)
+		assert.Contains(t, email.subject, "Your class has changed: Pilates")
+	}
+}
+
+func TestStudioTemplateOverride(t *testing.T) {
+	server := smtptest.NewServer()
+	defer server.Close()
+	notifier := newNotifier(server)
+
+	database.EmailTemplates = append(database.EmailTemplates, models.EmailTemplate{
+		StudioId: 1,
+		Kind:     Cancellation,
+		Subject:  "{{.Class.Name}} cancelled, {{.Member.Name}}",
+		Text:     "Sorry!",
+		HTML:     "<p>{{.Member.Name}}</p>",
+	})
+	defer func() { database.EmailTemplates = database.EmailTemplates[:len(database.EmailTemplates)-1] }()
+
+	assert.NoError(t, notifier.Handle(bookingEvent(3, events.BookingCancelled, database.Bookings[2])))
+
+	messages := server.Messages()
+	if assert.Len(t, messages, 1) {
+		email := parse(t, messages[0])
+		assert.Equal(t, "Boxing cancelled, Joaquin", email.subject)
+		assert.Equal(t, "Sorry!", email.text)
+		assert.Equal(t, "<p>Joaquin</p>", email.html)
+	}
+}
+
+func TestFailedSendIsRetried(t *testing.T) {
+	server := smtptest.NewServer()
+	defer server.Close()
+	notifier := newNotifier(server)
+
+	// The bus retries events whose handler fails
+	server.SetFailing(true)
+	event := bookingEvent(4, events.BookingPromoted, database.Bookings[1])
+	assert.Error(t, notifier.Handle(event))
+
+	server.SetFailing(false)
+	assert.NoError(t, notifier.Handle(event))
+
+	messages := server.Messages()
+	if assert.Len(t, messages, 1) {
+		assert.Contains(t, parse(t, messages[0]).subject, "A spot opened up: Pilates")
+	}
+}
+
+func TestSentHistoryIsBounded(t *testing.T) {
+	server := smtptest.NewServer()
+	defer server.Close()
+	notifier := newNotifier(server)
+
+	SentHistory = 2
+	defer func() { SentHistory = 10000 }()
+
+	for id := int64(1); id <= 3; id++ {
+		assert.NoError(t, notifier.Handle(bookingEvent(id, events.BookingPromoted, database.Bookings[1])))
+	}
+	assert.Len(t, server.Messages(), 3)
+	assert.Len(t, notifier.sent, 2)
+	assert.Equal(t, []int64{2, 3}, notifier.events)
+
+	// The events remembered are still not emailed twice
+	assert.NoError(t, notifier.Handle(bookingEvent(3, events.BookingPromoted, database.Bookings[1])))
+	assert.Len(t, server.Messages(), 3)
+}
\ No newline at end of file
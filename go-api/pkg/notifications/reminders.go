@@ -0,0 +1,152 @@
+package notifications
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+	"go-api/pkg/events"
+	"go-api/pkg/models"
+	"go-api/pkg/mockDatabase"
+	"go-api/pkg/scheduler"
+)
+
+// ReminderJob is the scheduler job kind of class reminders.
+const ReminderJob = "reminder"
+
+// ReminderOffsets are how long before the booked session starts its member is reminded.
+var ReminderOffsets = []time.Duration{24 * time.Hour, time.Hour}
+
+type reminderPayload struct {
+	BookingId int `json:"booking_id"`
+}
+
+// Reminders keeps the reminder jobs of bookings in step with booking and class events.
+type Reminders struct {
+	store scheduler.Store
+	now   func() time.Time
+}
+
+/**
+ * @brief NewReminders returns the event bus subscriber scheduling reminders in store.
+ */
+func NewReminders(store scheduler.Store) *Reminders {
+	return &Reminders{store: store, now: time.Now}
+}
+
+/**
+ * @brief Handle schedules the reminders of pending and confirmed bookings before their session, and drops them on any other status or with their class.
+ */
+func (reminders *Reminders) Handle(event models.DomainEvent) error {
+	switch event.Type {
+	case events.BookingCreated, events.BookingUpdated, events.BookingPromoted, events.BookingConfirmed, events.BookingCancelled, events.BookingAttended, events.BookingNoShow, events.BookingDeleted, events.BookingRestored:
+		var booking models.Booking
+		if err := json.Unmarshal(event.Data, &booking); err != nil {
+			return err
+		}
+		if event.Type == events.BookingDeleted || !expectsReminder(booking) {
+			return reminders.unschedule(booking.ID)
+		}
+
+		if class, _ := database.FindItemByID(database.Classes, booking.ClassId); class == nil {
+			return reminders.unschedule(booking.ID)
+		}
+		return reminders.schedule(booking.ID, booking.Date)
+
+	case events.ClassUpdated, events.ClassDeleted, events.ClassRestored:
+		var class models.Class
+		if err := json.Unmarshal(event.Data, &class); err != nil {
+			return err
+		}
+
+		for _, booking := range database.Bookings {
+			if booking.ClassId != class.ID || !expectsReminder(booking) {
+				continue
+			}
+			var err error
+			if event.Type == events.ClassDeleted {
+				err = reminders.unschedule(booking.ID)
+			} else {
+				err = reminders.schedule(booking.ID, booking.Date)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// schedule puts one job per offset, replacing the previous ones. Reminders whose time has passed are dropped.
+func (reminders *Reminders) schedule(bookingId int, start time.Time) error {
+	payload, _ := json.Marshal(reminderPayload{BookingId: bookingId})
+
+	for _, offset := range ReminderOffsets {
+		id := reminderID(bookingId, offset)
+		runAt := start.Add(-offset)
+
+		var err error
+		if runAt.After(reminders.now()) {
+			err = reminders.store.Put(scheduler.Job{ID: id, Kind: ReminderJob, RunAt: runAt, Payload: payload})
+		} else {
+			err = reminders.store.Delete(id)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (reminders *Reminders) unschedule(bookingId int) error {
+	for _, offset := range ReminderOffsets {
+		if err := reminders.store.Delete(reminderID(bookingId, offset)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reminderID is e.g. "reminder-7-24h", so that rescheduling replaces the job.
+func reminderID(bookingId int, offset time.Duration) string {
+	return "reminder-" + strconv.Itoa(bookingId) + "-" + strconv.Itoa(int(offset.Hours())) + "h"
+}
+
+/**
+ * @brief Remind is the scheduler handler emailing a reminder through the Default notifier.
+ */
+func Remind(job scheduler.Job) error {
+	if Default == nil {
+		return nil
+	}
+	return Default.Remind(job)
+}
+
+/**
+ * @brief Remind emails the reminder of a job, unless the booking is gone or no longer expected, or its session has started.
+ */
+func (notifier *Notifier) Remind(job scheduler.Job) error {
+	var payload reminderPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return err
+	}
+
+	found, _ := database.FindItemByID(database.Bookings, payload.BookingId)
+	if found == nil {
+		return nil
+	}
+	booking := *found.(*models.Booking)
+	if !expectsReminder(booking) {
+		return nil
+	}
+
+	class, _ := database.FindItemByID(database.Classes, booking.ClassId)
+	if class == nil || !booking.Date.After(time.Now()) {
+		return nil
+	}
+	return notifier.send(Reminder, booking, class.(*models.Class))
+}
+
+// expectsReminder reports whether the member of a booking is expected in class: pending and confirmed bookings that are not deleted.
+func expectsReminder(booking models.Booking) bool {
+	return (booking.Status == models.BookingPending || booking.Status == models.BookingConfirmed) && booking.DeletedAt == nil
+}
\ No newline at end of file
@@ -0,0 +1,115 @@
+package notifications
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+	"go-api/pkg/events"
+	"go-api/pkg/models"
+	"go-api/pkg/mockDatabase"
+	"go-api/pkg/notifications/smtptest"
+	"go-api/pkg/scheduler"
+	"github.com/stretchr/testify/assert"
+)
+
+func classEvent(id int64, eventType string, class models.Class) models.DomainEvent {
+	data, _ := json.Marshal(class)
+	return models.DomainEvent{ID: id, Type: eventType, AggregateType: "class", AggregateID: class.ID, Data: data}
+}
+
+func TestRemindersFollowTheBooking(t *testing.T) {
+	store := scheduler.NewMemoryStore()
+	reminders := NewReminders(store)
+	reminders.now = func() time.Time { return time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC) }
+
+	// Booking 1 is in the Yoga session of 2023-10-06 16:00 UTC
+	assert.NoError(t, reminders.Handle(bookingEvent(1, events.BookingCreated, database.Bookings[0])))
+
+	due, _ := store.Due(time.Date(2023, 10, 7, 0, 0, 0, 0, time.UTC))
+	if assert.Len(t, due, 2) {
+		assert.Equal(t, "reminder-1-24h", due[0].ID)
+		assert.Equal(t, time.Date(2023, 10, 5, 16, 0, 0, 0, time.UTC), due[0].RunAt)
+		assert.Equal(t, time.Date(2023, 10, 6, 15, 0, 0, 0, time.UTC), due[1].RunAt)
+	}
+
+	// Moving the booking to a later session of the class moves its reminders
+	moved := database.Bookings[0]
+	moved.Date = time.Date(2023, 10, 13, 16, 0, 0, 0, time.UTC)
+	assert.NoError(t, reminders.Handle(bookingEvent(2, events.BookingUpdated, moved)))
+
+	due, _ = store.Due(time.Date(2023, 10, 7, 0, 0, 0, 0, time.UTC))
+	assert.Empty(t, due)
+	due, _ = store.Due(time.Date(2023, 10, 14, 0, 0, 0, 0, time.UTC))
+	if assert.Len(t, due, 2) {
+		assert.Equal(t, time.Date(2023, 10, 12, 16, 0, 0, 0, time.UTC), due[0].RunAt)
+	}
+
+	// Updating the class keeps the reminders on the booked session
+	database.Bookings[0] = moved
+	defer func() { database.Bookings[0].Date = time.Date(2023, 10, 6, 16, 0, 0, 0, time.UTC) }()
+	assert.NoError(t, reminders.Handle(classEvent(3, events.ClassUpdated, database.Classes[0])))
+	due, _ = store.Due(time.Date(2023, 10, 14, 0, 0, 0, 0, time.UTC))
+	if assert.Len(t, due, 2) {
+		assert.Equal(t, time.Date(2023, 10, 12, 16, 0, 0, 0, time.UTC), due[0].RunAt)
+	}
+
+	// Cancelling the booking drops them
+	assert.NoError(t, reminders.Handle(bookingEvent(4, events.BookingDeleted, moved)))
+	due, _ = store.Due(time.Date(2023, 10, 14, 0, 0, 0, 0, time.UTC))
+	assert.Empty(t, due)
+}
+
+func TestRemindersSkipWaitlistedBookings(t *testing.T) {
+	store := scheduler.NewMemoryStore()
+	reminders := NewReminders(store)
+	reminders.now = func() time.Time { return time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC) }
+
+	waitlisted := database.Bookings[0]
+	waitlisted.Status = models.BookingWaitlisted
+	assert.NoError(t, reminders.Handle(bookingEvent(1, events.BookingCreated, waitlisted)))
+
+	due, _ := store.Due(time.Date(2023, 10, 7, 0, 0, 0, 0, time.UTC))
+	assert.Empty(t, due)
+
+	// Promotion schedules them
+	promoted := waitlisted
+	promoted.Status = models.BookingConfirmed
+	assert.NoError(t, reminders.Handle(bookingEvent(2, events.BookingPromoted, promoted)))
+
+	due, _ = store.Due(time.Date(2023, 10, 7, 0, 0, 0, 0, time.UTC))
+	assert.Len(t, due, 2)
+}
+
+func TestRemindEmailsTheMember(t *testing.T) {
+	server := smtptest.NewServer()
+	defer server.Close()
+	notifier := newNotifier(server)
+
+	// A class starting tomorrow, booked by Diego
+	class := models.Class{ID: 90, Name: "Spin", StudioId: 1, StartDate: time.Now().Add(24 * time.Hour), EndDate: time.Now().Add(25 * time.Hour), Capacity: 10, Version: 1}
+	booking := models.Booking{ID: 90, Name: "Diego", ClassId: 90, Date: class.StartDate, Version: 1, Status: models.BookingConfirmed}
+	database.Classes = append(database.Classes, class)
+	database.Bookings = append(database.Bookings, booking)
+	defer func() {
+		database.Classes = database.Classes[:len(database.Classes)-1]
+		database.Bookings = database.Bookings[:len(database.Bookings)-1]
+	}()
+
+	assert.NoError(t, notifier.Remind(scheduler.Job{ID: "reminder-90-24h", Kind: ReminderJob, Payload: json.RawMessage(`{"booking_id": 90}`)}))
+
+	messages := server.Messages()
+	if assert.Len(t, messages, 1) {
+		email := parse(t, messages[0])
+		assert.Equal(t, []string{"diego@example.com"}, email.to)
+		assert.Contains(t, email.subject, "Reminder: Spin")
+	}
+
+	// Reminders of sessions that have started are skipped
+	database.Bookings[len(database.Bookings)-1].Date = time.Now().Add(-time.Minute)
+	assert.NoError(t, notifier.Remind(scheduler.Job{ID: "reminder-90-1h", Kind: ReminderJob, Payload: json.RawMessage(`{"booking_id": 90}`)}))
+	assert.Len(t, server.Messages(), 1)
+
+	// Reminders of cancelled bookings are skipped
+	assert.NoError(t, notifier.Remind(scheduler.Job{ID: "reminder-99-1h", Kind: ReminderJob, Payload: json.RawMessage(`{"booking_id": 99}`)}))
+	assert.Len(t, server.Messages(), 1)
+}
\ No newline at end of file
@@ -0,0 +1,143 @@
+package smtptest
+
+import (
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+)
+
+// Message is an email received by the Server.
+type Message struct {
+	From string
+	To   []string
+	Data []byte
+}
+
+// Server is a local SMTP server for tests, in the spirit of httptest.Server.
+// It accepts every message and keeps it in memory.
+type Server struct {
+	// Addr is the host:port the server listens on.
+	Addr string
+
+	listener net.Listener
+	mu       sync.Mutex
+	messages []Message
+	failing  bool
+}
+
+/**
+ * @brief NewServer starts a server on a random local port. Call Close when done.
+ */
+func NewServer() *Server {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic("smtptest: " + err.Error())
+	}
+
+	server := &Server{Addr: listener.Addr().String(), listener: listener}
+	go server.accept()
+	return server
+}
+
+/**
+ * @brief Messages returns the messages received so far.
+ */
+func (server *Server) Messages() []Message {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	return append([]Message(nil), server.messages...)
+}
+
+/**
+ * @brief SetFailing makes the server reject messages with a permanent error, until called with false.
+ */
+func (server *Server) SetFailing(failing bool) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	server.failing = failing
+}
+
+/**
+ * @brief Close stops accepting connections.
+ */
+func (server *Server) Close() {
+	server.listener.Close()
+}
+
+func (server *Server) accept() {
+	for {
+		conn, err := server.listener.Accept()
+		if err != nil {
+			return
+		}
+		go server.serve(conn)
+	}
+}
+
+func (server *Server) serve(conn net.Conn) {
+	text := textproto.NewConn(conn)
+	defer text.Close()
+
+	text.PrintfLine("220 smtptest ESMTP")
+	var message Message
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			text.PrintfLine("250-smtptest")
+			text.PrintfLine("250 8BITMIME")
+		case "HELO", "NOOP":
+			text.PrintfLine("250 OK")
+		case "MAIL":
+			message = Message{From: address(arg)}
+			text.PrintfLine("250 OK")
+		case "RCPT":
+			message.To = append(message.To, address(arg))
+			text.PrintfLine("250 OK")
+		case "DATA":
+			text.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			data, err := text.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			message.Data = data
+
+			server.mu.Lock()
+			failing := server.failing
+			if !failing {
+				server.messages = append(server.messages, message)
+			}
+			server.mu.Unlock()
+
+			if failing {
+				text.PrintfLine("554 Transaction failed")
+			} else {
+				text.PrintfLine("250 OK")
+			}
+		case "RSET":
+			message = Message{}
+			text.PrintfLine("250 OK")
+		case "QUIT":
+			text.PrintfLine("221 Bye")
+			return
+		default:
+			text.PrintfLine("502 Command not implemented")
+		}
+	}
+}
+
+// address extracts the address of "FROM:<a@example.com> BODY=8BITMIME".
+func address(arg string) string {
+	start := strings.Index(arg, "<")
+	end := strings.Index(arg, ">")
+	if start < 0 || end < start {
+		return ""
+	}
+	return arg[start+1 : end]
+}
\ No newline at end of file
@@ -0,0 +1,151 @@
+package notifications
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"text/template"
+	"time"
+	"go-api/pkg/models"
+	"go-api/pkg/mockDatabase"
+)
+
+// Kinds of notification, each with its own template.
+const (
+	Confirmation = "confirmation"
+	Change       = "change"
+	Cancellation = "cancellation"
+	Promotion    = "promotion"
+	Reminder     = "reminder"
+)
+
+// Kinds lists the notification kinds in the order templates are listed.
+var Kinds = []string{Confirmation, Change, Cancellation, Promotion, Reminder}
+
+// Data is what notification templates can refer to, e.g. {{.Class.Name}}.
+type Data struct {
+	Member  models.Member
+	Booking models.Booking
+	Class   models.Class
+	Studio  models.Studio
+	// Start and End are the times of the booked session in the studio time zone.
+	Start time.Time
+	End   time.Time
+}
+
+const defaultHTMLFooter = `<p style="color:#888">{{.Studio.Name}}</p>`
+
+var defaultTemplates = map[string]models.EmailTemplate{
+	Confirmation: {
+		Subject: `Booking confirmed: {{.Class.Name}} on {{.Start.Format "Mon 2 Jan 15:04"}}`,
+		Text:    "Hi {{.Member.Name}},\n\nYou are booked for {{.Class.Name}} at {{.Studio.Name}} on {{.Start.Format \"Monday 2 January, 15:04\"}}.\n\nSee you there!\n",
+		HTML:    `<p>Hi {{.Member.Name}},</p><p>You are booked for <strong>{{.Class.Name}}</strong> at {{.Studio.Name}} on {{.Start.Format "Monday 2 January, 15:04"}}.</p><p>See you there!</p>` + defaultHTMLFooter,
+	},
+	Change: {
+		Subject: `Your class has changed: {{.Class.Name}} on {{.Start.Format "Mon 2 Jan 15:04"}}`,
+		Text:    "Hi {{.Member.Name}},\n\nYour booking for {{.Class.Name}} at {{.Studio.Name}} has changed. The class now runs on {{.Start.Format \"Monday 2 January, 15:04\"}} until {{.End.Format \"15:04\"}}.\n",
+		HTML:    `<p>Hi {{.Member.Name}},</p><p>Your booking for <strong>{{.Class.Name}}</strong> at {{.Studio.Name}} has changed. The class now runs on {{.Start.Format "Monday 2 January, 15:04"}} until {{.End.Format "15:04"}}.</p>` + defaultHTMLFooter,
+	},
+	Cancellation: {
+		Subject: `Booking cancelled: {{.Class.Name}} on {{.Start.Format "Mon 2 Jan 15:04"}}`,
+		Text:    "Hi {{.Member.Name}},\n\nYour booking for {{.Class.Name}} at {{.Studio.Name}} on {{.Start.Format \"Monday 2 January, 15:04\"}} has been cancelled.\n",
+		HTML:    `<p>Hi {{.Member.Name}},</p><p>Your booking for <strong>{{.Class.Name}}</strong> at {{.Studio.Name}} on {{.Start.Format "Monday 2 January, 15:04"}} has been cancelled.</p>` + defaultHTMLFooter,
+	},
+	Promotion: {
+		Subject: `A spot opened up: {{.Class.Name}} on {{.Start.Format "Mon 2 Jan 15:04"}}`,
+		Text:    "Hi {{.Member.Name}},\n\nGood news: a spot opened up and you moved from the waitlist to {{.Class.Name}} at {{.Studio.Name}} on {{.Start.Format \"Monday 2 January, 15:04\"}}.\n",
+		HTML:    `<p>Hi {{.Member.Name}},</p><p>Good news: a spot opened up and you moved from the waitlist to <strong>{{.Class.Name}}</strong> at {{.Studio.Name}} on {{.Start.Format "Monday 2 January, 15:04"}}.</p>` + defaultHTMLFooter,
+	},
+	Reminder: {
+		Subject: `Reminder: {{.Class.Name}} on {{.Start.Format "Mon 2 Jan 15:04"}}`,
+		Text:    "Hi {{.Member.Name}},\n\nThis is a reminder that {{.Class.Name}} at {{.Studio.Name}} starts on {{.Start.Format \"Monday 2 January, 15:04\"}}.\n\nSee you there!\n",
+		HTML:    `<p>Hi {{.Member.Name}},</p><p>This is a reminder that <strong>{{.Class.Name}}</strong> at {{.Studio.Name}} starts on {{.Start.Format "Monday 2 January, 15:04"}}.</p><p>See you there!</p>` + defaultHTMLFooter,
+	},
+}
+
+/**
+ * @brief Template returns the template a studio uses for a kind: its override, or the default.
+ *
+ * @return bool: false when the kind is unknown.
+ */
+func Template(studioId int, kind string) (models.EmailTemplate, bool) {
+	for _, override := range database.EmailTemplates {
+		if override.StudioId == studioId && override.Kind == kind {
+			return override, true
+		}
+	}
+
+	fallback, ok := defaultTemplates[kind]
+	fallback.StudioId = studioId
+	fallback.Kind = kind
+	fallback.Default = true
+	return fallback, ok
+}
+
+/**
+ * @brief Templates returns the template of every kind for a studio.
+ */
+func Templates(studioId int) []models.EmailTemplate {
+	templates := make([]models.EmailTemplate, 0, len(Kinds))
+	for _, kind := range Kinds {
+		item, _ := Template(studioId, kind)
+		templates = append(templates, item)
+	}
+	return templates
+}
+
+/**
+ * @brief Render executes a template, HTML-escaping the values in the HTML part.
+ *
+ * @return Message: The message, without recipient.
+ */
+func Render(source models.EmailTemplate, data Data) (Message, error) {
+	var message Message
+	var buffer bytes.Buffer
+
+	subject, err := template.New("subject").Parse(source.Subject)
+	if err != nil {
+		return message, err
+	}
+	if err := subject.Execute(&buffer, data); err != nil {
+		return message, err
+	}
+	message.Subject = buffer.String()
+
+	buffer.Reset()
+	text, err := template.New("text").Parse(source.Text)
+	if err != nil {
+		return message, err
+	}
+	if err := text.Execute(&buffer, data); err != nil {
+		return message, err
+	}
+	message.Text = buffer.String()
+
+	buffer.Reset()
+	html, err := htmltemplate.New("html").Parse(source.HTML)
+	if err != nil {
+		return message, err
+	}
+	if err := html.Execute(&buffer, data); err != nil {
+		return message, err
+	}
+	message.HTML = buffer.String()
+
+	return message, nil
+}
+
+/**
+ * @brief Check renders a template with sample data, to reject broken overrides before they are stored.
+ */
+func Check(source models.EmailTemplate, studio models.Studio) error {
+	start := time.Now().In(studio.Location()).Truncate(time.Hour).Add(24 * time.Hour)
+	_, err := Render(source, Data{
+		Member:  models.Member{ID: 1, Name: "Sample", Email: "sample@example.com"},
+		Booking: models.Booking{ID: 1, Name: "Sample", ClassId: 1, Date: start, Version: 1},
+		Class:   models.Class{ID: 1, Name: "Sample", StudioId: studio.ID, StartDate: start, EndDate: start.Add(time.Hour), Capacity: 10, Version: 1},
+		Studio:  studio,
+		Start:   start,
+		End:     start.Add(time.Hour),
+	})
+	return err
+}
\ No newline at end of file
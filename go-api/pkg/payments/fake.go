@@ -0,0 +1,166 @@
+package payments
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// FakeSignatureHeader carries the HMAC-SHA256 of the events sent by Fake.
+const FakeSignatureHeader = "X-Fake-Signature"
+
+// Fake is an in-memory Provider for tests and local development.
+//
+// Nothing is charged: Complete settles an intent and returns the signed event
+// a real provider would post to the payment webhook.
+type Fake struct {
+	secret []byte
+
+	mu       sync.Mutex
+	intents  map[string]*Intent
+	refunded map[string]int
+}
+
+// intentIDs numbers the intents of every Fake, so that their IDs are unique in the process, as a real provider's are.
+var intentIDs atomic.Int64
+
+/**
+ * @brief NewFake returns a fake provider.
+ *
+ * @param secret []byte: The key events are signed with; a random one is generated when empty.
+ */
+func NewFake(secret []byte) *Fake {
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			panic(err)
+		}
+	}
+	return &Fake{secret: secret, intents: map[string]*Intent{}, refunded: map[string]int{}}
+}
+
+/**
+ * @brief CreateIntent records a new intent waiting for payment.
+ */
+func (fake *Fake) CreateIntent(ctx context.Context, amount int, currency string, description string) (Intent, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	id := "pi_fake_" + strconv.FormatInt(intentIDs.Add(1), 10)
+	intent := &Intent{ID: id, ClientSecret: id + "_secret", Amount: amount, Currency: currency, Status: StatusRequiresPayment}
+	fake.intents[id] = intent
+	return *intent, nil
+}
+
+/**
+ * @brief Cancel abandons an intent that has not been paid.
+ */
+func (fake *Fake) Cancel(ctx context.Context, intentId string) error {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	intent, ok := fake.intents[intentId]
+	if !ok {
+		return ErrIntentNotFound
+	}
+	if intent.Status != StatusRequiresPayment {
+		return ErrCancel
+	}
+	intent.Status = StatusCancelled
+	return nil
+}
+
+/**
+ * @brief Refund records a refund of a succeeded intent, up to the amount paid.
+ */
+func (fake *Fake) Refund(ctx context.Context, intentId string, amount int) error {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	intent, ok := fake.intents[intentId]
+	if !ok {
+		return ErrIntentNotFound
+	}
+	if intent.Status != StatusSucceeded || fake.refunded[intentId]+amount > intent.Amount {
+		return ErrRefund
+	}
+	fake.refunded[intentId] += amount
+	return nil
+}
+
+/**
+ * @brief ParseEvent decodes an event returned by Complete, checking its signature.
+ */
+func (fake *Fake) ParseEvent(payload []byte, header http.Header) (Event, error) {
+	if !hmac.Equal([]byte(header.Get(FakeSignatureHeader)), []byte(fake.sign(payload))) {
+		return Event{}, ErrInvalidSignature
+	}
+
+	var event struct {
+		Type     string `json:"type"`
+		IntentId string `json:"intent_id"`
+	}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return Event{}, err
+	}
+	return Event{Type: event.Type, IntentId: event.IntentId}, nil
+}
+
+/**
+ * @brief Complete settles an intent as paid or failed.
+ *
+ * @param intentId string: The intent returned by CreateIntent.
+ * @param succeeded bool: Whether the payment went through.
+ * @return []byte: The event body to post to the payment webhook.
+ * @return http.Header: Its headers, with the signature.
+ */
+func (fake *Fake) Complete(intentId string, succeeded bool) ([]byte, http.Header, error) {
+	fake.mu.Lock()
+	intent, ok := fake.intents[intentId]
+	if !ok {
+		fake.mu.Unlock()
+		return nil, nil, ErrIntentNotFound
+	}
+	if intent.Status == StatusCancelled {
+		fake.mu.Unlock()
+		return nil, nil, ErrCancel
+	}
+	event := EventFailed
+	intent.Status = StatusFailed
+	if succeeded {
+		event = EventSucceeded
+		intent.Status = StatusSucceeded
+	}
+	fake.mu.Unlock()
+
+	payload, err := json.Marshal(map[string]string{"type": event, "intent_id": intentId})
+	if err != nil {
+		return nil, nil, err
+	}
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set(FakeSignatureHeader, fake.sign(payload))
+	return payload, header, nil
+}
+
+/**
+ * @brief Refunded returns how much of a payment has been refunded, in cents.
+ */
+func (fake *Fake) Refunded(intentId string) int {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	return fake.refunded[intentId]
+}
+
+func (fake *Fake) sign(payload []byte) string {
+	mac := hmac.New(sha256.New, fake.secret)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
\ No newline at end of file
@@ -0,0 +1,47 @@
+package payments
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFake(t *testing.T) {
+	ctx := context.Background()
+	fake := NewFake([]byte("secret"))
+
+	intent, err := fake.CreateIntent(ctx, 1500, "usd", "Yoga")
+	assert.NoError(t, err)
+	assert.Equal(t, StatusRequiresPayment, intent.Status)
+	assert.NotEmpty(t, intent.ClientSecret)
+
+	// Unpaid intents cannot be refunded
+	assert.ErrorIs(t, fake.Refund(ctx, intent.ID, 1500), ErrRefund)
+
+	body, header, err := fake.Complete(intent.ID, true)
+	assert.NoError(t, err)
+	event, err := fake.ParseEvent(body, header)
+	assert.NoError(t, err)
+	assert.Equal(t, Event{Type: EventSucceeded, IntentId: intent.ID}, event)
+
+	// Events signed with another key, or not at all, are rejected
+	_, err = NewFake([]byte("other")).ParseEvent(body, header)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+	_, err = fake.ParseEvent(body, http.Header{})
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	// Refunds add up to at most the amount paid
+	assert.NoError(t, fake.Refund(ctx, intent.ID, 1000))
+	assert.ErrorIs(t, fake.Refund(ctx, intent.ID, 1000), ErrRefund)
+	assert.NoError(t, fake.Refund(ctx, intent.ID, 500))
+	assert.Equal(t, 1500, fake.Refunded(intent.ID))
+	assert.ErrorIs(t, fake.Refund(ctx, "pi_unknown", 1), ErrIntentNotFound)
+
+	// Cancelled intents cannot be paid, paid ones cannot be cancelled
+	assert.ErrorIs(t, fake.Cancel(ctx, intent.ID), ErrCancel)
+	cancelled, _ := fake.CreateIntent(ctx, 1500, "usd", "Yoga")
+	assert.NoError(t, fake.Cancel(ctx, cancelled.ID))
+	_, _, err = fake.Complete(cancelled.ID, true)
+	assert.ErrorIs(t, err, ErrCancel)
+}
\ No newline at end of file
@@ -0,0 +1,68 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// Payment intent statuses.
+const (
+	StatusRequiresPayment = "requires_payment"
+	StatusSucceeded       = "succeeded"
+	StatusFailed          = "failed"
+	StatusCancelled       = "cancelled"
+	StatusRefunded        = "refunded"
+	// StatusRefunding marks a payment claimed by the request refunding it, until the provider answers.
+	StatusRefunding = "refunding"
+)
+
+// Types of the events providers send when a payment completes.
+const (
+	EventSucceeded = "payment.succeeded"
+	EventFailed    = "payment.failed"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid payment event signature")
+	ErrIntentNotFound   = errors.New("payment intent not found")
+	ErrRefund           = errors.New("refund exceeds the amount paid")
+	ErrCancel           = errors.New("payment intent is no longer waiting for payment")
+)
+
+// Intent is a payment the customer has to complete with the provider.
+type Intent struct {
+	ID string
+	// ClientSecret lets the client complete the payment with the provider.
+	ClientSecret string
+	Amount       int
+	Currency     string
+	Status       string
+}
+
+// Event tells that the payment of an intent succeeded or failed.
+type Event struct {
+	Type     string
+	IntentId string
+}
+
+// Provider takes payments through a payment service.
+//
+// Payments complete asynchronously: the provider reports the outcome of an
+// intent with a signed event posted to the payment webhook endpoint.
+type Provider interface {
+	// CreateIntent starts a payment of amount, in cents of currency.
+	CreateIntent(ctx context.Context, amount int, currency string, description string) (Intent, error)
+	// Cancel abandons an intent still waiting for payment.
+	Cancel(ctx context.Context, intentId string) error
+	// Refund gives back part or all of a succeeded payment, in cents.
+	Refund(ctx context.Context, intentId string, amount int) error
+	// ParseEvent verifies the signature of a webhook request and decodes its event.
+	ParseEvent(payload []byte, header http.Header) (Event, error)
+}
+
+// Default is the provider bookings of priced classes are paid through.
+var Default Provider = NewFake(nil)
+
+// Currency is the ISO 4217 code of class prices.
+var Currency = "usd"
\ No newline at end of file
@@ -0,0 +1,131 @@
+package retention
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+	"go-api/pkg/models"
+	"go-api/pkg/mockDatabase"
+)
+
+// Audit actions of the records removed by Purge.
+const (
+	ClassPurged   = "class.purged"
+	BookingPurged = "booking.purged"
+)
+
+// Audit records the removal of a class or booking in the transaction purging it, e.g. in the audit log.
+type Audit func(tx *database.Tx, action string, id int, before interface{}, after interface{}) error
+
+// Policy decides when soft-deleted classes and bookings are removed for good.
+type Policy struct {
+	// PurgeAfter is how long a soft-deleted record can still be restored; zero keeps them forever.
+	PurgeAfter time.Duration `json:"purge_after"`
+	// Interval is how often the purge job runs.
+	Interval time.Duration `json:"interval"`
+}
+
+/**
+ * @brief DefaultPolicy keeps soft-deleted records for 30 days, checked every hour.
+ */
+func DefaultPolicy() Policy {
+	return Policy{PurgeAfter: 30 * 24 * time.Hour, Interval: time.Hour}
+}
+
+// UnmarshalJSON accepts the durations as Go duration strings such as "720h".
+func (policy *Policy) UnmarshalJSON(data []byte) error {
+	raw := struct {
+		PurgeAfter string `json:"purge_after"`
+		Interval   string `json:"interval"`
+	}{PurgeAfter: policy.PurgeAfter.String(), Interval: policy.Interval.String()}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	purgeAfter, err := time.ParseDuration(raw.PurgeAfter)
+	if err != nil {
+		return err
+	}
+	interval, err := time.ParseDuration(raw.Interval)
+	if err != nil {
+		return err
+	}
+
+	policy.PurgeAfter = purgeAfter
+	policy.Interval = interval
+	return nil
+}
+
+/**
+ * @brief Purge removes the classes and bookings soft-deleted before a given time.
+ *
+ * @param before time.Time: Records deleted before this time are removed.
+ * @param audit Audit: Records each removal as ClassPurged or BookingPurged; nil records nothing.
+ * @return int: The number of classes and bookings removed.
+ */
+func Purge(before time.Time, audit Audit) (int, error) {
+	purged := 0
+	err := database.Transaction(func(tx *database.Tx) error {
+		classes := make([]models.Class, 0, len(database.Classes))
+		for _, class := range database.Classes {
+			if expired(class.DeletedAt, before) {
+				if audit != nil {
+					if err := audit(tx, ClassPurged, class.ID, class, nil); err != nil {
+						return err
+					}
+				}
+				purged++
+				continue
+			}
+			classes = append(classes, class)
+		}
+
+		bookings := make([]models.Booking, 0, len(database.Bookings))
+		for _, booking := range database.Bookings {
+			if expired(booking.DeletedAt, before) {
+				if audit != nil {
+					if err := audit(tx, BookingPurged, booking.ID, booking, nil); err != nil {
+						return err
+					}
+				}
+				purged++
+				continue
+			}
+			bookings = append(bookings, booking)
+		}
+		database.Classes = classes
+		database.Bookings = bookings
+		return nil
+	})
+	return purged, err
+}
+
+/**
+ * @brief Run purges the records older than the policy allows every policy interval until ctx is done.
+ *
+ * @param audit Audit: Records each removal, passed to Purge.
+ */
+func Run(ctx context.Context, policy Policy, audit Audit) {
+	if policy.PurgeAfter <= 0 || policy.Interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(policy.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if _, err := Purge(time.Now().Add(-policy.PurgeAfter), audit); err != nil {
+			log.Println("retention: purging deleted records:", err)
+		}
+	}
+}
+
+func expired(deletedAt *time.Time, before time.Time) bool {
+	return deletedAt != nil && deletedAt.Before(before)
+}
\ No newline at end of file
@@ -0,0 +1,58 @@
+package retention
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+	"go-api/pkg/models"
+	"go-api/pkg/mockDatabase"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPurge(t *testing.T) {
+	start := time.Date(2024, 6, 1, 18, 0, 0, 0, time.UTC)
+	old := start.Add(-60 * 24 * time.Hour)
+	recent := start.Add(-time.Hour)
+
+	expired := database.CreateClass(models.CreateClass{Name: "Old", StudioId: 1, StartDate: start, EndDate: start.Add(time.Hour), Capacity: 10})
+	expired.DeletedAt = &old
+	kept := database.CreateClass(models.CreateClass{Name: "Recent", StudioId: 1, StartDate: start, EndDate: start.Add(time.Hour), Capacity: 10})
+	kept.DeletedAt = &recent
+	live := database.CreateClass(models.CreateClass{Name: "Live", StudioId: 1, StartDate: start, EndDate: start.Add(time.Hour), Capacity: 10})
+	database.Classes = append(database.Classes, expired, kept, live)
+
+	booking := database.CreateBooking(models.CreateBooking{Name: "Diego", ClassId: expired.ID, Date: start})
+	booking.DeletedAt = &old
+	database.Bookings = append(database.Bookings, booking)
+
+	var audited []string
+	audit := func(tx *database.Tx, action string, id int, before interface{}, after interface{}) error {
+		audited = append(audited, action)
+		assert.Nil(t, after)
+		return nil
+	}
+
+	purged, err := Purge(start.Add(-30 * 24 * time.Hour), audit)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, purged)
+	assert.Contains(t, audited, ClassPurged)
+	assert.Contains(t, audited, BookingPurged)
+
+	found, _ := database.FindAnyItemByID(database.Classes, expired.ID)
+	assert.Nil(t, found)
+	found, _ = database.FindAnyItemByID(database.Bookings, booking.ID)
+	assert.Nil(t, found)
+	found, _ = database.FindAnyItemByID(database.Classes, kept.ID)
+	assert.NotNil(t, found)
+	found, _ = database.FindItemByID(database.Classes, live.ID)
+	assert.NotNil(t, found)
+}
+
+func TestPolicyUnmarshal(t *testing.T) {
+	policy := DefaultPolicy()
+	assert.NoError(t, json.Unmarshal([]byte(`{"purge_after": "168h"}`), &policy))
+	assert.Equal(t, 7*24*time.Hour, policy.PurgeAfter)
+	assert.Equal(t, time.Hour, policy.Interval)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"interval": "soon"}`), &policy))
+}
\ No newline at end of file
@@ -0,0 +1,54 @@
+//go:build !unix
+
+package scheduler
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// staleLock is the age after which a lock file left by a crashed process is taken over.
+const staleLock = 10 * time.Second
+
+/**
+ * @brief lock creates dir/jobs.lock exclusively, on platforms without flock.
+ *
+ * A stale lock is taken over by renaming it away, which only one instance
+ * can do; the renamed file is checked again so that a lock created in the
+ * meantime by another instance is put back instead of being removed.
+ *
+ * @return func(): Releases the lock.
+ */
+func (store *FileStore) lock() (func(), error) {
+	path := filepath.Join(store.dir, "jobs.lock")
+	deadline := time.Now().Add(lockTimeout)
+
+	for {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			file.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > staleLock {
+			taken := path + "." + strconv.FormatInt(time.Now().UnixNano(), 36)
+			if os.Rename(path, taken) == nil {
+				if info, err := os.Stat(taken); err == nil && time.Since(info.ModTime()) > staleLock {
+					os.Remove(taken)
+				} else if os.Link(taken, path) == nil {
+					os.Remove(taken)
+				}
+			}
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, errLockTimeout
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,45 @@
+//go:build unix
+
+package scheduler
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+/**
+ * @brief lock takes an exclusive flock on dir/jobs.lock.
+ *
+ * The kernel drops the lock when its process dies, so a crashed instance
+ * never leaves a stale lock behind for the others to take over.
+ *
+ * @return func(): Releases the lock.
+ */
+func (store *FileStore) lock() (func(), error) {
+	file, err := os.OpenFile(filepath.Join(store.dir, "jobs.lock"), os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(lockTimeout)
+
+	for {
+		err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+		if err == nil {
+			return func() {
+				syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+				file.Close()
+			}, nil
+		}
+		if err != syscall.EWOULDBLOCK && err != syscall.EINTR {
+			file.Close()
+			return nil, err
+		}
+		if time.Now().After(deadline) {
+			file.Close()
+			return nil, errLockTimeout
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,130 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Handler runs a job. Returning an error retries it later, up to MaxAttempts.
+type Handler func(job Job) error
+
+// Scheduler runs the due jobs of a Store. Several schedulers, in several processes, can
+// share a store: each job is claimed before it runs, so it runs once.
+type Scheduler struct {
+	store    Store
+	owner    string
+	handlers map[string]Handler
+
+	// Lease is how long a claimed job is reserved; it must exceed the time a handler takes.
+	Lease time.Duration
+	// MaxAttempts is how many times a failing job runs before it is dropped.
+	MaxAttempts int
+	// Backoff is the wait before the first retry, doubled after each failure.
+	Backoff time.Duration
+}
+
+/**
+ * @brief New returns a scheduler for store.
+ *
+ * @param owner string: Identifies this scheduler in leases, e.g. host name and process ID.
+ */
+func New(store Store, owner string) *Scheduler {
+	return &Scheduler{
+		store:       store,
+		owner:       owner,
+		handlers:    map[string]Handler{},
+		Lease:       time.Minute,
+		MaxAttempts: 5,
+		Backoff:     time.Minute,
+	}
+}
+
+/**
+ * @brief Handle registers the handler of a job kind.
+ */
+func (scheduler *Scheduler) Handle(kind string, handler Handler) {
+	scheduler.handlers[kind] = handler
+}
+
+/**
+ * @brief RunDue runs the jobs due at now once. Leases are taken on the wall clock.
+ *
+ * @return int: The number of jobs that ran successfully.
+ */
+func (scheduler *Scheduler) RunDue(now time.Time) int {
+	due, err := scheduler.store.Due(now)
+	if err != nil {
+		log.Println("scheduler:", err)
+		return 0
+	}
+
+	ran := 0
+	for _, job := range due {
+		claimed, err := scheduler.store.Claim(job.ID, scheduler.owner, time.Now().Add(scheduler.Lease))
+		if err != nil {
+			log.Println("scheduler:", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		if scheduler.run(job, now) {
+			ran++
+		}
+	}
+	return ran
+}
+
+func (scheduler *Scheduler) run(job Job, now time.Time) bool {
+	handler, ok := scheduler.handlers[job.Kind]
+	if !ok {
+		log.Printf("scheduler: no handler for job %s of kind %q, dropping it", job.ID, job.Kind)
+		scheduler.finish(job, nil)
+		return false
+	}
+
+	err := handler(job)
+	if err == nil {
+		scheduler.finish(job, nil)
+		return true
+	}
+
+	retry := job
+	retry.Attempts++
+	if retry.Attempts >= scheduler.MaxAttempts {
+		log.Printf("scheduler: job %s failed %d times, dropping it: %v", job.ID, retry.Attempts, err)
+		scheduler.finish(job, nil)
+		return false
+	}
+
+	log.Printf("scheduler: job %s failed, retrying: %v", job.ID, err)
+	retry.RunAt = now.Add(scheduler.Backoff << (retry.Attempts - 1))
+	scheduler.finish(job, &retry)
+	return false
+}
+
+// finish ends a run, keeping the job if it was rescheduled while it ran.
+func (scheduler *Scheduler) finish(job Job, retry *Job) {
+	if err := scheduler.store.Finish(job, scheduler.owner, retry); err != nil {
+		log.Println("scheduler:", err)
+	}
+}
+
+/**
+ * @brief Run runs the due jobs every interval until ctx is done.
+ */
+func (scheduler *Scheduler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		scheduler.RunDue(time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
\ No newline at end of file
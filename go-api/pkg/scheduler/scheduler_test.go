@@ -0,0 +1,128 @@
+package scheduler
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	"github.com/stretchr/testify/assert"
+)
+
+var start = time.Date(2023, 10, 6, 16, 0, 0, 0, time.UTC)
+
+func TestFileStoreSurvivesRestarts(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, store.Put(Job{ID: "a", Kind: "test", RunAt: start}))
+	assert.NoError(t, store.Put(Job{ID: "b", Kind: "test", RunAt: start.Add(time.Hour)}))
+
+	// A new store on the same directory, as after a restart, sees the pending jobs
+	restarted, _ := NewFileStore(dir)
+	due, err := restarted.Due(start.Add(time.Hour))
+	assert.NoError(t, err)
+	if assert.Len(t, due, 2) {
+		assert.Equal(t, "a", due[0].ID)
+	}
+
+	// Putting a job again replaces it
+	assert.NoError(t, restarted.Put(Job{ID: "a", Kind: "test", RunAt: start.Add(2 * time.Hour)}))
+	due, _ = store.Due(start.Add(time.Hour))
+	if assert.Len(t, due, 1) {
+		assert.Equal(t, "b", due[0].ID)
+	}
+}
+
+func TestJobsRunOnceAcrossInstances(t *testing.T) {
+	dir := t.TempDir()
+	seed, _ := NewFileStore(dir)
+	for _, id := range []string{"a", "b", "c", "d"} {
+		seed.Put(Job{ID: id, Kind: "test", RunAt: start})
+	}
+
+	// Two instances share the directory and run at the same time
+	var runs int32
+	var wg sync.WaitGroup
+	for _, owner := range []string{"one", "two"} {
+		store, _ := NewFileStore(dir)
+		scheduler := New(store, owner)
+		scheduler.Handle("test", func(job Job) error {
+			atomic.AddInt32(&runs, 1)
+			return nil
+		})
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			scheduler.RunDue(start)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(4), runs)
+	due, _ := seed.Due(start.Add(time.Hour))
+	assert.Empty(t, due)
+}
+
+func TestFailedJobsAreRetriedWithBackoff(t *testing.T) {
+	store := NewMemoryStore()
+	store.Put(Job{ID: "a", Kind: "test", RunAt: start})
+
+	scheduler := New(store, "one")
+	scheduler.MaxAttempts = 2
+	calls := 0
+	scheduler.Handle("test", func(job Job) error {
+		calls++
+		return errors.New("smtp down")
+	})
+
+	assert.Equal(t, 0, scheduler.RunDue(start))
+
+	// The retry waits for the backoff
+	due, _ := store.Due(start)
+	assert.Empty(t, due)
+	due, _ = store.Due(start.Add(time.Minute))
+	if assert.Len(t, due, 1) {
+		assert.Equal(t, 1, due[0].Attempts)
+	}
+
+	// After MaxAttempts the job is dropped
+	scheduler.RunDue(start.Add(time.Minute))
+	assert.Equal(t, 2, calls)
+	due, _ = store.Due(start.Add(time.Hour))
+	assert.Empty(t, due)
+}
+
+func TestClaimedJobsAreNotDue(t *testing.T) {
+	store := NewMemoryStore()
+	store.Put(Job{ID: "a", Kind: "test", RunAt: start})
+
+	claimed, _ := store.Claim("a", "one", time.Now().Add(time.Minute))
+	assert.True(t, claimed)
+	claimed, _ = store.Claim("a", "two", time.Now().Add(time.Minute))
+	assert.False(t, claimed)
+
+	due, _ := store.Due(start)
+	assert.Empty(t, due)
+}
+func TestJobRescheduledWhileRunningIsKept(t *testing.T) {
+	store := NewMemoryStore()
+	store.Put(Job{ID: "a", Kind: "test", RunAt: start})
+
+	// The job is put again, for later, while its handler runs
+	scheduler := New(store, "one")
+	scheduler.Handle("test", func(job Job) error {
+		return store.Put(Job{ID: "a", Kind: "test", RunAt: start.Add(time.Hour)})
+	})
+	assert.Equal(t, 1, scheduler.RunDue(start))
+
+	// The finished run does not delete the rescheduled job, nor keep its lease
+	due, _ := store.Due(start.Add(time.Hour))
+	if assert.Len(t, due, 1) {
+		assert.Equal(t, start.Add(time.Hour), due[0].RunAt)
+	}
+}
\ No newline at end of file
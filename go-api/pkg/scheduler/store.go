@@ -0,0 +1,248 @@
+package scheduler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Job is work to run at RunAt. Jobs are identified by ID, so scheduling a job again replaces it.
+type Job struct {
+	ID       string          `json:"id"`
+	Kind     string          `json:"kind"`
+	RunAt    time.Time       `json:"run_at"`
+	Payload  json.RawMessage `json:"payload,omitempty"`
+	Attempts int             `json:"attempts"`
+	// Revision changes every time the job is put, so that a run only finishes the job it claimed.
+	Revision string `json:"revision,omitempty"`
+}
+
+// Store persists the pending jobs. Claim makes sure a job runs on one scheduler at a time,
+// even when several server instances share the store.
+type Store interface {
+	// Put adds a job or replaces the job with the same ID.
+	Put(job Job) error
+	// Delete removes a job, if it exists.
+	Delete(id string) error
+	// Due returns the jobs whose RunAt has passed and that no other scheduler holds, oldest first.
+	Due(now time.Time) ([]Job, error)
+	// Claim leases a job to owner until the given time; false when another owner holds an unexpired lease.
+	Claim(id string, owner string, until time.Time) (bool, error)
+	// Release drops the lease of owner on a job.
+	Release(id string, owner string) error
+	// Finish ends the run of job by owner: the job is deleted, or replaced by retry when not
+	// nil. A job put again while it ran (another Revision) is kept and only its lease dropped.
+	Finish(job Job, owner string, retry *Job) error
+}
+
+// entry is a job with its lease.
+type entry struct {
+	Job
+	Owner      string    `json:"owner,omitempty"`
+	LeaseUntil time.Time `json:"lease_until,omitempty"`
+}
+
+// jobs is the content of a store, keyed by job ID.
+type jobs map[string]entry
+
+// due returns the jobs to run at now. Leases always expire by the wall clock.
+func (items jobs) due(now time.Time) []Job {
+	var due []Job
+	for _, item := range items {
+		if !item.RunAt.After(now) && !item.LeaseUntil.After(time.Now()) {
+			due = append(due, item.Job)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].RunAt.Before(due[j].RunAt) })
+	return due
+}
+
+func (items jobs) claim(id string, owner string, until time.Time) bool {
+	item, ok := items[id]
+	if !ok || (item.Owner != owner && item.LeaseUntil.After(time.Now())) {
+		return false
+	}
+	item.Owner = owner
+	item.LeaseUntil = until
+	items[id] = item
+	return true
+}
+
+func (items jobs) release(id string, owner string) {
+	if item, ok := items[id]; ok && item.Owner == owner {
+		item.Owner = ""
+		item.LeaseUntil = time.Time{}
+		items[id] = item
+	}
+}
+
+func (items jobs) put(job Job) {
+	// A replaced job keeps its lease, so rescheduling does not let it run twice.
+	item := items[job.ID]
+	item.Job = job
+	item.Revision = newRevision()
+	items[job.ID] = item
+}
+
+func (items jobs) finish(job Job, owner string, retry *Job) {
+	item, ok := items[job.ID]
+	switch {
+	case !ok:
+	case item.Revision != job.Revision:
+		items.release(job.ID, owner)
+	case retry == nil:
+		delete(items, job.ID)
+	default:
+		items.put(*retry)
+		items.release(job.ID, owner)
+	}
+}
+
+func newRevision() string {
+	data := make([]byte, 8)
+	rand.Read(data)
+	return hex.EncodeToString(data)
+}
+
+// MemoryStore keeps jobs in memory, for a single instance and for tests.
+type MemoryStore struct {
+	mu    sync.Mutex
+	items jobs
+}
+
+/**
+ * @brief NewMemoryStore returns an empty in-memory store.
+ */
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{items: jobs{}}
+}
+
+func (store *MemoryStore) Put(job Job) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.items.put(job)
+	return nil
+}
+
+func (store *MemoryStore) Delete(id string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	delete(store.items, id)
+	return nil
+}
+
+func (store *MemoryStore) Due(now time.Time) ([]Job, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.items.due(now), nil
+}
+
+func (store *MemoryStore) Claim(id string, owner string, until time.Time) (bool, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.items.claim(id, owner, until), nil
+}
+
+func (store *MemoryStore) Release(id string, owner string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.items.release(id, owner)
+	return nil
+}
+
+func (store *MemoryStore) Finish(job Job, owner string, retry *Job) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.items.finish(job, owner, retry)
+	return nil
+}
+
+// FileStore keeps jobs in a JSON file, so they survive restarts. Instances sharing the
+// directory serialize their access through a lock file (see lock).
+type FileStore struct {
+	dir string
+}
+
+// lockTimeout is how long update waits for the lock file.
+const lockTimeout = 20 * time.Second
+
+var errLockTimeout = errors.New("scheduler: timed out waiting for the store lock")
+
+/**
+ * @brief NewFileStore returns a store keeping its jobs in dir/jobs.json, creating dir if needed.
+ */
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+func (store *FileStore) Put(job Job) error {
+	return store.update(func(items jobs) { items.put(job) })
+}
+
+func (store *FileStore) Delete(id string) error {
+	return store.update(func(items jobs) { delete(items, id) })
+}
+
+func (store *FileStore) Due(now time.Time) ([]Job, error) {
+	var due []Job
+	err := store.update(func(items jobs) { due = items.due(now) })
+	return due, err
+}
+
+func (store *FileStore) Claim(id string, owner string, until time.Time) (bool, error) {
+	var claimed bool
+	err := store.update(func(items jobs) { claimed = items.claim(id, owner, until) })
+	return claimed, err
+}
+
+func (store *FileStore) Release(id string, owner string) error {
+	return store.update(func(items jobs) { items.release(id, owner) })
+}
+
+func (store *FileStore) Finish(job Job, owner string, retry *Job) error {
+	return store.update(func(items jobs) { items.finish(job, owner, retry) })
+}
+
+/**
+ * @brief update runs fn on the stored jobs holding the lock file, then writes them back atomically.
+ */
+func (store *FileStore) update(fn func(items jobs)) error {
+	unlock, err := store.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	path := filepath.Join(store.dir, "jobs.json")
+	items := jobs{}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &items); err != nil {
+			return err
+		}
+	}
+
+	fn(items)
+
+	data, err = json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return err
+	}
+	temp := path + ".tmp"
+	if err := os.WriteFile(temp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(temp, path)
+}
\ No newline at end of file
@@ -0,0 +1,99 @@
+package timezone
+
+import (
+	"errors"
+	"time"
+	_ "time/tzdata"
+)
+
+// LocalLayout is the layout used for wall-clock times sent without an offset.
+const LocalLayout = "2006-01-02T15:04:05"
+
+// Studio is the ?tz= value that renders times in each studio's own zone.
+const Studio = "studio"
+
+var ErrNonexistentTime = errors.New("local time does not exist in time zone")
+
+/**
+ * @brief Resolve returns the location requested through ?tz=.
+ *
+ * @param requested string: The requested zone, "studio" or an IANA name.
+ * @param studioZone string: The IANA zone of the studio owning the resource.
+ * @return *time.Location: nil when no conversion was requested.
+ */
+func Resolve(requested string, studioZone string) (*time.Location, error) {
+	switch requested {
+	case "":
+		return nil, nil
+	case Studio:
+		return time.LoadLocation(studioZone)
+	default:
+		return time.LoadLocation(requested)
+	}
+}
+
+/**
+ * @brief ParseLocal interprets a wall-clock time in the given location.
+ *
+ * Times skipped by a DST transition are rejected instead of being silently
+ * shifted; ambiguous times resolve to the first occurrence.
+ *
+ * @param value string: The wall-clock time formatted as LocalLayout.
+ * @param loc *time.Location: The zone the wall-clock time belongs to.
+ * @return time.Time: The instant, in UTC.
+ */
+func ParseLocal(value string, loc *time.Location) (time.Time, error) {
+	wall, err := time.Parse(LocalLayout, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return FromWallClock(wall, loc)
+}
+
+/**
+ * @brief FromWallClock converts the date and clock of wall, ignoring its zone, into an instant in loc.
+ *
+ * @param wall time.Time: The wall-clock date and time.
+ * @param loc *time.Location: The zone the wall-clock time belongs to.
+ * @return time.Time: The instant, in UTC.
+ */
+func FromWallClock(wall time.Time, loc *time.Location) (time.Time, error) {
+	instant := time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), 0, loc)
+
+	local := instant.In(loc)
+	if local.Hour() != wall.Hour() || local.Minute() != wall.Minute() || local.Day() != wall.Day() {
+		return time.Time{}, ErrNonexistentTime
+	}
+
+	// time.Date does not promise which side of a fall-back overlap it picks.
+	if earlier := instant.Add(-time.Hour); earlier.In(loc).Hour() == wall.Hour() && earlier.In(loc).Minute() == wall.Minute() && earlier.In(loc).Day() == wall.Day() {
+		instant = earlier
+	}
+
+	return instant.UTC(), nil
+}
+
+/**
+ * @brief Occurrences expands a recurring wall-clock schedule into instants.
+ *
+ * Each occurrence keeps the same local clock time, so a weekly 18:00 class
+ * stays at 18:00 across DST changes. Occurrences that fall in a DST gap are
+ * skipped.
+ *
+ * @param first time.Time: The wall-clock date and time of the first occurrence.
+ * @param loc *time.Location: The zone of the schedule.
+ * @param days int: Days between occurrences.
+ * @param count int: Number of occurrences.
+ * @return []time.Time: The occurrence instants, in UTC.
+ */
+func Occurrences(first time.Time, loc *time.Location, days int, count int) []time.Time {
+	var instants []time.Time
+	for i := 0; i < count; i++ {
+		instant, err := FromWallClock(first.AddDate(0, 0, i*days), loc)
+		if err != nil {
+			continue
+		}
+		instants = append(instants, instant)
+	}
+	return instants
+}
\ No newline at end of file
@@ -0,0 +1,370 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"go-api/pkg/models"
+	"go-api/pkg/mockDatabase"
+)
+
+// Headers sent with every delivery.
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// Delivery statuses.
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusDead      = "dead"
+)
+
+var (
+	ErrDeliveryNotFound = errors.New("delivery not found")
+	ErrDeliveryPending  = errors.New("delivery is still pending")
+	ErrDeliveryNotDead  = errors.New("delivery is not a dead letter")
+)
+
+// Dispatcher posts events to the subscribed webhooks, retrying failed
+// deliveries with exponential backoff. Deliveries that still fail after the
+// last attempt become dead letters until they are redelivered. The history is
+// kept in memory and only the last finished deliveries are remembered.
+type Dispatcher struct {
+	client      *http.Client
+	maxAttempts int
+	backoff     time.Duration
+	history     int
+
+	mu         sync.Mutex
+	deliveries []*delivery
+	nextID     int
+}
+
+type delivery struct {
+	models.WebhookDelivery
+	url    string
+	secret string
+	body   []byte
+}
+
+type Option func(*Dispatcher)
+
+/**
+ * @brief WithHTTPClient sets the client used to post events.
+ */
+func WithHTTPClient(client *http.Client) Option {
+	return func(dispatcher *Dispatcher) {
+		dispatcher.client = client
+	}
+}
+
+/**
+ * @brief WithRetries sets the number of attempts per delivery and the wait before the first retry.
+ *
+ * The wait doubles after each failed attempt. Zero values keep the defaults.
+ */
+func WithRetries(maxAttempts int, backoff time.Duration) Option {
+	return func(dispatcher *Dispatcher) {
+		if maxAttempts > 0 {
+			dispatcher.maxAttempts = maxAttempts
+		}
+		if backoff > 0 {
+			dispatcher.backoff = backoff
+		}
+	}
+}
+
+/**
+ * @brief WithHistory sets how many finished deliveries, dead letters included, are kept.
+ *
+ * The oldest ones are forgotten first. Zero keeps the default.
+ */
+func WithHistory(history int) Option {
+	return func(dispatcher *Dispatcher) {
+		if history > 0 {
+			dispatcher.history = history
+		}
+	}
+}
+
+/**
+ * @brief NewDispatcher returns a dispatcher making 6 attempts, 1s apart at first, and keeping 1000 finished deliveries.
+ */
+func NewDispatcher(options ...Option) *Dispatcher {
+	dispatcher := &Dispatcher{
+		client:      &http.Client{Timeout: 10 * time.Second},
+		maxAttempts: 6,
+		backoff:     time.Second,
+		history:     1000,
+	}
+	for _, option := range options {
+		option(dispatcher)
+	}
+	return dispatcher
+}
+
+// Default is the dispatcher used by Handle.
+var Default = NewDispatcher()
+
+/**
+ * @brief Handle is the event bus subscriber posting domain events through the Default dispatcher.
+ */
+func Handle(event models.DomainEvent) error {
+	return Default.Publish(event)
+}
+
+/**
+ * @brief Publish posts a domain event to every webhook subscribed to its type, in the background.
+ *
+ * The webhook event ID is derived from the domain event ID, so receivers can
+ * drop the duplicates of an event handed over more than once.
+ *
+ * @param event models.DomainEvent: The event; its data is sent as the "data" field.
+ */
+func (dispatcher *Dispatcher) Publish(event models.DomainEvent) error {
+	body, err := json.Marshal(models.WebhookEvent{
+		ID:        "evt_" + strconv.FormatInt(event.ID, 10),
+		Type:      event.Type,
+		CreatedAt: event.OccurredAt,
+		Data:      event.Data,
+	})
+	if err != nil {
+		return err
+	}
+
+	// The webhooks change under the database lock, so they are read from a copy taken under it.
+	var webhooks []models.Webhook
+	database.Transaction(func(tx *database.Tx) error {
+		webhooks = append(webhooks, database.Webhooks...)
+		return nil
+	})
+
+	for _, webhook := range webhooks {
+		if !subscribed(webhook, event.Type) {
+			continue
+		}
+
+		dispatcher.mu.Lock()
+		dispatcher.nextID++
+		d := &delivery{
+			WebhookDelivery: models.WebhookDelivery{
+				ID:        dispatcher.nextID,
+				WebhookId: webhook.ID,
+				EventId:   "evt_" + strconv.FormatInt(event.ID, 10),
+				EventType: event.Type,
+				Status:    StatusPending,
+				CreatedAt: time.Now().UTC(),
+			},
+			url:    webhook.URL,
+			secret: webhook.Secret,
+			body:   body,
+		}
+		dispatcher.deliveries = append(dispatcher.deliveries, d)
+		dispatcher.prune()
+		dispatcher.mu.Unlock()
+
+		go dispatcher.attempt(d)
+	}
+	return nil
+}
+
+/**
+ * @brief Deliveries returns the deliveries with the given status, all of them for "".
+ */
+func (dispatcher *Dispatcher) Deliveries(status string) []models.WebhookDelivery {
+	dispatcher.mu.Lock()
+	defer dispatcher.mu.Unlock()
+
+	deliveries := []models.WebhookDelivery{}
+	for _, d := range dispatcher.deliveries {
+		if status == "" || d.Status == status {
+			deliveries = append(deliveries, d.WebhookDelivery)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].ID < deliveries[j].ID })
+	return deliveries
+}
+
+/**
+ * @brief Redeliver posts a dead letter again, with a fresh retry budget.
+ *
+ * @param id int: The delivery ID.
+ * @return models.WebhookDelivery: The delivery, pending again.
+ * @return error: ErrDeliveryNotFound, ErrDeliveryPending, or ErrDeliveryNotDead for delivered ones.
+ */
+func (dispatcher *Dispatcher) Redeliver(id int) (models.WebhookDelivery, error) {
+	dispatcher.mu.Lock()
+	defer dispatcher.mu.Unlock()
+
+	for _, d := range dispatcher.deliveries {
+		if d.ID != id {
+			continue
+		}
+		if d.Status == StatusPending {
+			return d.WebhookDelivery, ErrDeliveryPending
+		}
+		if d.Status != StatusDead {
+			return d.WebhookDelivery, ErrDeliveryNotDead
+		}
+		d.Status = StatusPending
+		d.Attempts = 0
+		go dispatcher.attempt(d)
+		return d.WebhookDelivery, nil
+	}
+	return models.WebhookDelivery{}, ErrDeliveryNotFound
+}
+
+// prune forgets the oldest finished deliveries beyond the history size. The caller holds mu.
+func (dispatcher *Dispatcher) prune() {
+	finished := 0
+	for _, d := range dispatcher.deliveries {
+		if d.Status != StatusPending {
+			finished++
+		}
+	}
+
+	kept := dispatcher.deliveries[:0]
+	for _, d := range dispatcher.deliveries {
+		if d.Status != StatusPending && finished > dispatcher.history {
+			finished--
+			continue
+		}
+		kept = append(kept, d)
+	}
+	for i := len(kept); i < len(dispatcher.deliveries); i++ {
+		dispatcher.deliveries[i] = nil
+	}
+	dispatcher.deliveries = kept
+}
+
+func (dispatcher *Dispatcher) attempt(d *delivery) {
+	status, err := dispatcher.post(d)
+
+	dispatcher.mu.Lock()
+	defer dispatcher.mu.Unlock()
+
+	d.Attempts++
+	d.ResponseStatus = status
+	if err == nil {
+		d.Status = StatusDelivered
+		d.LastError = ""
+		dispatcher.prune()
+		return
+	}
+
+	d.LastError = err.Error()
+	if d.Attempts >= dispatcher.maxAttempts {
+		d.Status = StatusDead
+		dispatcher.prune()
+		return
+	}
+	time.AfterFunc(dispatcher.backoff<<(d.Attempts-1), func() { dispatcher.attempt(d) })
+}
+
+func (dispatcher *Dispatcher) post(d *delivery) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, d.url, bytes.NewReader(d.body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "go-api-webhooks")
+	req.Header.Set(EventHeader, d.EventType)
+	req.Header.Set(DeliveryHeader, strconv.Itoa(d.ID))
+	req.Header.Set(SignatureHeader, Sign(d.secret, time.Now().Unix(), d.body))
+
+	res, err := dispatcher.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return res.StatusCode, fmt.Errorf("unexpected status %d", res.StatusCode)
+	}
+	return res.StatusCode, nil
+}
+
+/**
+ * @brief Sign computes the X-Webhook-Signature header of a body.
+ *
+ * The signature is "t=<unix time>,v1=<hex HMAC-SHA256 of "<unix time>.<body>">",
+ * so receivers can reject replayed requests by their timestamp.
+ */
+func Sign(secret string, timestamp int64, body []byte) string {
+	return "t=" + strconv.FormatInt(timestamp, 10) + ",v1=" + mac(secret, timestamp, body)
+}
+
+/**
+ * @brief Verify checks an X-Webhook-Signature header, for receivers written in Go.
+ *
+ * @param tolerance time.Duration: The maximum age of the signature, and how far
+ * in the future its timestamp may be.
+ */
+func Verify(secret string, header string, body []byte, tolerance time.Duration) bool {
+	var timestamp int64
+	var signature string
+	for _, part := range strings.Split(header, ",") {
+		name, value, _ := strings.Cut(part, "=")
+		switch name {
+		case "t":
+			timestamp, _ = strconv.ParseInt(value, 10, 64)
+		case "v1":
+			signature = value
+		}
+	}
+
+	if timestamp == 0 {
+		return false
+	}
+	skew := time.Since(time.Unix(timestamp, 0))
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > tolerance {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(mac(secret, timestamp, body)))
+}
+
+/**
+ * @brief NewSecret returns a random signing secret for a new webhook.
+ */
+func NewSecret() string {
+	return "whsec_" + randomHex(32)
+}
+
+func mac(secret string, timestamp int64, body []byte) string {
+	hash := hmac.New(sha256.New, []byte(secret))
+	hash.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func subscribed(webhook models.Webhook, eventType string) bool {
+	for _, subscribed := range webhook.Events {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func randomHex(n int) string {
+	data := make([]byte, n)
+	rand.Read(data)
+	return hex.EncodeToString(data)
+}
\ No newline at end of file
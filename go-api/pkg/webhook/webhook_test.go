@@ -0,0 +1,43 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"id":"evt_1"}`)
+	now := time.Now().Unix()
+
+	assert.True(t, Verify("whsec_test", Sign("whsec_test", now, body), body, 5*time.Minute))
+	assert.False(t, Verify("whsec_other", Sign("whsec_test", now, body), body, 5*time.Minute))
+
+	// Timestamps too far in the past or in the future are rejected
+	assert.False(t, Verify("whsec_test", Sign("whsec_test", now-3600, body), body, 5*time.Minute))
+	assert.False(t, Verify("whsec_test", Sign("whsec_test", now+3600, body), body, 5*time.Minute))
+}
+
+func TestDispatcherKeepsLimitedHistory(t *testing.T) {
+	dispatcher := NewDispatcher(WithHistory(2))
+	for id := 1; id <= 4; id++ {
+		status := StatusDelivered
+		if id == 2 {
+			status = StatusPending
+		}
+		d := &delivery{}
+		d.ID, d.Status = id, status
+		dispatcher.deliveries = append(dispatcher.deliveries, d)
+	}
+
+	dispatcher.mu.Lock()
+	dispatcher.prune()
+	dispatcher.mu.Unlock()
+
+	// The oldest finished delivery is forgotten, pending ones are kept
+	var ids []int
+	for _, d := range dispatcher.Deliveries("") {
+		ids = append(ids, d.ID)
+	}
+	assert.Equal(t, []int{2, 3, 4}, ids)
+}
\ No newline at end of file